@@ -19,7 +19,7 @@ fail() {
 # Builds all of the CLI binaries
 build_cli() {
     echo -n "Building CLI binaries... "
-    docker buildx build --rm -f docker/cli.dockerfile --output build/$VERSION --target cli . || fail "Error building CLI binaries."
+    docker buildx build --rm -f docker/cli.dockerfile --build-arg RELEASE_SIGNING_KEY="$RELEASE_SIGNING_KEY" --output build/$VERSION --target cli . || fail "Error building CLI binaries."
     echo "done!"
 }
 
@@ -42,6 +42,24 @@ build_install_packages() {
 }
 
 
+# Creates the signed checksum manifest of the CLI binaries and installer packages.
+# The CLI refuses to update itself or run the installer unless they match it, and the version line binds them to this release.
+sign_release() {
+    echo -n "Signing release checksum manifest... "
+    (
+        cd build/$VERSION || exit 1
+        echo "# version: $VERSION" > checksums.txt
+        for ARTIFACT in hyperdrive-cli-* install.sh hyperdrive-install.tar.xz; do
+            if [ -f "$ARTIFACT" ]; then
+                sha256sum "$ARTIFACT" >> checksums.txt || exit 1
+            fi
+        done
+    ) || fail "Error creating release checksum manifest."
+    openssl pkeyutl -sign -inkey "$SIGNING_KEY" -rawin -in build/$VERSION/checksums.txt -out build/$VERSION/checksums.txt.sig || fail "Error signing release checksum manifest."
+    echo "done!"
+}
+
+
 # Print usage
 usage() {
     echo "Usage: build.sh [options] -v <version number>"
@@ -51,6 +69,7 @@ usage() {
     echo $'\t-c\tBuild the CLI binaries for all platforms'
     echo $'\t-t\tBuild the distro packages (.deb)'
     echo $'\t-p\tBuild the Hyperdrive installer packages'
+    echo $'\t-k\tThe Ed25519 private key (PEM) used to sign the release artifacts'
     exit 0
 }
 
@@ -60,13 +79,14 @@ usage() {
 # =================
 
 # Parse arguments
-while getopts "actpv:" FLAG; do
+while getopts "actpv:k:" FLAG; do
     case "$FLAG" in
         a) CLI=true DISTRO=true PACKAGES=true ;;
         c) CLI=true ;;
         t) DISTRO=true ;;
         p) PACKAGES=true ;;
         v) VERSION="$OPTARG" ;;
+        k) SIGNING_KEY="$OPTARG" ;;
         *) usage ;;
     esac
 done
@@ -74,6 +94,11 @@ if [ -z "$VERSION" ]; then
     usage
 fi
 
+# Embed the public half of the signing key in the CLI so it can verify updates
+if [ -n "$SIGNING_KEY" ]; then
+    RELEASE_SIGNING_KEY=$(openssl pkey -in "$SIGNING_KEY" -pubout -outform DER | base64 -w0) || fail "Error reading signing key $SIGNING_KEY."
+fi
+
 # Cleanup old artifacts
 rm -rf build/$VERSION/*
 mkdir -p build/$VERSION
@@ -89,8 +114,12 @@ if [ "$DISTRO" = true ]; then
     build_distro_packages
 fi
 if [ "$PACKAGES" = true ]; then
+    [ -n "$SIGNING_KEY" ] || fail "The installer packages must be signed; please provide the signing key with -k."
     build_install_packages
 fi
+if [ -n "$SIGNING_KEY" ]; then
+    sign_release
+fi
 
 
 # =======================
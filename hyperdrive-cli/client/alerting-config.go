@@ -0,0 +1,343 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	// The ID of the Alertmanager container, which only the CLI knows about
+	ContainerID_Alertmanager config.ContainerID = "alertmanager"
+
+	alertingConfigID       string = "alerting"
+	alertingEnableID       string = "enableAlerting"
+	alertingContainerTagID string = "containerTag"
+	alertingPortID         string = "port"
+	alertingMinPeersID     string = "minimumPeers"
+	alertingDiskUsageID    string = "maxDiskUsage"
+	alertingMinBalanceID   string = "minimumWalletBalance"
+	alertingKeyUploadID    string = "keyUploadCost"
+	alertingWebhookUrlID   string = "webhookUrl"
+	alertingSmtpServerID   string = "smtpServer"
+	alertingSmtpFromID     string = "smtpFrom"
+	alertingSmtpToID       string = "smtpTo"
+	alertingSmtpUserID     string = "smtpUsername"
+	alertingSmtpPassID     string = "smtpPassword"
+	alertingSmtpTlsID      string = "smtpRequireTls"
+
+	alertmanagerTag string = "prom/alertmanager:v0.27.0"
+)
+
+// Settings for Prometheus' alerting rules and the Alertmanager container that sends their notifications
+type AlertingConfig struct {
+	// True to run Alertmanager and load the default alerting rules
+	EnableAlerting config.Parameter[bool]
+
+	// The Alertmanager container tag
+	ContainerTag config.Parameter[string]
+
+	// The port Alertmanager's web UI is available on, from this machine only
+	Port config.Parameter[uint16]
+
+	// The peer count that the clients are considered to be poorly connected below
+	MinimumPeers config.Parameter[uint16]
+
+	// The percentage of a disk that can be used before it's considered nearly full
+	MaxDiskUsage config.Parameter[uint16]
+
+	// The node wallet balance, in ETH, that's considered too low, or 0 to use the balance the modules need
+	MinimumWalletBalance config.Parameter[float64]
+
+	// The ETH StakeWise needs in the node wallet for each validator key it uploads
+	KeyUploadCost config.Parameter[float64]
+
+	// The URL to send alerts to as generic webhooks
+	WebhookUrl config.Parameter[string]
+
+	// The SMTP server to send alert emails through, as host:port
+	SmtpServer config.Parameter[string]
+
+	// The address alert emails are sent from
+	SmtpFrom config.Parameter[string]
+
+	// The address alert emails are sent to
+	SmtpTo config.Parameter[string]
+
+	// The username for the SMTP server
+	SmtpUsername config.Parameter[string]
+
+	// The password for the SMTP server
+	SmtpPassword config.Parameter[string]
+
+	// True to require STARTTLS when sending alert emails
+	SmtpRequireTls config.Parameter[bool]
+}
+
+// Generates a new alerting config
+func NewAlertingConfig() *AlertingConfig {
+	alertingContainers := []config.ContainerID{config.ContainerID_Prometheus, ContainerID_Alertmanager}
+	return &AlertingConfig{
+		EnableAlerting: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingEnableID,
+				Name:               "Enable Alerting",
+				Description:        "Run Alertmanager alongside Prometheus and load Hyperdrive's default alerting rules, so you're notified when your clients fall out of sync, lose peers, your disk fills up, your Validator Client goes down or misses attestations, your node wallet runs low, or the Hyperdrive daemons stop responding.\n\n`hyperdrive service start` also schedules the job that collects the wallet balance and daemon metrics for these alerts.",
+				AffectsContainers:  alertingContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: false,
+			},
+		},
+
+		ContainerTag: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingContainerTagID,
+				Name:               "Alertmanager Container Tag",
+				Description:        "The tag name of the Alertmanager container you want to use on Docker Hub.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: true,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: alertmanagerTag,
+			},
+		},
+
+		Port: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingPortID,
+				Name:               "Alertmanager Port",
+				Description:        "The port Alertmanager's web UI is available on. It's only reachable from this machine.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 9093,
+			},
+		},
+
+		MinimumPeers: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingMinPeersID,
+				Name:               "Minimum Peers",
+				Description:        "Alert when your Execution Client or Beacon Node has had fewer than this many peers for 15 minutes.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 10,
+			},
+		},
+
+		MaxDiskUsage: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingDiskUsageID,
+				Name:               "Maximum Disk Usage",
+				Description:        "Alert when any of your disks is more than this percent full.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 90,
+			},
+		},
+
+		MinimumWalletBalance: config.Parameter[float64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingMinBalanceID,
+				Name:               "Minimum Wallet Balance",
+				Description:        "Alert when your node wallet has less than this much ETH. Leave this at 0 to alert when it has less than StakeWise needs to upload your validator keys, which is the Key Upload Cost for each key that hasn't been uploaded yet (and at least enough for one more).",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]float64{
+				config.Network_All: 0,
+			},
+		},
+
+		KeyUploadCost: config.Parameter[float64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingKeyUploadID,
+				Name:               "Key Upload Cost",
+				Description:        "The ETH that StakeWise requires in your node wallet for each validator key it uploads to NodeSet, which the default wallet balance alert is based on. The default of 0.01 ETH is the StakeWise module's current requirement; only change this if a StakeWise release changes it.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: true,
+			},
+			Default: map[config.Network]float64{
+				config.Network_All: 0.01,
+			},
+		},
+
+		WebhookUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingWebhookUrlID,
+				Name:               "Webhook URL",
+				Description:        "Send alerts to this URL as Alertmanager's generic webhook JSON payload. Leave this blank to not send webhooks.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpServer: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpServerID,
+				Name:               "SMTP Server",
+				Description:        "Send alerts by email through this SMTP server, written as `host:port` (such as `smtp.example.com:587`). Leave this blank to not send emails.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpFrom: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpFromID,
+				Name:               "Email From",
+				Description:        "The address to send alert emails from.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpTo: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpToID,
+				Name:               "Email To",
+				Description:        "The address to send alert emails to.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpUsername: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpUserID,
+				Name:               "SMTP Username",
+				Description:        "The username to log into the SMTP server with. Leave this blank if it doesn't need one.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpPassword: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpPassID,
+				Name:               "SMTP Password",
+				Description:        "The password to log into the SMTP server with.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SmtpRequireTls: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 alertingSmtpTlsID,
+				Name:               "Require TLS",
+				Description:        "Require the SMTP server to support STARTTLS. Only disable this for a server on your local network, such as a test server.",
+				AffectsContainers:  []config.ContainerID{ContainerID_Alertmanager},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: true,
+			},
+		},
+	}
+}
+
+// The title for the config
+func (cfg *AlertingConfig) GetTitle() string {
+	return "Alerting"
+}
+
+// Get the parameters for this config
+func (cfg *AlertingConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.EnableAlerting,
+		&cfg.ContainerTag,
+		&cfg.Port,
+		&cfg.MinimumPeers,
+		&cfg.MaxDiskUsage,
+		&cfg.MinimumWalletBalance,
+		&cfg.KeyUploadCost,
+		&cfg.WebhookUrl,
+		&cfg.SmtpServer,
+		&cfg.SmtpFrom,
+		&cfg.SmtpTo,
+		&cfg.SmtpUsername,
+		&cfg.SmtpPassword,
+		&cfg.SmtpRequireTls,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *AlertingConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
+
+// Checks to see if the alerting settings are valid; if not, returns a list of errors
+func (cfg *AlertingConfig) Validate() []string {
+	errors := []string{}
+	if !cfg.EnableAlerting.Value {
+		return errors
+	}
+	if cfg.MaxDiskUsage.Value == 0 || cfg.MaxDiskUsage.Value > 100 {
+		errors = append(errors, fmt.Sprintf("[%s - %s] must be between 1 and 100.", cfg.GetTitle(), cfg.MaxDiskUsage.Name))
+	}
+	if cfg.KeyUploadCost.Value <= 0 {
+		errors = append(errors, fmt.Sprintf("[%s - %s] must be more than 0.", cfg.GetTitle(), cfg.KeyUploadCost.Name))
+	}
+	if cfg.WebhookUrl.Value != "" {
+		webhookUrl, err := url.Parse(cfg.WebhookUrl.Value)
+		if err != nil || (webhookUrl.Scheme != "http" && webhookUrl.Scheme != "https") || webhookUrl.Host == "" {
+			errors = append(errors, fmt.Sprintf("[%s - %s] must be an http or https URL.", cfg.GetTitle(), cfg.WebhookUrl.Name))
+		}
+	}
+	if cfg.SmtpServer.Value != "" {
+		_, _, err := net.SplitHostPort(cfg.SmtpServer.Value)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("[%s - %s] must be written as host:port.", cfg.GetTitle(), cfg.SmtpServer.Name))
+		}
+		for _, param := range []*config.Parameter[string]{&cfg.SmtpFrom, &cfg.SmtpTo} {
+			_, err := mail.ParseAddress(param.Value)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("[%s - %s] must be a valid email address when sending alerts by email.", cfg.GetTitle(), param.Name))
+			}
+		}
+	}
+	return errors
+}
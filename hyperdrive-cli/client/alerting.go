@@ -0,0 +1,218 @@
+package client
+
+import (
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	swtypes "github.com/nodeset-org/hyperdrive-stakewise/shared/types"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client/template"
+	"github.com/rocket-pool/node-manager-core/eth"
+)
+
+const (
+	AlertsLogFile string = "alerts.log"
+
+	alertmanagerConfigTemplate string = "alertmanager-cfg.tmpl"
+	alertmanagerConfigTarget   string = "alertmanager.yml"
+	alertingRulesTemplate      string = "alerting-rules.tmpl"
+	alertingRulesTarget        string = "alerting-rules.yml"
+	extraAlertingRulesDir      string = "extra-alerting-rules"
+)
+
+// The state of the node that Prometheus can't scrape directly, collected for the alerting rules
+type AlertMetrics struct {
+	// True if the Hyperdrive daemon answered its API
+	DaemonUp bool
+
+	// The node wallet's balance in ETH, or nil if it couldn't be read (such as when there's no wallet)
+	WalletBalance *float64
+
+	// The ETH the enabled modules need in the node wallet to upload their pending validator keys, or nil if no module needs any
+	RequiredBalance *float64
+
+	// The Hyperdrive daemon and module containers
+	Containers []ContainerHealth
+}
+
+// Whether one of the Hyperdrive or module containers is running
+type ContainerHealth struct {
+	// The container's name within the project
+	Name string
+
+	// True if the container is running and not stuck restarting
+	Running bool
+
+	// True if Docker is waiting to restart the container after it crashed
+	Restarting bool
+
+	// How many times Docker has restarted the container
+	Restarts int
+}
+
+// Get the job that collects the alerting metrics for the configured project
+func (c *GlobalConfig) GetAlertMetricsJob(cliPath string, configPath string, jobUser *user.User) *ScheduledJob {
+	projectName := c.Hyperdrive.ProjectName.Value
+	return &ScheduledJob{
+		Name:         fmt.Sprintf("%s-alerts", projectName),
+		Generator:    "hyperdrive service alerts schedule",
+		Description:  fmt.Sprintf("Hyperdrive alerting metrics collection (%s)", projectName),
+		Args:         []string{"service", "alerts", "collect"},
+		CliPath:      cliPath,
+		ConfigPath:   configPath,
+		User:         jobUser,
+		Rootless:     c.Cli.IsRootless(),
+		OnCalendar:   "*:0/5",
+		CronSchedule: "*/5 * * * *",
+		LogFile:      AlertsLogFile,
+	}
+}
+
+// Load the Alertmanager config and alerting rules templates, do a template variable substitution, and save them
+func (c *HyperdriveClient) UpdateAlertingConfiguration(config *GlobalConfig) error {
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("error expanding config path: %w", err)
+	}
+
+	for source, target := range map[string]string{
+		alertmanagerConfigTemplate: alertmanagerConfigTarget,
+		alertingRulesTemplate:      alertingRulesTarget,
+	} {
+		t := template.Template{
+			Src: filepath.Join(templatesDir, source),
+			Dst: filepath.Join(configPath, target),
+		}
+		err = t.Write(config)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// Check on the daemons and the node wallet, and write the results to node-exporter's textfile collector for the alerting rules
+func (c *HyperdriveClient) CollectAlertMetrics(sw *StakewiseClient) (*AlertMetrics, error) {
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return nil, fmt.Errorf("settings file not found. Please run `hyperdrive service config` to set up Hyperdrive first")
+	}
+	projectName := cfg.Hyperdrive.ProjectName.Value
+	metrics := &AlertMetrics{
+		Containers: []ContainerHealth{},
+	}
+
+	// Check the daemon and the wallet
+	_, err = c.Api.Service.Version()
+	metrics.DaemonUp = (err == nil)
+	if metrics.DaemonUp {
+		response, err := c.Api.Wallet.Balance()
+		if err == nil && response.Data.Balance != nil {
+			balance := eth.WeiToEth(response.Data.Balance)
+			metrics.WalletBalance = &balance
+		}
+	}
+
+	// StakeWise needs gas for every key that hasn't been uploaded yet, and always enough for the next one
+	if cfg.Stakewise.Enabled.Value {
+		pendingKeys := 0
+		response, err := sw.Api.Status.GetValidatorStatuses()
+		if err == nil {
+			for _, state := range response.Data.States {
+				if state.NodesetStatus == swtypes.NodesetStatus_Generated {
+					pendingKeys++
+				}
+			}
+		}
+		required := float64(max(pendingKeys, 1)) * cfg.Cli.Alerting.KeyUploadCost.Value
+		metrics.RequiredBalance = &required
+	}
+
+	// Check the daemon and module containers
+	containers := []string{cfg.Hyperdrive.DaemonContainerName()}
+	for _, module := range cfg.GetAllModuleConfigs() {
+		if !module.IsEnabled() {
+			continue
+		}
+		for _, container := range module.GetContainersToDeploy() {
+			containers = append(containers, string(container))
+		}
+	}
+	for _, container := range containers {
+		health := ContainerHealth{
+			Name: container,
+		}
+		ci, err := inspectContainer(c, fmt.Sprintf("%s_%s", projectName, container))
+		if err == nil {
+			health.Running = ci.State.Running && !ci.State.Restarting
+			health.Restarting = ci.State.Restarting
+			health.Restarts = ci.RestartCount
+		}
+		metrics.Containers = append(metrics.Containers, health)
+	}
+
+	path := filepath.Join(textfileCollectorDir, fmt.Sprintf("hyperdrive_alerts_%s.prom", projectName))
+	err = writeFileAtomically(path, []byte(metrics.format(projectName, time.Now())), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error writing alerting metrics [%s]: %w", path, err)
+	}
+	return metrics, nil
+}
+
+// Format the metrics in the Prometheus text format
+func (m *AlertMetrics) format(projectName string, collected time.Time) string {
+	labels := fmt.Sprintf("project=%q", projectName)
+	daemonUp := 0
+	if m.DaemonUp {
+		daemonUp = 1
+	}
+	lines := []string{
+		"# HELP hyperdrive_alerts_last_collection_timestamp_seconds When these metrics were last collected.",
+		"# TYPE hyperdrive_alerts_last_collection_timestamp_seconds gauge",
+		fmt.Sprintf("hyperdrive_alerts_last_collection_timestamp_seconds{%s} %d", labels, collected.Unix()),
+		"# HELP hyperdrive_daemon_up 1 if the Hyperdrive daemon answered its API, 0 if it didn't.",
+		"# TYPE hyperdrive_daemon_up gauge",
+		fmt.Sprintf("hyperdrive_daemon_up{%s} %d", labels, daemonUp),
+	}
+	if m.WalletBalance != nil {
+		lines = append(lines,
+			"# HELP hyperdrive_node_wallet_balance_eth The node wallet's ETH balance.",
+			"# TYPE hyperdrive_node_wallet_balance_eth gauge",
+			fmt.Sprintf("hyperdrive_node_wallet_balance_eth{%s} %f", labels, *m.WalletBalance),
+		)
+	}
+	if m.RequiredBalance != nil {
+		lines = append(lines,
+			"# HELP hyperdrive_node_wallet_required_balance_eth The ETH the modules need in the node wallet to upload their pending validator keys.",
+			"# TYPE hyperdrive_node_wallet_required_balance_eth gauge",
+			fmt.Sprintf("hyperdrive_node_wallet_required_balance_eth{%s} %f", labels, *m.RequiredBalance),
+		)
+	}
+
+	lines = append(lines,
+		"# HELP hyperdrive_container_running 1 if the container is running, 0 if it isn't.",
+		"# TYPE hyperdrive_container_running gauge",
+	)
+	for _, container := range m.Containers {
+		running := 0
+		if container.Running {
+			running = 1
+		}
+		lines = append(lines, fmt.Sprintf("hyperdrive_container_running{%s,container=%q} %d", labels, container.Name, running))
+	}
+	lines = append(lines,
+		"# HELP hyperdrive_container_restarts How many times Docker has restarted the container.",
+		"# TYPE hyperdrive_container_restarts gauge",
+	)
+	for _, container := range m.Containers {
+		lines = append(lines, fmt.Sprintf("hyperdrive_container_restarts{%s,container=%q} %d", labels, container.Name, container.Restarts))
+	}
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
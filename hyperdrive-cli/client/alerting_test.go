@@ -0,0 +1,255 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client/template"
+	"github.com/rocket-pool/node-manager-core/config"
+	"gopkg.in/yaml.v2"
+)
+
+// The templates in the repository, relative to this package
+const testTemplatesDir string = "../../install/deploy/templates"
+
+// The parts of a Prometheus rules file the tests check
+type testAlertingRules struct {
+	Groups []struct {
+		Name  string `yaml:"name"`
+		Rules []struct {
+			Alert string `yaml:"alert"`
+			Expr  string `yaml:"expr"`
+		} `yaml:"rules"`
+	} `yaml:"groups"`
+}
+
+// The parts of an Alertmanager config the tests check
+type testAlertmanagerConfig struct {
+	Receivers []struct {
+		Name           string `yaml:"name"`
+		WebhookConfigs []struct {
+			Url string `yaml:"url"`
+		} `yaml:"webhook_configs"`
+		EmailConfigs []struct {
+			To           string `yaml:"to"`
+			Smarthost    string `yaml:"smarthost"`
+			AuthUsername string `yaml:"auth_username"`
+			RequireTls   bool   `yaml:"require_tls"`
+		} `yaml:"email_configs"`
+	} `yaml:"receivers"`
+}
+
+// Create a config with alerting enabled and local clients
+func newTestAlertingConfig(t *testing.T) *GlobalConfig {
+	t.Helper()
+	cfg := NewGlobalConfig(hdconfig.NewHyperdriveConfig(t.TempDir()))
+	cfg.Hyperdrive.ClientMode.Value = config.ClientMode_Local
+	cfg.Hyperdrive.Metrics.EnableMetrics.Value = true
+	cfg.Cli.Alerting.EnableAlerting.Value = true
+	return cfg
+}
+
+// Render one of the repository's templates with the given config and return the result
+func renderTestTemplate(t *testing.T, name string, cfg *GlobalConfig) string {
+	t.Helper()
+	dst := filepath.Join(t.TempDir(), "rendered")
+	tmpl := template.Template{
+		Src: filepath.Join(testTemplatesDir, name),
+		Dst: dst,
+	}
+	err := tmpl.Write(cfg)
+	if err != nil {
+		t.Fatalf("error rendering %s: %v", name, err)
+	}
+	contents, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("error reading rendered %s: %v", name, err)
+	}
+	return string(contents)
+}
+
+func TestAlertingRulesTemplate(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           func(cfg *GlobalConfig)
+		balanceExpr     string
+		hasVcRules      bool
+		minimumPeersExp string
+	}{
+		{
+			name:            "defaults",
+			setup:           func(cfg *GlobalConfig) {},
+			balanceExpr:     "hyperdrive_node_wallet_balance_eth < hyperdrive_node_wallet_required_balance_eth",
+			minimumPeersExp: "< 10",
+		},
+		{
+			name: "custom minimum balance and peers",
+			setup: func(cfg *GlobalConfig) {
+				cfg.Cli.Alerting.MinimumWalletBalance.Value = 0.5
+				cfg.Cli.Alerting.MinimumPeers.Value = 25
+			},
+			balanceExpr:     "hyperdrive_node_wallet_balance_eth < 0.5",
+			minimumPeersExp: "< 25",
+		},
+		{
+			name: "stakewise enabled",
+			setup: func(cfg *GlobalConfig) {
+				cfg.Stakewise.Enabled.Value = true
+			},
+			balanceExpr:     "hyperdrive_node_wallet_balance_eth < hyperdrive_node_wallet_required_balance_eth",
+			hasVcRules:      true,
+			minimumPeersExp: "< 10",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newTestAlertingConfig(t)
+			test.setup(cfg)
+			rendered := renderTestTemplate(t, alertingRulesTemplate, cfg)
+
+			var rules testAlertingRules
+			err := yaml.Unmarshal([]byte(rendered), &rules)
+			if err != nil {
+				t.Fatalf("rendered rules aren't valid YAML: %v\n%s", err, rendered)
+			}
+			exprs := map[string]string{}
+			for _, group := range rules.Groups {
+				for _, rule := range group.Rules {
+					if rule.Expr == "" {
+						t.Errorf("rule %s has no expression", rule.Alert)
+					}
+					exprs[rule.Alert] = rule.Expr
+				}
+			}
+
+			if exprs["NodeWalletBalanceLow"] != test.balanceExpr {
+				t.Errorf("expected the wallet balance expression %q, got %q", test.balanceExpr, exprs["NodeWalletBalanceLow"])
+			}
+			for _, alert := range []string{"ExecutionClientLowPeers", "BeaconNodeLowPeers"} {
+				if !strings.HasSuffix(exprs[alert], test.minimumPeersExp) {
+					t.Errorf("expected %s to end with %q, got %q", alert, test.minimumPeersExp, exprs[alert])
+				}
+			}
+			_, hasVcRules := exprs["ValidatorClientDown"]
+			if hasVcRules != test.hasVcRules {
+				t.Errorf("expected Validator Client rules to be included: %t, got %t", test.hasVcRules, hasVcRules)
+			}
+		})
+	}
+}
+
+func TestAlertingRulesTemplateClients(t *testing.T) {
+	cfg := newTestAlertingConfig(t)
+	cfg.Stakewise.Enabled.Value = true
+	for _, ec := range cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Options {
+		for _, bn := range cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Options {
+			t.Run(string(ec.Value)+"-"+string(bn.Value), func(t *testing.T) {
+				cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value = ec.Value
+				cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Value = bn.Value
+				rendered := renderTestTemplate(t, alertingRulesTemplate, cfg)
+
+				var rules testAlertingRules
+				err := yaml.Unmarshal([]byte(rendered), &rules)
+				if err != nil {
+					t.Fatalf("rendered rules aren't valid YAML: %v\n%s", err, rendered)
+				}
+				for _, group := range rules.Groups {
+					for _, rule := range group.Rules {
+						if strings.Contains(rule.Expr, "{}") || strings.Contains(rule.Expr, `job=""`) {
+							t.Errorf("rule %s has an empty selector: %s", rule.Alert, rule.Expr)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAlertmanagerConfigTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(cfg *GlobalConfig)
+		webhookUrl string
+		emailTo    string
+		emailUser  string
+	}{
+		{
+			name:  "no receivers",
+			setup: func(cfg *GlobalConfig) {},
+		},
+		{
+			name: "webhook",
+			setup: func(cfg *GlobalConfig) {
+				cfg.Cli.Alerting.WebhookUrl.Value = "https://example.com/hook?token=a:b"
+			},
+			webhookUrl: "https://example.com/hook?token=a:b",
+		},
+		{
+			name: "email without authentication",
+			setup: func(cfg *GlobalConfig) {
+				cfg.Cli.Alerting.SmtpServer.Value = "smtp.example.com:587"
+				cfg.Cli.Alerting.SmtpFrom.Value = "node@example.com"
+				cfg.Cli.Alerting.SmtpTo.Value = "me@example.com"
+			},
+			emailTo: "me@example.com",
+		},
+		{
+			name: "webhook and email with authentication",
+			setup: func(cfg *GlobalConfig) {
+				cfg.Cli.Alerting.WebhookUrl.Value = "http://localhost:8080"
+				cfg.Cli.Alerting.SmtpServer.Value = "smtp.example.com:587"
+				cfg.Cli.Alerting.SmtpFrom.Value = "node@example.com"
+				cfg.Cli.Alerting.SmtpTo.Value = "me@example.com"
+				cfg.Cli.Alerting.SmtpUsername.Value = "user"
+				cfg.Cli.Alerting.SmtpPassword.Value = `pa"ss`
+			},
+			webhookUrl: "http://localhost:8080",
+			emailTo:    "me@example.com",
+			emailUser:  "user",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newTestAlertingConfig(t)
+			test.setup(cfg)
+			rendered := renderTestTemplate(t, alertmanagerConfigTemplate, cfg)
+
+			var alertmanagerCfg testAlertmanagerConfig
+			err := yaml.Unmarshal([]byte(rendered), &alertmanagerCfg)
+			if err != nil {
+				t.Fatalf("rendered config isn't valid YAML: %v\n%s", err, rendered)
+			}
+			if len(alertmanagerCfg.Receivers) != 1 {
+				t.Fatalf("expected 1 receiver, got %d", len(alertmanagerCfg.Receivers))
+			}
+			receiver := alertmanagerCfg.Receivers[0]
+
+			if test.webhookUrl == "" && len(receiver.WebhookConfigs) > 0 {
+				t.Errorf("expected no webhooks, got %d", len(receiver.WebhookConfigs))
+			}
+			if test.webhookUrl != "" && (len(receiver.WebhookConfigs) != 1 || receiver.WebhookConfigs[0].Url != test.webhookUrl) {
+				t.Errorf("expected a webhook to %s, got %+v", test.webhookUrl, receiver.WebhookConfigs)
+			}
+			if test.emailTo == "" && len(receiver.EmailConfigs) > 0 {
+				t.Errorf("expected no emails, got %d", len(receiver.EmailConfigs))
+			}
+			if test.emailTo != "" {
+				if len(receiver.EmailConfigs) != 1 {
+					t.Fatalf("expected 1 email receiver, got %d", len(receiver.EmailConfigs))
+				}
+				email := receiver.EmailConfigs[0]
+				if email.To != test.emailTo || email.Smarthost != "smtp.example.com:587" || !email.RequireTls {
+					t.Errorf("unexpected email receiver %+v", email)
+				}
+				if email.AuthUsername != test.emailUser {
+					t.Errorf("expected auth username %q, got %q", test.emailUser, email.AuthUsername)
+				}
+			}
+		})
+	}
+}
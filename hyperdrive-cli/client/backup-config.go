@@ -0,0 +1,269 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	backupConfigID          string = "backup"
+	backupDirectoryID       string = "directory"
+	backupKeepDailyID       string = "keepDaily"
+	backupKeepWeeklyID      string = "keepWeekly"
+	backupIncludePasswordID string = "includePassword"
+	backupEnableS3ID        string = "enableS3"
+	backupS3EndpointID      string = "s3Endpoint"
+	backupS3RegionID        string = "s3Region"
+	backupS3BucketID        string = "s3Bucket"
+	backupS3PrefixID        string = "s3Prefix"
+	backupS3AccessKeyID     string = "s3AccessKey"
+	backupS3SecretKeyID     string = "s3SecretKey"
+)
+
+// Settings for the backups made by `hyperdrive service backup run`
+type BackupConfig struct {
+	// The local or mounted directory to save backups to
+	Directory config.Parameter[string]
+
+	// The number of days to keep the newest backup of
+	KeepDaily config.Parameter[uint16]
+
+	// The number of weeks to keep the newest backup of
+	KeepWeekly config.Parameter[uint16]
+
+	// True to include the saved node wallet password in backups
+	IncludePassword config.Parameter[bool]
+
+	// True to upload backups to S3-compatible storage
+	EnableS3 config.Parameter[bool]
+
+	// The URL of the S3-compatible endpoint
+	S3Endpoint config.Parameter[string]
+
+	// The region of the bucket
+	S3Region config.Parameter[string]
+
+	// The bucket to upload backups to
+	S3Bucket config.Parameter[string]
+
+	// The prefix to put in front of backup names in the bucket
+	S3Prefix config.Parameter[string]
+
+	// The access key for the bucket
+	S3AccessKey config.Parameter[string]
+
+	// The secret key for the bucket
+	S3SecretKey config.Parameter[string]
+}
+
+// Generates a new backup config
+func NewBackupConfig() *BackupConfig {
+	return &BackupConfig{
+		Directory: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupDirectoryID,
+				Name:               "Backup Directory",
+				Description:        "The directory to save scheduled backups to, such as a mounted network share or external drive. Leave this blank to not save backups locally.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		KeepDaily: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupKeepDailyID,
+				Name:               "Daily Backups to Keep",
+				Description:        "The number of days to keep a backup for. The newest backup of each day is kept until it's older than this many days' worth of backups.\n\nIf this and the weekly count are both 0, old backups are never deleted.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 7,
+			},
+		},
+
+		KeepWeekly: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupKeepWeeklyID,
+				Name:               "Weekly Backups to Keep",
+				Description:        "The number of weeks to keep a backup for. The newest backup of each week is kept until it's older than this many weeks' worth of backups.\n\nIf this and the daily count are both 0, old backups are never deleted.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 4,
+			},
+		},
+
+		IncludePassword: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupIncludePasswordID,
+				Name:               "Include Wallet Password",
+				Description:        "Include your saved node wallet password in scheduled backups, so the wallet loads automatically after one is restored.\n\nAnyone with a backup and its passphrase will be able to use your node wallet either way, but without the password they'd also need to know it.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: false,
+			},
+		},
+
+		EnableS3: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupEnableS3ID,
+				Name:               "Upload to S3",
+				Description:        "Upload scheduled backups to Amazon S3 or any S3-compatible storage, such as MinIO, Backblaze B2, or Cloudflare R2.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: false,
+			},
+		},
+
+		S3Endpoint: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3EndpointID,
+				Name:               "S3 Endpoint",
+				Description:        "The URL of the S3-compatible endpoint, such as `https://s3.us-east-1.amazonaws.com` or `http://192.168.1.10:9000` for a MinIO server. Buckets are addressed by path, so the bucket name shouldn't be part of this URL.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		S3Region: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3RegionID,
+				Name:               "S3 Region",
+				Description:        "The region the bucket is in. Most S3-compatible services other than Amazon accept the default.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "us-east-1",
+			},
+		},
+
+		S3Bucket: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3BucketID,
+				Name:               "S3 Bucket",
+				Description:        "The name of the bucket to upload backups to. It must already exist.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		S3Prefix: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3PrefixID,
+				Name:               "S3 Prefix",
+				Description:        "The folder within the bucket to upload backups to, such as `hyperdrive/`. Leave this blank to upload them to the root of the bucket.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		S3AccessKey: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3AccessKeyID,
+				Name:               "S3 Access Key",
+				Description:        "The access key ID for the bucket. It only needs permission to list, upload, and delete objects in it.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		S3SecretKey: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 backupS3SecretKeyID,
+				Name:               "S3 Secret Key",
+				Description:        "The secret access key for the bucket.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+	}
+}
+
+// The title for the config
+func (cfg *BackupConfig) GetTitle() string {
+	return "Backups"
+}
+
+// Get the parameters for this config
+func (cfg *BackupConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.Directory,
+		&cfg.KeepDaily,
+		&cfg.KeepWeekly,
+		&cfg.IncludePassword,
+		&cfg.EnableS3,
+		&cfg.S3Endpoint,
+		&cfg.S3Region,
+		&cfg.S3Bucket,
+		&cfg.S3Prefix,
+		&cfg.S3AccessKey,
+		&cfg.S3SecretKey,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *BackupConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
+
+// Get the destinations that scheduled backups are sent to
+func (cfg *BackupConfig) GetTargets() []BackupTarget {
+	targets := []BackupTarget{}
+	if cfg.Directory.Value != "" {
+		targets = append(targets, NewLocalBackupTarget(cfg.Directory.Value))
+	}
+	if cfg.EnableS3.Value {
+		targets = append(targets, NewS3BackupTarget(cfg.S3Endpoint.Value, cfg.S3Region.Value, cfg.S3Bucket.Value, cfg.S3Prefix.Value, cfg.S3AccessKey.Value, cfg.S3SecretKey.Value))
+	}
+	return targets
+}
+
+// Checks to see if the backup settings are valid; if not, returns a list of errors
+func (cfg *BackupConfig) Validate() []string {
+	errors := []string{}
+	if cfg.EnableS3.Value {
+		for _, param := range []*config.Parameter[string]{&cfg.S3Endpoint, &cfg.S3Region, &cfg.S3Bucket, &cfg.S3AccessKey, &cfg.S3SecretKey} {
+			if param.Value == "" {
+				errors = append(errors, fmt.Sprintf("[%s - %s] cannot be blank when uploading backups to S3.", cfg.GetTitle(), param.Name))
+			}
+		}
+	}
+	return errors
+}
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	BackupPassphraseFile string = "backup-passphrase"
+	BackupStatusFile     string = "backup-status.json"
+	BackupLogFile        string = "backup.log"
+)
+
+// How often scheduled backups run
+type BackupSchedule string
+
+const (
+	BackupSchedule_Hourly BackupSchedule = "hourly"
+	BackupSchedule_Daily  BackupSchedule = "daily"
+	BackupSchedule_Weekly BackupSchedule = "weekly"
+)
+
+// The settings used to generate the scheduled backup job
+type BackupScheduleOptions struct {
+	// The path of the Hyperdrive CLI binary
+	CliPath string
+
+	// The Hyperdrive config directory
+	ConfigPath string
+
+	// The user that runs the job; rootful runtimes need root to read the data folder unattended
+	User *user.User
+
+	// How often to run it
+	Schedule BackupSchedule
+}
+
+// The outcome of the most recent scheduled backup
+type BackupStatus struct {
+	// When the last backup was attempted
+	LastAttempt time.Time `json:"lastAttempt"`
+
+	// When the last backup succeeded, or the zero time if none have
+	LastSuccess time.Time `json:"lastSuccess"`
+
+	// The name of the last backup that succeeded
+	LastBackup string `json:"lastBackup"`
+
+	// The error from the last attempt, or empty if it succeeded
+	LastError string `json:"lastError"`
+}
+
+// Get the job that runs scheduled backups for the configured project
+func (c *GlobalConfig) GetBackupJob(opts BackupScheduleOptions) *ScheduledJob {
+	projectName := c.Hyperdrive.ProjectName.Value
+	return &ScheduledJob{
+		Name:            fmt.Sprintf("%s-backup", projectName),
+		Generator:       "hyperdrive service backup schedule",
+		Description:     fmt.Sprintf("Hyperdrive node backup (%s)", projectName),
+		Args:            []string{"service", "backup", "run"},
+		CliPath:         opts.CliPath,
+		ConfigPath:      opts.ConfigPath,
+		User:            opts.User,
+		Rootless:        c.Cli.IsRootless(),
+		OnCalendar:      string(opts.Schedule),
+		RandomizedDelay: "10min",
+		CronSchedule:    "@" + string(opts.Schedule),
+		LogFile:         BackupLogFile,
+	}
+}
+
+// Save the passphrase that scheduled backups are encrypted with, readable only by its owner
+func (c *HyperdriveClient) SaveBackupPassphrase(passphrase string) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, BackupPassphraseFile))
+	if err != nil {
+		return fmt.Errorf("error expanding backup passphrase path: %w", err)
+	}
+	err = os.WriteFile(path, []byte(passphrase), 0600)
+	if err != nil {
+		return fmt.Errorf("error saving backup passphrase [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Load the passphrase that scheduled backups are encrypted with
+func (c *HyperdriveClient) LoadBackupPassphrase() (string, error) {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, BackupPassphraseFile))
+	if err != nil {
+		return "", fmt.Errorf("error expanding backup passphrase path: %w", err)
+	}
+	passphrase, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no backup passphrase has been saved; run `hyperdrive service backup schedule` to set one")
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading backup passphrase [%s]: %w", path, err)
+	}
+	return string(passphrase), nil
+}
+
+// Create a backup with the saved passphrase, send it to each of the configured destinations, and delete the ones that have expired.
+// The outcome is recorded for `hyperdrive service status` and Prometheus.
+func (c *HyperdriveClient) RunScheduledBackup() error {
+	err := c.runScheduledBackupImpl()
+	recordErr := c.recordBackupResult(err)
+	if err != nil {
+		return err
+	}
+	return recordErr
+}
+
+// Load the outcome of the most recent scheduled backup, or nil if there hasn't been one
+func (c *HyperdriveClient) LoadBackupStatus() (*BackupStatus, error) {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, BackupStatusFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding backup status path: %w", err)
+	}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup status [%s]: %w", path, err)
+	}
+	var status BackupStatus
+	err = json.Unmarshal(bytes, &status)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing backup status: %w", err)
+	}
+	return &status, nil
+}
+
+// Create the backup and send it to the destinations
+func (c *HyperdriveClient) runScheduledBackupImpl() error {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	backupCfg := cfg.Cli.Backup
+	targets := backupCfg.GetTargets()
+	if len(targets) == 0 {
+		return fmt.Errorf("no backup destinations are configured; set them in the CLI section of `hyperdrive service config`")
+	}
+	passphrase, err := c.LoadBackupPassphrase()
+	if err != nil {
+		return err
+	}
+
+	// Create it in a private temp dir
+	tmpDir, err := os.MkdirTemp("", "hyperdrive-backup-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary backup folder: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	name := cfg.GetDefaultBackupFilename()
+	path := filepath.Join(tmpDir, name)
+	_, err = c.CreateBackup(path, passphrase, backupCfg.IncludePassword.Value)
+	if err != nil {
+		return err
+	}
+
+	// Send it everywhere even if one of the destinations fails
+	failures := []string{}
+	for _, target := range targets {
+		fmt.Printf("Sending %s to %s...\n", name, target.GetName())
+		err = target.Upload(name, path)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		names, err := target.List()
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		for _, expired := range GetExpiredBackups(names, cfg.Hyperdrive.ProjectName.Value, string(cfg.Hyperdrive.Network.Value), int(backupCfg.KeepDaily.Value), int(backupCfg.KeepWeekly.Value)) {
+			fmt.Printf("Deleting expired backup %s...\n", expired)
+			err = target.Delete(expired)
+			if err != nil {
+				failures = append(failures, err.Error())
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("backup %s failed for some destinations:\n%s", name, strings.Join(failures, "\n"))
+	}
+	fmt.Printf("Backup %s complete.\n", name)
+	return c.updateLastBackup(name)
+}
+
+// Record the name of the backup that just succeeded
+func (c *HyperdriveClient) updateLastBackup(name string) error {
+	status, err := c.LoadBackupStatus()
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		status = &BackupStatus{}
+	}
+	status.LastBackup = name
+	status.LastSuccess = time.Now()
+	return c.saveBackupStatus(status)
+}
+
+// Record the outcome of a scheduled backup in the status file and the node-exporter metrics
+func (c *HyperdriveClient) recordBackupResult(backupErr error) error {
+	status, err := c.LoadBackupStatus()
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		status = &BackupStatus{}
+	}
+	status.LastAttempt = time.Now()
+	status.LastError = ""
+	if backupErr != nil {
+		status.LastError = backupErr.Error()
+	}
+	err = c.saveBackupStatus(status)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if !cfg.Hyperdrive.Metrics.EnableMetrics.Value {
+		return nil
+	}
+	err = writeBackupMetrics(cfg.Hyperdrive.ProjectName.Value, status)
+	if err != nil {
+		// The backup itself is more important than its metrics
+		fmt.Printf("WARNING: couldn't write backup metrics: %s\n", err.Error())
+	}
+	return nil
+}
+
+// Save the backup status, replacing it atomically so it can be updated by both root and the user
+func (c *HyperdriveClient) saveBackupStatus(status *BackupStatus) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, BackupStatusFile))
+	if err != nil {
+		return fmt.Errorf("error expanding backup status path: %w", err)
+	}
+	bytes, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing backup status: %w", err)
+	}
+	err = writeFileAtomically(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error saving backup status [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Write the backup status in the Prometheus text format for node-exporter's textfile collector
+func writeBackupMetrics(projectName string, status *BackupStatus) error {
+	lastSuccess := int64(0)
+	if !status.LastSuccess.IsZero() {
+		lastSuccess = status.LastSuccess.Unix()
+	}
+	succeeded := 0
+	if status.LastError == "" {
+		succeeded = 1
+	}
+	labels := fmt.Sprintf("{project=%q}", projectName)
+	metrics := strings.Join([]string{
+		"# HELP hyperdrive_backup_last_attempt_timestamp_seconds When the last scheduled backup ran.",
+		"# TYPE hyperdrive_backup_last_attempt_timestamp_seconds gauge",
+		fmt.Sprintf("hyperdrive_backup_last_attempt_timestamp_seconds%s %d", labels, status.LastAttempt.Unix()),
+		"# HELP hyperdrive_backup_last_success_timestamp_seconds When the last scheduled backup succeeded, or 0 if none have.",
+		"# TYPE hyperdrive_backup_last_success_timestamp_seconds gauge",
+		fmt.Sprintf("hyperdrive_backup_last_success_timestamp_seconds%s %d", labels, lastSuccess),
+		"# HELP hyperdrive_backup_last_attempt_success 1 if the last scheduled backup succeeded, 0 if it failed.",
+		"# TYPE hyperdrive_backup_last_attempt_success gauge",
+		fmt.Sprintf("hyperdrive_backup_last_attempt_success%s %d", labels, succeeded),
+		"",
+	}, "\n")
+
+	path := filepath.Join(textfileCollectorDir, fmt.Sprintf("hyperdrive_backup_%s.prom", projectName))
+	return writeFileAtomically(path, []byte(metrics), 0644)
+}
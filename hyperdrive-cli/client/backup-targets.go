@@ -0,0 +1,399 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	backupTimeFormat string = "20060102-150405"
+
+	s3Service       string        = "s3"
+	s3Algorithm     string        = "AWS4-HMAC-SHA256"
+	s3DateFormat    string        = "20060102"
+	s3TimeFormat    string        = "20060102T150405Z"
+	s3SignedHeaders string        = "host;x-amz-content-sha256;x-amz-date"
+	s3Timeout       time.Duration = 10 * time.Minute
+)
+
+// A destination that scheduled backups are sent to
+type BackupTarget interface {
+	// A description of the destination for logging
+	GetName() string
+
+	// Get the names of the files in the destination
+	List() ([]string, error)
+
+	// Copy a backup file to the destination
+	Upload(name string, path string) error
+
+	// Delete a file from the destination
+	Delete(name string) error
+}
+
+// Get the names of the backups that fall outside of the retention rules.
+// The newest backup of each of the most recent keepDaily days and keepWeekly weeks is kept.
+// Only backups for the given project and network are considered, and nothing expires if both counts are 0.
+func GetExpiredBackups(names []string, projectName string, network string, keepDaily int, keepWeekly int) []string {
+	if keepDaily == 0 && keepWeekly == 0 {
+		return []string{}
+	}
+
+	// Get the backups that belong to this node, newest first
+	prefix := fmt.Sprintf("%s-%s-", projectName, network)
+	times := map[string]time.Time{}
+	backups := []string{}
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, BackupExtension) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), BackupExtension)
+		created, err := time.ParseInLocation(backupTimeFormat, timestamp, time.Local)
+		if err != nil {
+			continue
+		}
+		times[name] = created
+		backups = append(backups, name)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return times[backups[i]].After(times[backups[j]])
+	})
+
+	// Keep the newest one of each day and week until there are enough of them
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	expired := []string{}
+	for _, name := range backups {
+		keep := false
+		day := times[name].Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep = true
+		}
+		year, weekNumber := times[name].ISOWeek()
+		week := fmt.Sprintf("%d-%d", year, weekNumber)
+		if !weeks[week] && len(weeks) < keepWeekly {
+			weeks[week] = true
+			keep = true
+		}
+		if !keep {
+			expired = append(expired, name)
+		}
+	}
+	return expired
+}
+
+// ==================
+// === Local Disk ===
+// ==================
+
+// A local or mounted directory
+type LocalBackupTarget struct {
+	dir string
+}
+
+// Create a new local directory destination
+func NewLocalBackupTarget(dir string) *LocalBackupTarget {
+	return &LocalBackupTarget{
+		dir: dir,
+	}
+}
+
+// A description of the destination for logging
+func (t *LocalBackupTarget) GetName() string {
+	return t.dir
+}
+
+// Get the names of the files in the directory
+func (t *LocalBackupTarget) List() ([]string, error) {
+	dir, err := homedir.Expand(t.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding backup directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup directory [%s]: %w", dir, err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Copy a backup file into the directory
+func (t *LocalBackupTarget) Upload(name string, path string) error {
+	dir, err := homedir.Expand(t.dir)
+	if err != nil {
+		return fmt.Errorf("error expanding backup directory: %w", err)
+	}
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("error creating backup directory [%s]: %w", dir, err)
+	}
+
+	// Write it under a temporary name first so a partial copy never looks like a backup
+	dest := filepath.Join(dir, name)
+	err = copyFile(path, dest+".tmp")
+	if err != nil {
+		return fmt.Errorf("error copying backup to [%s]: %w", dir, err)
+	}
+	err = os.Rename(dest+".tmp", dest)
+	if err != nil {
+		return fmt.Errorf("error saving backup to [%s]: %w", dest, err)
+	}
+	return nil
+}
+
+// Delete a file from the directory
+func (t *LocalBackupTarget) Delete(name string) error {
+	dir, err := homedir.Expand(t.dir)
+	if err != nil {
+		return fmt.Errorf("error expanding backup directory: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("error deleting [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// ==========
+// === S3 ===
+// ==========
+
+// A bucket on Amazon S3 or an S3-compatible service, addressed by path and authenticated with Signature Version 4
+type S3BackupTarget struct {
+	endpoint  string
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// The response to a ListObjectsV2 request
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// The response to a failed request
+type s3ErrorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// Create a new S3 destination
+func NewS3BackupTarget(endpoint string, region string, bucket string, prefix string, accessKey string, secretKey string) *S3BackupTarget {
+	return &S3BackupTarget{
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		prefix:    prefix,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client: &http.Client{
+			Timeout: s3Timeout,
+		},
+	}
+}
+
+// A description of the destination for logging
+func (t *S3BackupTarget) GetName() string {
+	return fmt.Sprintf("s3://%s/%s (%s)", t.bucket, t.prefix, t.endpoint)
+}
+
+// Get the names of the objects under the prefix
+func (t *S3BackupTarget) List() ([]string, error) {
+	names := []string{}
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", t.prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		body, err := t.send(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error listing bucket [%s]: %w", t.bucket, err)
+		}
+
+		var result s3ListResult
+		err = xml.Unmarshal(body, &result)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing bucket listing: %w", err)
+		}
+		for _, object := range result.Contents {
+			name := strings.TrimPrefix(object.Key, t.prefix)
+			if !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Upload a backup file to the bucket
+func (t *S3BackupTarget) Upload(name string, path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading backup [%s]: %w", path, err)
+	}
+	_, err = t.send(http.MethodPut, t.prefix+name, nil, contents)
+	if err != nil {
+		return fmt.Errorf("error uploading [%s] to bucket [%s]: %w", name, t.bucket, err)
+	}
+	return nil
+}
+
+// Delete an object from the bucket
+func (t *S3BackupTarget) Delete(name string) error {
+	_, err := t.send(http.MethodDelete, t.prefix+name, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting [%s] from bucket [%s]: %w", name, t.bucket, err)
+	}
+	return nil
+}
+
+// Send a signed request for an object in the bucket (or the bucket itself if the key is empty) and return the response body
+func (t *S3BackupTarget) send(method string, key string, query url.Values, body []byte) ([]byte, error) {
+	endpoint, err := url.Parse(t.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint [%s]: %w", t.endpoint, err)
+	}
+	canonicalPath := endpoint.Path + "/" + s3Escape(t.bucket, true)
+	if key != "" {
+		canonicalPath += "/" + s3Escape(key, false)
+	}
+	canonicalQuery := s3CanonicalQuery(query)
+	requestUrl := fmt.Sprintf("%s://%s%s", endpoint.Scheme, endpoint.Host, canonicalPath)
+	if canonicalQuery != "" {
+		requestUrl += "?" + canonicalQuery
+	}
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.ContentLength = int64(len(body))
+
+	// Sign it
+	now := time.Now().UTC()
+	payloadHash := sha256Hex(body)
+	request.Header.Set("x-amz-content-sha256", payloadHash)
+	request.Header.Set("x-amz-date", now.Format(s3TimeFormat))
+	request.Header.Set("Authorization", t.getAuthorization(method, endpoint.Host, canonicalPath, canonicalQuery, payloadHash, now))
+
+	// Send it
+	response, err := t.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var s3Error s3ErrorResponse
+		if xml.Unmarshal(responseBody, &s3Error) == nil && s3Error.Code != "" {
+			return nil, fmt.Errorf("%s: %s (%s)", response.Status, s3Error.Message, s3Error.Code)
+		}
+		return nil, fmt.Errorf("%s", response.Status)
+	}
+	return responseBody, nil
+}
+
+// Get the Signature Version 4 authorization header for a request
+func (t *S3BackupTarget) getAuthorization(method string, host string, canonicalPath string, canonicalQuery string, payloadHash string, now time.Time) string {
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalPath,
+		canonicalQuery,
+		fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", host, payloadHash, amzDate),
+		s3SignedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, t.region, s3Service)
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := []byte("AWS4" + t.secretKey)
+	for _, part := range []string{date, t.region, s3Service, "aws4_request"} {
+		signingKey = hmacSha256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3Algorithm, t.accessKey, scope, s3SignedHeaders, signature)
+}
+
+// Build the canonical query string for a Signature Version 4 request
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// URI-encode a string the way Signature Version 4 expects, which leaves only unreserved characters (and optionally slashes) alone
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+// Get the hex-encoded SHA-256 hash of some data
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Get the HMAC-SHA256 of a message
+func hmacSha256(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,518 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/nodeset-org/hyperdrive-daemon/shared/config/ids"
+	"github.com/rocket-pool/node-manager-core/config"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	BackupExtension string = ".hdbak"
+
+	backupMagic        string = "HDBACKUP1\n"
+	backupManifestName string = "backup.json"
+	backupDataName     string = "data.tar"
+	backupSaltLength   int    = 32
+	backupKeyLength    int    = 32
+
+	// scrypt parameters; these take about a second on a typical node, which makes brute-forcing a stolen backup expensive
+	backupScryptN int = 1 << 18
+	backupScryptR int = 8
+	backupScryptP int = 1
+)
+
+// Describes the contents of a node backup
+type BackupManifest struct {
+	// The Hyperdrive version that created the backup
+	Version string `json:"version"`
+
+	// The project name of the backed up node
+	ProjectName string `json:"projectName"`
+
+	// The network the backed up node was configured for
+	Network config.Network `json:"network"`
+
+	// The address of the node wallet, or the zero address if there wasn't one
+	NodeAddress common.Address `json:"nodeAddress"`
+
+	// True if the saved node wallet password is included
+	IncludesPassword bool `json:"includesPassword"`
+
+	// When the backup was created
+	Created time.Time `json:"created"`
+}
+
+// A decrypted node backup
+type Backup struct {
+	// The backup's manifest
+	Manifest *BackupManifest
+
+	// The contents of the user settings file
+	Settings []byte
+
+	// The override files, by path relative to the override folder
+	Overrides map[string][]byte
+
+	// A tar archive of the user data folder
+	Data []byte
+}
+
+// Get the default filename for a backup of the node
+func (c *GlobalConfig) GetDefaultBackupFilename() string {
+	return fmt.Sprintf("%s-%s-%s%s", c.Hyperdrive.ProjectName.Value, c.Hyperdrive.Network.Value, time.Now().Format(backupTimeFormat), BackupExtension)
+}
+
+// Create an encrypted backup of the node's wallet, validator keys, slashing protection databases, settings, and overrides
+func (c *HyperdriveClient) CreateBackup(outputPath string, passphrase string, includePassword bool) (*BackupManifest, error) {
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return nil, fmt.Errorf("no configuration detected, there's nothing to back up")
+	}
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding config path: %w", err)
+	}
+	dataPath, err := homedir.Expand(cfg.Hyperdrive.UserDataPath.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding data path: %w", err)
+	}
+
+	// Get the user data; it's written by the containers, so it has to be read with their privileges
+	rootCmd, err := c.getRuntimeEscalationCommand()
+	if err != nil {
+		return nil, fmt.Errorf("could not get privilege escalation command: %w", err)
+	}
+	exclude := ""
+	if !includePassword {
+		exclude = fmt.Sprintf("--exclude=%s ", shellescape.Quote("./"+hdconfig.UserPasswordFilename))
+	}
+	fmt.Printf("Reading %s...\n", dataPath)
+	data, err := c.readOutput(fmt.Sprintf("%s tar -C %s -cf - %s.", rootCmd, shellescape.Quote(dataPath), exclude))
+	if err != nil {
+		return nil, fmt.Errorf("error reading data folder [%s]: %w", dataPath, err)
+	}
+	nodeAddress, err := getBackupNodeAddress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the settings and overrides
+	settings, err := os.ReadFile(filepath.Join(configPath, SettingsFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading user settings: %w", err)
+	}
+	overrides := map[string][]byte{}
+	overridePath := filepath.Join(configPath, overrideDir)
+	err = filepath.WalkDir(overridePath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(overridePath, filePath)
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		overrides[filepath.ToSlash(relPath)] = contents
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading overrides: %w", err)
+	}
+
+	// Build the archive
+	manifest := &BackupManifest{
+		Version:          shared.HyperdriveVersion,
+		ProjectName:      cfg.Hyperdrive.ProjectName.Value,
+		Network:          cfg.Hyperdrive.Network.Value,
+		NodeAddress:      nodeAddress,
+		IncludesPassword: includePassword,
+		Created:          time.Now(),
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing backup manifest: %w", err)
+	}
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	entries := map[string][]byte{
+		backupManifestName: manifestBytes,
+		SettingsFile:       settings,
+		backupDataName:     data,
+	}
+	for name, contents := range overrides {
+		entries[path.Join(overrideDir, name)] = contents
+	}
+	for name, contents := range entries {
+		err = writeBundleEntry(writer, name, int64(len(contents)), bytes.NewReader(contents))
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error finalizing backup archive: %w", err)
+	}
+
+	// Encrypt it
+	fmt.Println("Encrypting backup...")
+	salt := make([]byte, backupSaltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, fmt.Errorf("error generating backup salt: %w", err)
+	}
+	aead, err := getBackupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("error generating backup nonce: %w", err)
+	}
+	header := append([]byte(backupMagic), salt...)
+	header = append(header, nonce...)
+	ciphertext := aead.Seal(nil, nonce, archive.Bytes(), header)
+
+	// Only the owner should be able to read it, even though it's encrypted
+	err = os.WriteFile(outputPath, append(header, ciphertext...), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error writing backup [%s]: %w", outputPath, err)
+	}
+	return manifest, nil
+}
+
+// Decrypt a node backup and check that it's consistent
+func OpenBackup(backupPath string, passphrase string) (*Backup, error) {
+	contents, err := os.ReadFile(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup [%s]: %w", backupPath, err)
+	}
+	if !bytes.HasPrefix(contents, []byte(backupMagic)) {
+		return nil, fmt.Errorf("[%s] is not a Hyperdrive backup", backupPath)
+	}
+
+	// Decrypt it
+	saltStart := len(backupMagic)
+	nonceStart := saltStart + backupSaltLength
+	if len(contents) < nonceStart {
+		return nil, fmt.Errorf("backup [%s] is truncated", backupPath)
+	}
+	aead, err := getBackupCipher(passphrase, contents[saltStart:nonceStart])
+	if err != nil {
+		return nil, err
+	}
+	dataStart := nonceStart + aead.NonceSize()
+	if len(contents) < dataStart {
+		return nil, fmt.Errorf("backup [%s] is truncated", backupPath)
+	}
+	archive, err := aead.Open(nil, contents[nonceStart:dataStart], contents[dataStart:], contents[:dataStart])
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting backup [%s]; either the passphrase is incorrect or the file is corrupted", backupPath)
+	}
+
+	// Read the entries
+	backup := &Backup{
+		Overrides: map[string][]byte{},
+	}
+	var manifestBytes []byte
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading backup [%s]: %w", backupPath, err)
+		}
+		entry, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading [%s] from backup: %w", header.Name, err)
+		}
+
+		name := path.Clean(header.Name)
+		switch {
+		case name == backupManifestName:
+			manifestBytes = entry
+		case name == SettingsFile:
+			backup.Settings = entry
+		case name == backupDataName:
+			backup.Data = entry
+		case strings.HasPrefix(name, overrideDir+"/") && !strings.Contains(name, ".."):
+			backup.Overrides[strings.TrimPrefix(name, overrideDir+"/")] = entry
+		default:
+			return nil, fmt.Errorf("backup [%s] has an unexpected entry [%s]", backupPath, header.Name)
+		}
+	}
+	if manifestBytes == nil || backup.Settings == nil || backup.Data == nil {
+		return nil, fmt.Errorf("backup [%s] is incomplete", backupPath)
+	}
+	var manifest BackupManifest
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing backup manifest: %w", err)
+	}
+	backup.Manifest = &manifest
+
+	// Make sure the wallet in the backup is the one the manifest describes
+	nodeAddress, err := getBackupNodeAddress(backup.Data)
+	if err != nil {
+		return nil, err
+	}
+	if nodeAddress != manifest.NodeAddress {
+		return nil, fmt.Errorf("backup [%s] is for node %s, but its wallet is for %s", backupPath, manifest.NodeAddress.Hex(), nodeAddress.Hex())
+	}
+	return backup, nil
+}
+
+// Get the address of the node wallet that's currently on disk, or the zero address if there isn't one
+func (c *HyperdriveClient) GetNodeWalletAddressOnDisk() (common.Address, error) {
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return common.Address{}, nil
+	}
+	addressPath, err := homedir.Expand(cfg.Hyperdrive.GetNodeAddressFilePath())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error expanding node address path: %w", err)
+	}
+
+	rootCmd, err := c.getRuntimeEscalationCommand()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("could not get privilege escalation command: %w", err)
+	}
+	output, err := c.readOutput(fmt.Sprintf("%s cat %s", rootCmd, shellescape.Quote(addressPath)))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// There's no wallet yet
+			return common.Address{}, nil
+		}
+		return common.Address{}, fmt.Errorf("error reading node address [%s]: %w", addressPath, err)
+	}
+	return common.HexToAddress(strings.TrimSpace(string(output))), nil
+}
+
+// Restore a node backup, replacing the user settings, overrides, and the user data folder.
+// The data is extracted next to the existing folder and then swapped in, so none of the old keys are left next to the restored ones.
+// Returns the path the replaced data folder was moved to, or an empty string if there wasn't one. The service should be stopped first.
+func (c *HyperdriveClient) RestoreBackup(backup *Backup) (string, error) {
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return "", fmt.Errorf("error expanding config path: %w", err)
+	}
+
+	// Restore the settings and overrides
+	err = os.MkdirAll(configPath, 0755)
+	if err != nil {
+		return "", fmt.Errorf("error creating config folder [%s]: %w", configPath, err)
+	}
+	settings, err := relocateSettings(backup.Settings, configPath)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(configPath, SettingsFile), settings, 0644)
+	if err != nil {
+		return "", fmt.Errorf("error restoring user settings: %w", err)
+	}
+	userOverrideDir := filepath.Join(configPath, overrideDir)
+	err = os.RemoveAll(userOverrideDir)
+	if err != nil {
+		return "", fmt.Errorf("error removing overrides [%s]: %w", userOverrideDir, err)
+	}
+	for name, contents := range backup.Overrides {
+		overridePath := filepath.Join(userOverrideDir, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(overridePath), 0755)
+		if err != nil {
+			return "", fmt.Errorf("error creating override folder for [%s]: %w", name, err)
+		}
+		err = os.WriteFile(overridePath, contents, 0644)
+		if err != nil {
+			return "", fmt.Errorf("error restoring override [%s]: %w", name, err)
+		}
+	}
+
+	// Reload the restored settings so the data goes where they say it should
+	c.cfg = nil
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading restored user settings: %w", err)
+	}
+	dataPath, err := homedir.Expand(cfg.Hyperdrive.UserDataPath.Value)
+	if err != nil {
+		return "", fmt.Errorf("error expanding data path: %w", err)
+	}
+	dataPath = filepath.Clean(dataPath)
+
+	// Extract the user data with the containers' privileges into a folder next to the current one, so it's on the same filesystem
+	rootCmd, err := c.getRuntimeEscalationCommand()
+	if err != nil {
+		return "", fmt.Errorf("could not get privilege escalation command: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	stagingPath := fmt.Sprintf("%s.restore-%d", dataPath, timestamp)
+	quotedStagingPath := shellescape.Quote(stagingPath)
+	fmt.Printf("Extracting the backup's data to %s...\n", stagingPath)
+	cmd := c.newCommand(fmt.Sprintf("%[1]s mkdir -p %[2]s && %[1]s tar -C %[2]s -xf -", rootCmd, quotedStagingPath))
+	cmd.SetStdin(bytes.NewReader(backup.Data))
+	cmd.SetStdout(os.Stdout)
+	cmd.SetStderr(os.Stderr)
+	err = cmd.Run()
+	if err != nil {
+		_ = c.printOutput(fmt.Sprintf("%s rm -rf %s", rootCmd, quotedStagingPath))
+		return "", fmt.Errorf("error extracting the backup's data to [%s]: %w", stagingPath, err)
+	}
+
+	// Move the current data folder out of the way, keeping it in case its wallet is still needed
+	previousPath := ""
+	_, err = c.readOutput(fmt.Sprintf("%s test -e %s", rootCmd, shellescape.Quote(dataPath)))
+	if err == nil {
+		previousPath = fmt.Sprintf("%s.pre-restore-%d", dataPath, timestamp)
+		err = c.printOutput(fmt.Sprintf("%s mv %s %s", rootCmd, shellescape.Quote(dataPath), shellescape.Quote(previousPath)))
+		if err != nil {
+			_ = c.printOutput(fmt.Sprintf("%s rm -rf %s", rootCmd, quotedStagingPath))
+			return "", fmt.Errorf("error moving the current data folder [%s] aside: %w", dataPath, err)
+		}
+	}
+
+	// Swap in the restored one, putting the old one back if that fails
+	fmt.Printf("Restoring %s...\n", dataPath)
+	err = c.printOutput(fmt.Sprintf("%s mv %s %s", rootCmd, quotedStagingPath, shellescape.Quote(dataPath)))
+	if err != nil {
+		if previousPath != "" {
+			_ = c.printOutput(fmt.Sprintf("%s mv %s %s", rootCmd, shellescape.Quote(previousPath), shellescape.Quote(dataPath)))
+		}
+		return "", fmt.Errorf("error moving the restored data folder into place at [%s]: %w", dataPath, err)
+	}
+	return previousPath, nil
+}
+
+// Move the paths in a backup's user settings from the backed up node's config folder to the local one, so the restored node doesn't use folders from the other machine
+func relocateSettings(settings []byte, configPath string) ([]byte, error) {
+	var root yaml.MapSlice
+	err := yaml.Unmarshal(settings, &root)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the backup's user settings: %w", err)
+	}
+
+	// The settings record the config folder they were saved in
+	oldPath := ""
+	for _, item := range root {
+		if item.Key == ids.UserDirID {
+			oldPath, _ = item.Value.(string)
+		}
+	}
+	if oldPath == "" {
+		return settings, nil
+	}
+	oldPath = filepath.Clean(oldPath)
+	configPath = filepath.Clean(configPath)
+	if oldPath == configPath {
+		return settings, nil
+	}
+
+	relocated := relocateSettingsValue(root, oldPath, configPath)
+	relocatedSettings, err := yaml.Marshal(relocated)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing the backup's user settings: %w", err)
+	}
+	return relocatedSettings, nil
+}
+
+// Recursively replace the old config folder with the new one in any settings value that's a path inside it
+func relocateSettingsValue(value any, oldPath string, newPath string) any {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		for i, item := range value {
+			value[i].Value = relocateSettingsValue(item.Value, oldPath, newPath)
+		}
+		return value
+	case []any:
+		for i, item := range value {
+			value[i] = relocateSettingsValue(item, oldPath, newPath)
+		}
+		return value
+	case string:
+		if value == oldPath {
+			return newPath
+		}
+		if strings.HasPrefix(value, oldPath+string(filepath.Separator)) {
+			return filepath.Join(newPath, strings.TrimPrefix(value, oldPath))
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// Get the node address from a tar archive of the user data folder, or the zero address if it doesn't have a wallet
+func getBackupNodeAddress(data []byte) (common.Address, error) {
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return common.Address{}, nil
+		}
+		if err != nil {
+			return common.Address{}, fmt.Errorf("error reading data archive: %w", err)
+		}
+		if path.Clean(header.Name) != hdconfig.UserAddressFilename {
+			continue
+		}
+		address, err := io.ReadAll(reader)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("error reading node address from data archive: %w", err)
+		}
+		return common.HexToAddress(strings.TrimSpace(string(address))), nil
+	}
+}
+
+// Derive the cipher for a backup from its passphrase
+func getBackupCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, backupScryptN, backupScryptR, backupScryptP, backupKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving backup key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating backup cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating backup cipher: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,394 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	bundleManifestName         string = "bundle.json"
+	bundleImagesName           string = "images.tar"
+	bundleChecksumManifestName string = "bundle-checksums.txt"
+	bundleSigningKeyName       string = "bundle-signing-key.pub"
+)
+
+// Describes the contents of an offline installation bundle
+type BundleManifest struct {
+	// The Hyperdrive version the bundle installs
+	Version string `json:"version"`
+
+	// The Docker images included in the bundle
+	Images []string `json:"images"`
+
+	// When the bundle was created
+	Created time.Time `json:"created"`
+}
+
+// Get the Docker images referenced by the rendered Hyperdrive configuration.
+// The templates are rendered into a temporary folder, so the running installation isn't changed.
+func (c *HyperdriveClient) GetComposeImages() ([]string, error) {
+	expandedConfigPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if isNew {
+		return nil, fmt.Errorf("settings file not found. Please run `hyperdrive service config` to set up Hyperdrive first")
+	}
+
+	runtimeFolder, err := os.MkdirTemp("", "hyperdrive-runtime-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary runtime folder: %w", err)
+	}
+	defer os.RemoveAll(runtimeFolder)
+	renderedFiles, err := c.renderTemplates(cfg, expandedConfigPath, runtimeFolder)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering Docker templates: %w", err)
+	}
+
+	// Overrides are only copied in when the service is started, so skip any that haven't been yet
+	composeFiles := []string{}
+	for _, file := range renderedFiles {
+		_, err := os.Stat(file)
+		if err == nil {
+			composeFiles = append(composeFiles, file)
+		}
+	}
+	cmd, err := c.getComposeCommand(cfg, expandedConfigPath, composeFiles, "config --images")
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.readOutput(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error getting images from the Docker compose config: %w", err)
+	}
+
+	images := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		image := strings.TrimSpace(line)
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// Create an offline installation bundle with the installer for the given version and all of the Docker images used by the current configuration
+// The bundle is signed with a new key, whose fingerprint is returned so it can be checked when the bundle is installed.
+func (c *HyperdriveClient) CreateBundle(version string, outputPath string) (*BundleManifest, string, error) {
+	// Pin the version so the installer artifacts all come from the same release
+	version, err := ResolveReleaseVersion(version)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Get the images
+	images, err := c.GetComposeImages()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, image := range images {
+		fmt.Printf("Pulling %s...\n", image)
+		err = c.PullImage(image)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Download the installer artifacts
+	artifacts := map[string]string{
+		InstallerName:                             fmt.Sprintf(InstallerURL, version),
+		InstallerPackageName:                      fmt.Sprintf(InstallerPackageURL, version),
+		ChecksumManifestName:                      fmt.Sprintf(ChecksumManifestURL, version),
+		ChecksumManifestName + SignatureExtension: fmt.Sprintf(ChecksumManifestURL, version) + SignatureExtension,
+	}
+	artifactData := map[string][]byte{}
+	for name, url := range artifacts {
+		fmt.Printf("Downloading %s...\n", name)
+		data, err := DownloadReleaseArtifact(url)
+		if err != nil {
+			return nil, "", err
+		}
+		artifactData[name] = data
+	}
+
+	// Make sure they're legitimate before bundling them
+	manifest, err := ParseChecksumManifest(artifactData[ChecksumManifestName], artifactData[ChecksumManifestName+SignatureExtension])
+	if err != nil {
+		return nil, "", err
+	}
+	err = manifest.VerifyVersion(version)
+	if err != nil {
+		return nil, "", fmt.Errorf("error verifying checksum manifest: %w", err)
+	}
+	for _, name := range []string{InstallerName, InstallerPackageName} {
+		err = verifyInstallerArtifact(name, artifactData[name], manifest)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Save the images to a temporary file, since the archive needs to know their size up front
+	fmt.Println("Exporting images...")
+	imagesFile, err := os.CreateTemp("", "hyperdrive-bundle-images-*.tar")
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating temporary image archive: %w", err)
+	}
+	defer os.Remove(imagesFile.Name())
+	defer imagesFile.Close()
+	err = c.SaveImages(images, imagesFile)
+	if err != nil {
+		return nil, "", err
+	}
+	imagesInfo, err := imagesFile.Stat()
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting temporary image archive size: %w", err)
+	}
+	_, err = imagesFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, "", fmt.Errorf("error rewinding temporary image archive: %w", err)
+	}
+
+	// Create the bundle manifest
+	bundleManifest := &BundleManifest{
+		Version: version,
+		Images:  images,
+		Created: time.Now(),
+	}
+	bundleManifestBytes, err := json.MarshalIndent(bundleManifest, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("error serializing bundle manifest: %w", err)
+	}
+	artifactData[bundleManifestName] = bundleManifestBytes
+
+	// Sign the bundle's own files, since they aren't covered by the release's checksum manifest
+	imagesChecksum, err := getFileSha256(imagesFile.Name())
+	if err != nil {
+		return nil, "", fmt.Errorf("error hashing temporary image archive: %w", err)
+	}
+	bundleManifestChecksum := sha256.Sum256(bundleManifestBytes)
+	checksumManifest := createChecksumManifest(version, map[string]string{
+		bundleManifestName: hex.EncodeToString(bundleManifestChecksum[:]),
+		bundleImagesName:   imagesChecksum,
+	})
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("error generating bundle signing key: %w", err)
+	}
+	artifactData[bundleChecksumManifestName] = checksumManifest
+	artifactData[bundleChecksumManifestName+SignatureExtension] = ed25519.Sign(privateKey, checksumManifest)
+	artifactData[bundleSigningKeyName] = []byte(base64.StdEncoding.EncodeToString(publicKey))
+	fingerprint := getSigningKeyFingerprint(publicKey)
+
+	// Write the bundle
+	fmt.Printf("Writing bundle to %s...\n", outputPath)
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating bundle [%s]: %w", outputPath, err)
+	}
+	defer output.Close()
+	writer := tar.NewWriter(output)
+	for _, name := range []string{
+		bundleManifestName,
+		bundleChecksumManifestName,
+		bundleChecksumManifestName + SignatureExtension,
+		bundleSigningKeyName,
+		InstallerName,
+		InstallerPackageName,
+		ChecksumManifestName,
+		ChecksumManifestName + SignatureExtension,
+	} {
+		err = writeBundleEntry(writer, name, int64(len(artifactData[name])), bytes.NewReader(artifactData[name]))
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	err = writeBundleEntry(writer, bundleImagesName, imagesInfo.Size(), imagesFile)
+	if err != nil {
+		return nil, "", err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("error finalizing bundle [%s]: %w", outputPath, err)
+	}
+	err = output.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("error closing bundle [%s]: %w", outputPath, err)
+	}
+	return bundleManifest, fingerprint, nil
+}
+
+// Extract an offline installation bundle into a directory and read its manifest
+func ExtractBundle(bundlePath string, dir string) (*BundleManifest, error) {
+	input, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bundle [%s]: %w", bundlePath, err)
+	}
+	defer input.Close()
+
+	reader := tar.NewReader(input)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading bundle [%s]: %w", bundlePath, err)
+		}
+
+		// Bundles are flat, so reject anything that isn't a plain file in the root
+		name := header.Name
+		if header.Typeflag != tar.TypeReg || name != filepath.Base(name) || name == ".." {
+			return nil, fmt.Errorf("bundle [%s] has an unexpected entry [%s]", bundlePath, name)
+		}
+		file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error extracting [%s] from bundle: %w", name, err)
+		}
+		_, err = io.Copy(file, reader)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error extracting [%s] from bundle: %w", name, err)
+		}
+	}
+
+	manifestBytes, err := os.ReadFile(filepath.Join(dir, bundleManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle manifest: %w", err)
+	}
+	var manifest BundleManifest
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing bundle manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// Get the fingerprint of the key that signed an extracted offline installation bundle
+func GetBundleSigningKeyFingerprint(bundleDir string) (string, error) {
+	key, err := getBundleSigningKey(bundleDir)
+	if err != nil {
+		return "", err
+	}
+	return getSigningKeyFingerprint(key), nil
+}
+
+// Check that an extracted offline installation bundle was signed by the key with the provided fingerprint,
+// and that its manifest and images haven't been changed since. This must be done before its images are loaded.
+func VerifyBundle(bundleDir string, fingerprint string, manifest *BundleManifest) error {
+	key, err := getBundleSigningKey(bundleDir)
+	if err != nil {
+		return err
+	}
+	actualFingerprint := getSigningKeyFingerprint(key)
+	if actualFingerprint != fingerprint {
+		return fmt.Errorf("bundle was signed by %s, not %s", actualFingerprint, fingerprint)
+	}
+
+	checksumManifestPath := filepath.Join(bundleDir, bundleChecksumManifestName)
+	checksumManifest, err := os.ReadFile(checksumManifestPath)
+	if err != nil {
+		return fmt.Errorf("error reading bundle checksum manifest: %w", err)
+	}
+	signature, err := os.ReadFile(checksumManifestPath + SignatureExtension)
+	if err != nil {
+		return fmt.Errorf("error reading bundle checksum manifest signature: %w", err)
+	}
+	if !ed25519.Verify(key, checksumManifest, signature) {
+		return fmt.Errorf("bundle checksum manifest signature was not made by the bundle signing key")
+	}
+	parsed, err := parseChecksumManifest(checksumManifest)
+	if err != nil {
+		return err
+	}
+	err = parsed.VerifyVersion(manifest.Version)
+	if err != nil {
+		return fmt.Errorf("error verifying bundle: %w", err)
+	}
+	for _, name := range []string{bundleManifestName, bundleImagesName} {
+		err = parsed.VerifyFile(name, filepath.Join(bundleDir, name))
+		if err != nil {
+			return fmt.Errorf("error verifying bundle's %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Install Hyperdrive from an extracted offline installation bundle. Its images must be loaded separately with LoadBundleImages.
+// Operating System dependencies are never installed, since they can't be downloaded offline.
+func (c *HyperdriveClient) InstallServiceFromBundle(verbose bool, path string, bundleDir string, manifest *BundleManifest, insecure bool) error {
+	installer, err := LoadLocalInstaller(bundleDir, manifest.Version, insecure)
+	if err != nil {
+		return err
+	}
+	return c.RunInstaller(verbose, true, path, installer)
+}
+
+// Load the Docker images from an extracted offline installation bundle
+func (c *HyperdriveClient) LoadBundleImages(bundleDir string) error {
+	path := filepath.Join(bundleDir, bundleImagesName)
+	images, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening bundle images [%s]: %w", path, err)
+	}
+	defer images.Close()
+
+	fmt.Println("Loading Docker images from the bundle...")
+	return c.LoadImages(images)
+}
+
+// Read the public key an extracted offline installation bundle was signed with
+func getBundleSigningKey(bundleDir string) (ed25519.PublicKey, error) {
+	path := filepath.Join(bundleDir, bundleSigningKeyName)
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle signing key [%s]: %w", path, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding bundle signing key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("bundle signing key is %d bytes, not %d", len(key), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Write a single file into a bundle
+func writeBundleEntry(writer *tar.Writer, name string, size int64, data io.Reader) error {
+	err := writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing bundle header for [%s]: %w", name, err)
+	}
+	_, err = io.Copy(writer, data)
+	if err != nil {
+		return fmt.Errorf("error writing [%s] to bundle: %w", name, err)
+	}
+	return nil
+}
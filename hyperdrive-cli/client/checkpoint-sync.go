@@ -0,0 +1,246 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	checkpointSyncTimeout time.Duration = 15 * time.Second
+
+	genesisPath            string = "/eth/v1/beacon/genesis"
+	depositContractPath    string = "/eth/v1/config/deposit_contract"
+	finalizedHeaderPath    string = "/eth/v1/beacon/headers/finalized"
+	headerAtSlotPathFormat string = "/eth/v1/beacon/headers/%d"
+)
+
+// The finalized checkpoint a provider reported
+type CheckpointInfo struct {
+	// The slot of the finalized block
+	Slot uint64
+
+	// The root of the finalized block
+	BlockRoot string
+
+	// The state root of the finalized block
+	StateRoot string
+}
+
+// The result of checking a single checkpoint sync provider
+type CheckpointProviderResult struct {
+	// The provider's URL
+	Url string
+
+	// True if the provider serves the expected network
+	NetworkMatches bool
+
+	// A description of the network the provider serves, if it didn't match
+	NetworkDetails string
+
+	// The finalized checkpoint reported by the provider, or the block at the primary's finalized slot for secondary providers
+	Checkpoint *CheckpointInfo
+
+	// True if the checkpoint matched the primary provider's
+	CheckpointMatches bool
+
+	// Any error that occurred while checking the provider
+	Error error
+}
+
+// The report for a checkpoint sync provider verification
+type CheckpointSyncReport struct {
+	// The result for the configured provider
+	Primary *CheckpointProviderResult
+
+	// The results for the providers it was compared against
+	Others []*CheckpointProviderResult
+}
+
+// Get a list of problems found during verification; an empty list means the provider can be trusted
+func (r *CheckpointSyncReport) GetWarnings() []string {
+	warnings := []string{}
+	if r.Primary.Error != nil {
+		return append(warnings, fmt.Sprintf("Couldn't query the configured provider %s: %s", r.Primary.Url, r.Primary.Error.Error()))
+	}
+	if !r.Primary.NetworkMatches {
+		warnings = append(warnings, fmt.Sprintf("The configured provider %s is serving the wrong network (%s).", r.Primary.Url, r.Primary.NetworkDetails))
+	}
+	if len(r.Others) == 0 {
+		warnings = append(warnings, "No other providers were given to compare the finalized checkpoint against.")
+	}
+	for _, other := range r.Others {
+		if other.Error != nil {
+			warnings = append(warnings, fmt.Sprintf("Couldn't query provider %s: %s", other.Url, other.Error.Error()))
+			continue
+		}
+		if !other.NetworkMatches {
+			warnings = append(warnings, fmt.Sprintf("Provider %s is serving the wrong network (%s), so it can't be compared.", other.Url, other.NetworkDetails))
+			continue
+		}
+		if !other.CheckpointMatches {
+			warnings = append(warnings, fmt.Sprintf("Provider %s disagrees about the block at slot %d: block root %s, state root %s (configured provider: block root %s, state root %s).", other.Url, r.Primary.Checkpoint.Slot, other.Checkpoint.BlockRoot, other.Checkpoint.StateRoot, r.Primary.Checkpoint.BlockRoot, r.Primary.Checkpoint.StateRoot))
+		}
+	}
+	return warnings
+}
+
+// Check if the configured provider serves the expected network and every provider it was compared against agrees with it.
+// A missing comparison is worth a warning but doesn't fail the check.
+func (r *CheckpointSyncReport) IsVerified() bool {
+	if r.Primary.Error != nil || !r.Primary.NetworkMatches {
+		return false
+	}
+	for _, other := range r.Others {
+		if other.Error != nil || !other.NetworkMatches || !other.CheckpointMatches {
+			return false
+		}
+	}
+	return true
+}
+
+// Verify a checkpoint sync provider by making sure it serves the expected network and that its finalized checkpoint
+// matches the block and state roots reported by each of the other providers
+func VerifyCheckpointSyncProvider(providerUrl string, otherUrls []string, resources *config.NetworkResources) *CheckpointSyncReport {
+	httpClient := &http.Client{
+		Timeout: checkpointSyncTimeout,
+	}
+
+	// Check the configured provider
+	report := &CheckpointSyncReport{
+		Primary: &CheckpointProviderResult{
+			Url: providerUrl,
+		},
+		Others: []*CheckpointProviderResult{},
+	}
+	primary := report.Primary
+	primary.NetworkMatches, primary.NetworkDetails, primary.Error = checkProviderNetwork(httpClient, providerUrl, resources)
+	if primary.Error != nil {
+		return report
+	}
+	primary.Checkpoint, primary.Error = getBeaconHeader(httpClient, providerUrl, finalizedHeaderPath)
+	if primary.Error != nil {
+		return report
+	}
+	primary.CheckpointMatches = true
+
+	// Compare the others against the configured provider's finalized block
+	for _, otherUrl := range otherUrls {
+		other := &CheckpointProviderResult{
+			Url: otherUrl,
+		}
+		report.Others = append(report.Others, other)
+
+		other.NetworkMatches, other.NetworkDetails, other.Error = checkProviderNetwork(httpClient, otherUrl, resources)
+		if other.Error != nil || !other.NetworkMatches {
+			continue
+		}
+		other.Checkpoint, other.Error = getBeaconHeader(httpClient, otherUrl, fmt.Sprintf(headerAtSlotPathFormat, primary.Checkpoint.Slot))
+		if other.Error != nil {
+			continue
+		}
+		other.CheckpointMatches = strings.EqualFold(other.Checkpoint.BlockRoot, primary.Checkpoint.BlockRoot) &&
+			strings.EqualFold(other.Checkpoint.StateRoot, primary.Checkpoint.StateRoot)
+	}
+	return report
+}
+
+// Check that a provider serves the expected network based on its genesis fork version and deposit contract chain ID
+func checkProviderNetwork(httpClient *http.Client, providerUrl string, resources *config.NetworkResources) (bool, string, error) {
+	// Check the genesis fork version
+	var genesis struct {
+		Data struct {
+			GenesisForkVersion string `json:"genesis_fork_version"`
+		} `json:"data"`
+	}
+	err := getBeaconApiResponse(httpClient, providerUrl, genesisPath, &genesis)
+	if err != nil {
+		return false, "", err
+	}
+	forkVersion, err := hexutil.Decode(genesis.Data.GenesisForkVersion)
+	if err != nil {
+		return false, "", fmt.Errorf("error parsing genesis fork version [%s]: %w", genesis.Data.GenesisForkVersion, err)
+	}
+	if !bytes.Equal(forkVersion, resources.GenesisForkVersion) {
+		return false, fmt.Sprintf("genesis fork version %s, expected %s", genesis.Data.GenesisForkVersion, hexutil.Encode(resources.GenesisForkVersion)), nil
+	}
+
+	// Check the chain ID
+	var depositContract struct {
+		Data struct {
+			ChainID string `json:"chain_id"`
+		} `json:"data"`
+	}
+	err = getBeaconApiResponse(httpClient, providerUrl, depositContractPath, &depositContract)
+	if err != nil {
+		return false, "", err
+	}
+	chainID, err := strconv.ParseUint(depositContract.Data.ChainID, 10, 64)
+	if err != nil {
+		return false, "", fmt.Errorf("error parsing chain ID [%s]: %w", depositContract.Data.ChainID, err)
+	}
+	if uint(chainID) != resources.ChainID {
+		return false, fmt.Sprintf("chain ID %d, expected %d", chainID, resources.ChainID), nil
+	}
+	return true, "", nil
+}
+
+// Get a block header from a provider
+func getBeaconHeader(httpClient *http.Client, providerUrl string, path string) (*CheckpointInfo, error) {
+	var header struct {
+		Data struct {
+			Root   string `json:"root"`
+			Header struct {
+				Message struct {
+					Slot      string `json:"slot"`
+					StateRoot string `json:"state_root"`
+				} `json:"message"`
+			} `json:"header"`
+		} `json:"data"`
+	}
+	err := getBeaconApiResponse(httpClient, providerUrl, path, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := strconv.ParseUint(header.Data.Header.Message.Slot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing slot [%s]: %w", header.Data.Header.Message.Slot, err)
+	}
+	return &CheckpointInfo{
+		Slot:      slot,
+		BlockRoot: header.Data.Root,
+		StateRoot: header.Data.Header.Message.StateRoot,
+	}, nil
+}
+
+// Run a GET request against a Beacon API and deserialize the response
+func getBeaconApiResponse(httpClient *http.Client, providerUrl string, path string, result any) error {
+	url := strings.TrimSuffix(providerUrl, "/") + path
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("error requesting %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response for %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status requesting %s: %d", path, resp.StatusCode)
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("error deserializing response for %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	// The name of the top-level settings section that belongs to the CLI instead of the daemon
+	CliConfigName string = "cli"
+
+	containerRuntimeID     string = "containerRuntime"
+	rootfulDockerSocket    string = "/var/run/docker.sock"
+	rootlessDockerSocket   string = "docker.sock"
+	rootlessDockerPidFile  string = "docker.pid"
+	rootlessPodmanSocket   string = "podman/podman.sock"
+	runtimeDirFormat       string = "/run/user/%s"
+	runtimeDirEnvVar       string = "XDG_RUNTIME_DIR"
+	dockerHostEnvVar       string = "DOCKER_HOST"
+	unixSocketScheme       string = "unix://"
+	noNewPrivilegesOption  string = "no-new-privileges"
+	disableSelinuxLabeling string = "label=disable"
+)
+
+// The container runtime that runs Hyperdrive's containers
+type ContainerRuntime string
+
+const (
+	// Docker running as root, with the socket at /var/run/docker.sock
+	ContainerRuntime_Docker ContainerRuntime = "docker"
+
+	// Docker running in rootless mode as the current user
+	ContainerRuntime_RootlessDocker ContainerRuntime = "rootless-docker"
+
+	// Podman running in rootless mode as the current user, with its Docker-compatible socket enabled
+	ContainerRuntime_Podman ContainerRuntime = "podman"
+)
+
+// Settings that only the CLI uses, which are stored alongside the Hyperdrive config
+type CliConfig struct {
+	// The container runtime to use
+	ContainerRuntime config.Parameter[ContainerRuntime]
+
+	// Settings for scheduled backups
+	Backup *BackupConfig
+
+	// Settings for alerting rules and Alertmanager
+	Alerting *AlertingConfig
+
+	// Settings for event notifications
+	Notifications *NotificationsConfig
+
+	// Settings for MEV-Boost
+	MevBoost *MevBoostConfig
+
+	// Settings for each container's resource limits and log rotation
+	Resources *ResourcesConfig
+}
+
+// Generates a new CLI config
+func NewCliConfig() *CliConfig {
+	return &CliConfig{
+		ContainerRuntime: config.Parameter[ContainerRuntime]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 containerRuntimeID,
+				Name:               "Container Runtime",
+				Description:        "Select the container runtime that runs Hyperdrive's containers.\n\nRootless runtimes run as your user instead of root, so a container that escapes can't take over the machine. They must already be installed and running; the Hyperdrive installer only sets up rootful Docker.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_BeaconNode, config.ContainerID_Daemon, config.ContainerID_ExecutionClient, config.ContainerID_Exporter, config.ContainerID_Grafana, config.ContainerID_Prometheus, config.ContainerID_ValidatorClient},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Options: []*config.ParameterOption[ContainerRuntime]{
+				{
+					ParameterOptionCommon: &config.ParameterOptionCommon{
+						Name:        "Docker",
+						Description: "Use Docker running as root. This is the standard Docker installation.",
+					},
+					Value: ContainerRuntime_Docker,
+				}, {
+					ParameterOptionCommon: &config.ParameterOptionCommon{
+						Name:        "Rootless Docker",
+						Description: "Use Docker running in rootless mode as your user. See https://docs.docker.com/engine/security/rootless/ to set it up.",
+					},
+					Value: ContainerRuntime_RootlessDocker,
+				}, {
+					ParameterOptionCommon: &config.ParameterOptionCommon{
+						Name:        "Podman",
+						Description: "Use rootless Podman as your user, with `podman compose` and its Docker-compatible socket. Enable the socket with `systemctl --user enable --now podman.socket`.",
+					},
+					Value: ContainerRuntime_Podman,
+				},
+			},
+			Default: map[config.Network]ContainerRuntime{
+				config.Network_All: ContainerRuntime_Docker,
+			},
+		},
+
+		Backup:        NewBackupConfig(),
+		Alerting:      NewAlertingConfig(),
+		Notifications: NewNotificationsConfig(),
+		MevBoost:      NewMevBoostConfig(),
+		Resources:     NewResourcesConfig(),
+	}
+}
+
+// The title for the config
+func (cfg *CliConfig) GetTitle() string {
+	return "CLI"
+}
+
+// Get the parameters for this config
+func (cfg *CliConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.ContainerRuntime,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *CliConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{
+		backupConfigID:        cfg.Backup,
+		alertingConfigID:      cfg.Alerting,
+		notificationsConfigID: cfg.Notifications,
+		mevBoostConfigID:      cfg.MevBoost,
+		resourcesConfigID:     cfg.Resources,
+	}
+}
+
+// Creates a copy of the config
+func (cfg *CliConfig) Clone() *CliConfig {
+	clone := NewCliConfig()
+	config.Clone(cfg, clone, config.Network_All)
+	return clone
+}
+
+// Deserialize the CLI section of a settings file. Settings files made before the CLI had its own section don't have one, so they get the defaults.
+func (cfg *CliConfig) Deserialize(configMap map[string]any, network config.Network) error {
+	err := config.Deserialize(cfg, configMap, network)
+	if err != nil {
+		return fmt.Errorf("error deserializing [%s]: %w", CliConfigName, err)
+	}
+	return nil
+}
+
+// Check if the selected runtime runs as the current user instead of root
+func (cfg *CliConfig) IsRootless() bool {
+	return cfg.ContainerRuntime.Value != ContainerRuntime_Docker
+}
+
+// Get the address of the selected runtime's Docker-compatible API.
+// Like the Docker CLI, rootless Docker respects a Docker host that's already been set up in the environment.
+func (cfg *CliConfig) GetDockerHost() string {
+	switch cfg.ContainerRuntime.Value {
+	case ContainerRuntime_RootlessDocker:
+		dockerHost := os.Getenv(dockerHostEnvVar)
+		if dockerHost != "" {
+			return dockerHost
+		}
+		return unixSocketScheme + filepath.Join(getUserRuntimeDir(), rootlessDockerSocket)
+	case ContainerRuntime_Podman:
+		return unixSocketScheme + filepath.Join(getUserRuntimeDir(), rootlessPodmanSocket)
+	default:
+		return unixSocketScheme + rootfulDockerSocket
+	}
+}
+
+// Get the path of the selected runtime's Docker-compatible API socket on the host, so it can be mounted into containers
+func (cfg *CliConfig) GetDockerSocketPath() string {
+	dockerHost := cfg.GetDockerHost()
+	if strings.HasPrefix(dockerHost, unixSocketScheme) {
+		return strings.TrimPrefix(dockerHost, unixSocketScheme)
+	}
+
+	// A Docker host that isn't a local socket can't be mounted, so fall back to the runtime's default socket
+	return filepath.Join(getUserRuntimeDir(), rootlessDockerSocket)
+}
+
+// Get the security options for containers that mount the runtime's socket
+func (cfg *CliConfig) GetSocketSecurityOptions() []string {
+	options := []string{noNewPrivilegesOption}
+	if cfg.ContainerRuntime.Value == ContainerRuntime_Podman {
+		// SELinux hosts won't let a labeled container talk to Podman's socket
+		options = append(options, disableSelinuxLabeling)
+	}
+	return options
+}
+
+// Get the user's runtime directory, where rootless runtimes put their sockets
+func getUserRuntimeDir() string {
+	runtimeDir := os.Getenv(runtimeDirEnvVar)
+	if runtimeDir != "" {
+		return runtimeDir
+	}
+	return fmt.Sprintf(runtimeDirFormat, strconv.Itoa(os.Getuid()))
+}
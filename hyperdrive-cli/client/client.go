@@ -21,6 +21,11 @@ const (
 	InstallerName string = "install.sh"
 	InstallerURL  string = "https://github.com/nodeset-org/hyperdrive/releases/download/%s/" + InstallerName
 
+	InstallerPackageName string = "hyperdrive-install.tar.xz"
+	InstallerPackageURL  string = "https://github.com/nodeset-org/hyperdrive/releases/download/%s/" + InstallerPackageName
+	ChecksumManifestName string = "checksums.txt"
+	ChecksumManifestURL  string = "https://github.com/nodeset-org/hyperdrive/releases/download/%s/" + ChecksumManifestName
+
 	SettingsFile       string = "user-settings.yml"
 	BackupSettingsFile string = "user-settings-backup.yml"
 
@@ -80,8 +85,17 @@ func NewStakewiseClientFromCtx(c *cli.Context) *StakewiseClient {
 // Get the Docker client
 func (c *HyperdriveClient) GetDocker() (*docker.Client, error) {
 	if c.docker == nil {
-		var err error
-		c.docker, err = docker.NewClientWithOpts(docker.WithAPIVersionNegotiation())
+		cfg, _, err := c.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error loading user settings: %w", err)
+		}
+
+		// Rootless runtimes listen on a socket in the user's runtime directory instead of the default one
+		opts := []docker.Opt{docker.WithAPIVersionNegotiation()}
+		if cfg.Cli.IsRootless() {
+			opts = append(opts, docker.WithHost(cfg.Cli.GetDockerHost()))
+		}
+		c.docker, err = docker.NewClientWithOpts(opts...)
 		if err != nil {
 			return nil, fmt.Errorf("error creating Docker client: %w", err)
 		}
@@ -5,7 +5,10 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+
+	"github.com/alessio/shellescape"
 )
 
 // ===============
@@ -81,7 +84,8 @@ func (c *command) OutputPipes() (io.Reader, io.Reader, error) {
 // === Client ===
 // ==============
 
-// Get the command used to escalate privileges on the system
+// Get the command used to escalate privileges on the system.
+// This is for system files, such as the installation folder; files written by containers should be removed with getRuntimeEscalationCommand instead.
 func (c *HyperdriveClient) getEscalationCommand() (string, error) {
 	// Check for sudo first
 	sudo := "sudo"
@@ -106,6 +110,44 @@ func (c *HyperdriveClient) getEscalationCommand() (string, error) {
 	return "", fmt.Errorf("no privilege escalation command found")
 }
 
+// Get the command used to act on files written by containers, such as the data folder.
+// Rootful Docker's files belong to root, but rootless runtimes map container users to the current user's subordinate IDs,
+// so root can't be used there; the command has to enter the runtime's user namespace instead.
+func (c *HyperdriveClient) getRuntimeEscalationCommand() (string, error) {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	switch cfg.Cli.ContainerRuntime.Value {
+	case ContainerRuntime_RootlessDocker:
+		pidFile := filepath.Join(getUserRuntimeDir(), rootlessDockerPidFile)
+		return fmt.Sprintf("nsenter -U --preserve-credentials -m -t $(cat %s)", shellescape.Quote(pidFile)), nil
+	case ContainerRuntime_Podman:
+		return "podman unshare", nil
+	default:
+		return c.getEscalationCommand()
+	}
+}
+
+// Get the command used to run the selected container runtime's CLI
+func (c *HyperdriveClient) getRuntimeCommand() (string, error) {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	switch cfg.Cli.ContainerRuntime.Value {
+	case ContainerRuntime_RootlessDocker:
+		// Point the CLI at the same Docker host as the API client
+		return fmt.Sprintf("%s=%s docker", dockerHostEnvVar, shellescape.Quote(cfg.Cli.GetDockerHost())), nil
+	case ContainerRuntime_Podman:
+		return "podman", nil
+	default:
+		return "docker", nil
+	}
+}
+
 func (c *HyperdriveClient) checkIfCommandExists(command string) (bool, error) {
 	// Run `type` to check for existence
 	cmd := fmt.Sprintf("type %s", command)
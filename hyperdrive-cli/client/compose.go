@@ -12,6 +12,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client/template"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
 	"github.com/rocket-pool/node-manager-core/config"
 )
 
@@ -43,6 +44,21 @@ func (c *HyperdriveClient) compose(composeFiles []string, args string) (string,
 		return "", errors.New("no Beacon Node selected. Please run 'hyperdrive service config' before running this command")
 	}
 
+	// Switch to portable images if this CPU can't run the modern ones
+	changes, err := c.ApplyPortableImages(cfg)
+	if err != nil {
+		return "", fmt.Errorf("error selecting portable images: %w", err)
+	}
+	if len(changes) > 0 {
+		for _, change := range changes {
+			fmt.Printf("%sNOTE: Your CPU doesn't support the modern %s image (%s), switching to the portable one (%s).%s\n", terminal.ColorYellow, change.Section, change.OldTag, change.NewTag, terminal.ColorReset)
+		}
+		err = c.SaveConfig(cfg)
+		if err != nil {
+			return "", fmt.Errorf("error saving portable image selection: %w", err)
+		}
+	}
+
 	// Deploy the templates and run environment variable substitution on them
 	deployedContainers, err := c.deployTemplates(cfg, expandedConfigPath)
 	if err != nil {
@@ -50,16 +66,21 @@ func (c *HyperdriveClient) compose(composeFiles []string, args string) (string,
 	}
 
 	// Include all of the relevant docker compose definition files
+	return c.getComposeCommand(cfg, expandedConfigPath, append(deployedContainers, composeFiles...), args)
+}
+
+// Build a docker compose command that uses the provided compose definition files
+func (c *HyperdriveClient) getComposeCommand(cfg *GlobalConfig, hyperdriveDir string, composeFiles []string, args string) (string, error) {
 	composeFileFlags := []string{}
-	for _, container := range deployedContainers {
-		composeFileFlags = append(composeFileFlags, fmt.Sprintf("-f %s", shellescape.Quote(container)))
-	}
 	for _, container := range composeFiles {
 		composeFileFlags = append(composeFileFlags, fmt.Sprintf("-f %s", shellescape.Quote(container)))
 	}
 
-	// Return command
-	return fmt.Sprintf("COMPOSE_PROJECT_NAME=%s docker compose --project-directory %s %s %s", cfg.Hyperdrive.ProjectName.Value, shellescape.Quote(expandedConfigPath), strings.Join(composeFileFlags, " "), args), nil
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("COMPOSE_PROJECT_NAME=%s %s compose --project-directory %s %s %s", cfg.Hyperdrive.ProjectName.Value, runtimeCmd, shellescape.Quote(hyperdriveDir), strings.Join(composeFileFlags, " "), args), nil
 }
 
 // Deploys all of the appropriate docker compose template files and provisions them based on the provided configuration
@@ -95,10 +116,23 @@ func (c *HyperdriveClient) deployTemplates(cfg *GlobalConfig, hyperdriveDir stri
 		return []string{}, fmt.Errorf("error creating extra-scrape-jobs folder: %w", err)
 	}
 
+	// Make the extra alerting rules folder
+	extraAlertingRulesFolder := filepath.Join(hyperdriveDir, extraAlertingRulesDir)
+	err = os.MkdirAll(extraAlertingRulesFolder, 0755)
+	if err != nil {
+		return []string{}, fmt.Errorf("error creating extra-alerting-rules folder: %w", err)
+	}
+
+	return c.renderTemplates(cfg, hyperdriveDir, runtimeFolder)
+}
+
+// Renders the docker compose template files for the containers used by the provided configuration into the runtime folder.
+// Returns the paths of the rendered files along with their overrides.
+func (c *HyperdriveClient) renderTemplates(cfg *GlobalConfig, hyperdriveDir string, runtimeFolder string) ([]string, error) {
 	composePaths := template.ComposePaths{
 		RuntimePath:  runtimeFolder,
 		TemplatePath: templatesDir,
-		OverridePath: overrideFolder,
+		OverridePath: filepath.Join(hyperdriveDir, overrideDir),
 	}
 
 	// Read and substitute the templates
@@ -115,6 +149,11 @@ func (c *HyperdriveClient) deployTemplates(cfg *GlobalConfig, hyperdriveDir stri
 		toDeploy = append(toDeploy, config.ContainerID_BeaconNode)
 	}
 
+	// Check if we are running MEV-Boost
+	if cfg.Cli.MevBoost.IsLocal() {
+		toDeploy = append(toDeploy, config.ContainerID_MevBoost)
+	}
+
 	// Check the metrics containers
 	if cfg.Hyperdrive.Metrics.EnableMetrics.Value {
 		toDeploy = append(toDeploy,
@@ -122,6 +161,9 @@ func (c *HyperdriveClient) deployTemplates(cfg *GlobalConfig, hyperdriveDir stri
 			config.ContainerID_Exporter,
 			config.ContainerID_Prometheus,
 		)
+		if cfg.Cli.Alerting.EnableAlerting.Value {
+			toDeploy = append(toDeploy, ContainerID_Alertmanager)
+		}
 	}
 
 	// Deploy main containers
@@ -134,9 +176,10 @@ func (c *HyperdriveClient) deployTemplates(cfg *GlobalConfig, hyperdriveDir stri
 	}
 
 	// Deploy modules
+	var err error
 	for _, module := range cfg.GetAllModuleConfigs() {
 		if module.IsEnabled() {
-			deployedContainers, err = c.composeModule(cfg, module, hyperdriveDir, deployedContainers)
+			deployedContainers, err = c.composeModule(cfg, module, hyperdriveDir, runtimeFolder, deployedContainers)
 			if err != nil {
 				return nil, err
 			}
@@ -239,10 +282,10 @@ func removeComposeVersion(targetDir string) error {
 }
 
 // Handle composing for modules
-func (c *HyperdriveClient) composeModule(global *GlobalConfig, module hdconfig.IModuleConfig, hyperdriveDir string, deployedContainers []string) ([]string, error) {
+func (c *HyperdriveClient) composeModule(global *GlobalConfig, module hdconfig.IModuleConfig, hyperdriveDir string, runtimeFolder string, deployedContainers []string) ([]string, error) {
 	moduleName := module.GetModuleName()
 	composePaths := template.ComposePaths{
-		RuntimePath:  filepath.Join(hyperdriveDir, runtimeDir, hdconfig.ModulesName, moduleName),
+		RuntimePath:  filepath.Join(runtimeFolder, hdconfig.ModulesName, moduleName),
 		TemplatePath: filepath.Join(templatesDir, hdconfig.ModulesName, moduleName),
 		OverridePath: filepath.Join(hyperdriveDir, overrideDir, hdconfig.ModulesName, moduleName),
 	}
@@ -64,6 +64,12 @@ func (c *HyperdriveClient) SaveConfig(cfg *GlobalConfig) error {
 	if err != nil {
 		return err
 	}
+
+	// Make sure the CPU can run the selected images
+	_, err = c.ApplyPortableImages(cfg)
+	if err != nil {
+		return fmt.Errorf("error selecting portable images: %w", err)
+	}
 	return SaveConfig(cfg, settingsFileDirectoryPath, SettingsFile)
 }
 
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client/template"
+)
+
+const (
+	grafanaDashboardsTemplate string = "grafana-dashboards.tmpl"
+	grafanaDashboardsTarget   string = "grafana-dashboards.yml"
+	dashboardTemplatesDir     string = "dashboards"
+	grafanaDashboardsDir      string = "grafana-dashboards"
+	customDashboardsDir       string = "custom-dashboards"
+	dashboardTemplateSuffix   string = ".tmpl"
+	dashboardSuffix           string = ".json"
+)
+
+// Get the names of the dashboard templates to provision for the selected clients and enabled modules
+func (c *GlobalConfig) GetGrafanaDashboards() []string {
+	dashboards := []string{
+		"hyperdrive",
+		"node-exporter",
+	}
+	ec := c.Hyperdrive.GetSelectedExecutionClient()
+	if ec != "" {
+		dashboards = append(dashboards, fmt.Sprintf("ec-%s", ec))
+	}
+	bn := c.Hyperdrive.GetSelectedBeaconNode()
+	if bn != "" {
+		dashboards = append(dashboards, "bn")
+		if c.Stakewise.Enabled.Value {
+			// The StakeWise VC is the same client as the Beacon Node
+			dashboards = append(dashboards, fmt.Sprintf("stakewise-vc-%s", bn))
+		}
+	}
+	return dashboards
+}
+
+// Load the Grafana dashboard provider template and the dashboards for the selected clients, do a template variable substitution, and save them.
+// Dashboards from previous runs are removed so switching clients doesn't leave stale ones behind; the custom dashboards folder is never touched.
+func (c *HyperdriveClient) UpdateGrafanaDashboards(config *GlobalConfig) error {
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("error expanding config path: %w", err)
+	}
+
+	// Write the provider config
+	t := template.Template{
+		Src: filepath.Join(templatesDir, grafanaDashboardsTemplate),
+		Dst: filepath.Join(configPath, grafanaDashboardsTarget),
+	}
+	err = t.Write(config)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", grafanaDashboardsTarget, err)
+	}
+
+	// Clear out the provisioned dashboards and remake them; the folder itself is bind-mounted into Grafana, so only its contents are deleted
+	dashboardsFolder := filepath.Join(configPath, grafanaDashboardsDir)
+	err = os.MkdirAll(dashboardsFolder, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating Grafana dashboards folder [%s]: %w", dashboardsFolder, err)
+	}
+	entries, err := os.ReadDir(dashboardsFolder)
+	if err != nil {
+		return fmt.Errorf("error reading Grafana dashboards folder [%s]: %w", dashboardsFolder, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dashboardsFolder, entry.Name())
+		err = os.RemoveAll(path)
+		if err != nil {
+			return fmt.Errorf("error deleting old Grafana dashboard [%s]: %w", path, err)
+		}
+	}
+	for _, dashboard := range config.GetGrafanaDashboards() {
+		t := template.Template{
+			Src: filepath.Join(templatesDir, dashboardTemplatesDir, dashboard+dashboardTemplateSuffix),
+			Dst: filepath.Join(dashboardsFolder, dashboard+dashboardSuffix),
+		}
+		err = t.Write(config)
+		if err != nil {
+			return fmt.Errorf("error writing the %s dashboard: %w", dashboard, err)
+		}
+	}
+
+	// Make the custom dashboards folder
+	customDashboardsFolder := filepath.Join(configPath, customDashboardsDir)
+	err = os.MkdirAll(customDashboardsFolder, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating custom dashboards folder [%s]: %w", customDashboardsFolder, err)
+	}
+	return nil
+}
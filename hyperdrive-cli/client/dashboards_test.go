@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// The parts of a Grafana dashboard the tests check
+type testDashboard struct {
+	Title  string   `json:"title"`
+	Tags   []string `json:"tags"`
+	Panels []struct {
+		Title   string `json:"title"`
+		Targets []struct {
+			Expr string `json:"expr"`
+		} `json:"targets"`
+	} `json:"panels"`
+}
+
+func TestGrafanaDashboardTemplates(t *testing.T) {
+	cfg := newTestAlertingConfig(t)
+	cfg.Stakewise.Enabled.Value = true
+	for _, ec := range cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Options {
+		for _, bn := range cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Options {
+			t.Run(string(ec.Value)+"-"+string(bn.Value), func(t *testing.T) {
+				cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value = ec.Value
+				cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Value = bn.Value
+
+				dashboards := cfg.GetGrafanaDashboards()
+				expected := []string{"hyperdrive", "node-exporter", "ec-" + string(ec.Value), "bn", "stakewise-vc-" + string(bn.Value)}
+				if strings.Join(dashboards, ",") != strings.Join(expected, ",") {
+					t.Fatalf("expected dashboards %v, got %v", expected, dashboards)
+				}
+
+				for _, name := range dashboards {
+					rendered := renderTestTemplate(t, dashboardTemplatesDir+"/"+name+dashboardTemplateSuffix, cfg)
+					var dashboard testDashboard
+					err := json.Unmarshal([]byte(rendered), &dashboard)
+					if err != nil {
+						t.Fatalf("the %s dashboard isn't valid JSON: %v", name, err)
+					}
+					if dashboard.Title == "" {
+						t.Errorf("the %s dashboard has no title", name)
+					}
+					if name != "bn" {
+						continue
+					}
+
+					// The shared Beacon Node dashboard is filled in for the selected client
+					if !strings.Contains(dashboard.Title, bn.Name) {
+						t.Errorf("expected the Beacon Node dashboard title to name %s, got %q", bn.Name, dashboard.Title)
+					}
+					hasTag := false
+					for _, tag := range dashboard.Tags {
+						hasTag = hasTag || tag == string(bn.Value)
+					}
+					if !hasTag {
+						t.Errorf("expected the Beacon Node dashboard to be tagged %s, got %v", bn.Value, dashboard.Tags)
+					}
+					for _, panel := range dashboard.Panels {
+						for _, target := range panel.Targets {
+							if strings.Contains(target.Expr, "<no value>") {
+								t.Errorf("panel %q has an unrendered query: %s", panel.Title, target.Expr)
+							}
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGrafanaDashboardsWithoutStakewise(t *testing.T) {
+	cfg := newTestAlertingConfig(t)
+	cfg.Stakewise.Enabled.Value = false
+	for _, name := range cfg.GetGrafanaDashboards() {
+		if strings.HasPrefix(name, "stakewise-") {
+			t.Errorf("expected no StakeWise dashboards with StakeWise disabled, got %s", name)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// The log of destructive operations, kept outside of the Hyperdrive directory so it survives `service terminate`
+	AuditLogPath string = "~/.hyperdrive-audit.log"
+
+	composeProjectLabel string = "com.docker.compose.project"
+	composeVolumeLabel  string = "com.docker.compose.volume"
+)
+
+// A record of a destructive operation in the audit log
+type AuditEntry struct {
+	Time             time.Time `json:"time"`
+	User             string    `json:"user"`
+	Command          string    `json:"command"`
+	ProjectName      string    `json:"projectName"`
+	ConfirmedWith    string    `json:"confirmedWith"`
+	Volumes          []string  `json:"volumes,omitempty"`
+	Paths            []string  `json:"paths,omitempty"`
+	ValidatorsActive bool      `json:"validatorsActive"`
+	Backup           string    `json:"backup,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// Get the names of the Docker volumes that belong to the given project
+func (c *HyperdriveClient) GetProjectVolumes(projectName string) ([]string, error) {
+	list, err := c.listProjectVolumes(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make([]string, len(list))
+	for i, volume := range list {
+		volumes[i] = volume.Name
+	}
+	return volumes, nil
+}
+
+// Get the disk usage of the given file or directory, including files owned by the container runtime
+func (c *HyperdriveClient) GetDirectorySize(path string) (int64, error) {
+	rootCmd, err := c.getRuntimeEscalationCommand()
+	if err != nil {
+		return 0, fmt.Errorf("could not get privilege escalation command: %w", err)
+	}
+	output, err := c.readOutput(fmt.Sprintf("%s du -sb %s", rootCmd, shellescape.Quote(path)))
+	if err != nil {
+		return 0, fmt.Errorf("error getting the size of [%s]: %w", path, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected output getting the size of [%s]: %s", path, string(output))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing the size of [%s]: %w", path, err)
+	}
+	return size, nil
+}
+
+// Get the validator client containers of the given project that are currently running
+func (c *HyperdriveClient) GetRunningValidatorContainers(projectName string) ([]string, error) {
+	vcs, err := c.GetValidatorContainers(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator client containers: %w", err)
+	}
+
+	running := []string{}
+	for _, vc := range vcs {
+		status, err := c.GetDockerStatus(vc)
+		if err != nil {
+			return nil, fmt.Errorf("error getting status of [%s]: %w", vc, err)
+		}
+		if status == "running" {
+			running = append(running, vc)
+		}
+	}
+	return running, nil
+}
+
+// Append an entry to the audit log of destructive operations
+func WriteAuditEntry(entry AuditEntry) error {
+	if entry.User == "" {
+		entry.User = getAuditUser()
+	}
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error serializing audit entry: %w", err)
+	}
+
+	path, err := homedir.Expand(AuditLogPath)
+	if err != nil {
+		return fmt.Errorf("error expanding audit log path: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening audit log [%s]: %w", path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(bytes, '\n'))
+	if err != nil {
+		return fmt.Errorf("error writing to audit log [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Get the name of the user running the command, including the original user if it's being run with sudo
+func getAuditUser() string {
+	name := "unknown"
+	current, err := user.Current()
+	if err == nil {
+		name = current.Username
+	}
+	sudoUser := os.Getenv("SUDO_USER")
+	if sudoUser != "" && sudoUser != name {
+		name = fmt.Sprintf("%s (as %s)", sudoUser, name)
+	}
+	return name
+}
+
+// Get the Docker volumes that belong to the given project
+func (c *HyperdriveClient) listProjectVolumes(projectName string) ([]*volume.Volume, error) {
+	d, err := c.GetDocker()
+	if err != nil {
+		return nil, err
+	}
+	list, err := d.VolumeList(context.Background(), volume.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", composeProjectLabel, projectName))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting volume list: %w", err)
+	}
+	return list.Volumes, nil
+}
@@ -1,13 +1,21 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	dt "github.com/docker/docker/api/types"
 	dtc "github.com/docker/docker/api/types/container"
+	dti "github.com/docker/docker/api/types/image"
+	dmt "github.com/docker/docker/api/types/mount"
+	dtn "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/nodeset-org/hyperdrive-daemon/shared/config"
 )
 
@@ -129,9 +137,30 @@ func (c *HyperdriveClient) GetClientVolumeSource(containerName string, volumeTar
 
 	// Find the mount with the provided destination
 	for _, mount := range ci.Mounts {
-		if mount.Destination == volumeTarget {
+		if mount.Destination != volumeTarget {
+			continue
+		}
+		if mount.Type != dmt.TypeVolume {
 			return mount.Source, nil
 		}
+
+		// Rootless runtimes can report a volume's source from inside their own mount namespace, so ask for the volume's mountpoint instead
+		cfg, _, err := c.LoadConfig()
+		if err != nil {
+			return "", fmt.Errorf("error loading user settings: %w", err)
+		}
+		if !cfg.Cli.IsRootless() {
+			return mount.Source, nil
+		}
+		d, err := c.GetDocker()
+		if err != nil {
+			return "", err
+		}
+		volume, err := d.VolumeInspect(context.Background(), mount.Name)
+		if err != nil {
+			return "", fmt.Errorf("error inspecting volume [%s]: %w", mount.Name, err)
+		}
+		return volume.Mountpoint, nil
 	}
 	return "", fmt.Errorf("container [%s] doesn't have a volume with [%s] as a destination", containerName, volumeTarget)
 }
@@ -171,6 +200,146 @@ func (c *HyperdriveClient) GetVolumeSize(volumeName string) (int64, error) {
 	return 0, fmt.Errorf("couldn't find a volume named [%s]", volumeName)
 }
 
+// Replace an existing container with a copy of it under a new name, without any of its host port bindings.
+// The copy keeps the source's image, command, environment (with the provided overrides), and mounts, and is attached to the given network.
+// The source is stopped and removed before the copy starts, so the two never have the same data open at the same time.
+func (c *HyperdriveClient) CloneContainer(sourceName string, cloneName string, networkName string, envOverrides map[string]string) error {
+	d, err := c.GetDocker()
+	if err != nil {
+		return err
+	}
+	ci, err := inspectContainer(c, sourceName)
+	if err != nil {
+		return err
+	}
+
+	// Apply the environment overrides
+	env := []string{}
+	for _, entry := range ci.Config.Env {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, exists := envOverrides[key]; exists {
+			continue
+		}
+		env = append(env, entry)
+	}
+	for key, value := range envOverrides {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	// Copy the config without any exposed ports so it doesn't collide with the source's replacement
+	config := *ci.Config
+	config.Env = env
+	config.ExposedPorts = nil
+
+	// Drop the compose labels so compose doesn't treat the copy as part of the project
+	labels := map[string]string{}
+	for key, value := range ci.Config.Labels {
+		if !strings.HasPrefix(key, "com.docker.compose.") {
+			labels[key] = value
+		}
+	}
+	config.Labels = labels
+	hostConfig := *ci.HostConfig
+	hostConfig.PortBindings = nil
+	hostConfig.NetworkMode = dtc.NetworkMode(networkName)
+	networkingConfig := &dtn.NetworkingConfig{
+		EndpointsConfig: map[string]*dtn.EndpointSettings{
+			networkName: {},
+		},
+	}
+
+	// Stop the source with its own grace period so its database is closed cleanly, then remove it
+	err = d.ContainerStop(context.Background(), sourceName, dtc.StopOptions{})
+	if err != nil {
+		return fmt.Errorf("error stopping container [%s]: %w", sourceName, err)
+	}
+	err = d.ContainerRemove(context.Background(), sourceName, dtc.RemoveOptions{})
+	if err != nil {
+		return fmt.Errorf("error removing container [%s]: %w", sourceName, err)
+	}
+
+	_, err = d.ContainerCreate(context.Background(), &config, &hostConfig, networkingConfig, nil, cloneName)
+	if err != nil {
+		return fmt.Errorf("error creating container [%s] from [%s]: %w", cloneName, sourceName, err)
+	}
+	return d.ContainerStart(context.Background(), cloneName, dtc.StartOptions{})
+}
+
+// Deletes a folder inside of a client's data volume, using the given container which must be running and have the volume mounted
+func (c *HyperdriveClient) DeleteClientDataFolder(containerName string, volumeTarget string, folder string) error {
+	if folder == "" || strings.Contains(folder, "/") || strings.Contains(folder, "..") {
+		return fmt.Errorf("invalid client data folder [%s]", folder)
+	}
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("%s exec %s rm -rf %s/%s", runtimeCmd, containerName, volumeTarget, folder)
+	output, err := c.readOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("error deleting folder [%s] from container [%s]: %w", folder, containerName, err)
+	}
+	outputString := strings.TrimSpace(string(output))
+	if outputString != "" {
+		return fmt.Errorf("unexpected output deleting folder [%s] from container [%s]: %s", folder, containerName, outputString)
+	}
+	return nil
+}
+
+// Gets the root directory Docker stores its data (including volumes) in
+func (c *HyperdriveClient) GetDockerRootDir() (string, error) {
+	d, err := c.GetDocker()
+	if err != nil {
+		return "", err
+	}
+	info, err := d.Info(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("error getting Docker info: %w", err)
+	}
+	return info.DockerRootDir, nil
+}
+
+// Get the IP address of a container on its first attached network
+func (c *HyperdriveClient) GetContainerIP(containerName string) (string, error) {
+	ci, err := inspectContainer(c, containerName)
+	if err != nil {
+		return "", err
+	}
+	if ci.NetworkSettings != nil {
+		for _, network := range ci.NetworkSettings.Networks {
+			if network.IPAddress != "" {
+				return network.IPAddress, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("container [%s] doesn't have an IP address", containerName)
+}
+
+// Get the most recent lines of a container's logs, combining stdout and stderr
+func (c *HyperdriveClient) GetContainerLogs(containerName string, tail string) (string, error) {
+	d, err := c.GetDocker()
+	if err != nil {
+		return "", err
+	}
+	reader, err := d.ContainerLogs(context.Background(), containerName, dtc.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       tail,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error getting logs for container [%s]: %w", containerName, err)
+	}
+	defer reader.Close()
+
+	// Hyperdrive's containers don't use a TTY, so the stream is multiplexed
+	var logs bytes.Buffer
+	_, err = stdcopy.StdCopy(&logs, &logs, reader)
+	if err != nil {
+		return "", fmt.Errorf("error reading logs for container [%s]: %w", containerName, err)
+	}
+	return logs.String(), nil
+}
+
 // Inspect a Docker container
 func inspectContainer(c *HyperdriveClient, container string) (dt.ContainerJSON, error) {
 	d, err := c.GetDocker()
@@ -183,3 +352,81 @@ func inspectContainer(c *HyperdriveClient, container string) (dt.ContainerJSON,
 	}
 	return ci, nil
 }
+
+// Pull a Docker image
+func (c *HyperdriveClient) PullImage(image string) error {
+	d, err := c.GetDocker()
+	if err != nil {
+		return err
+	}
+	reader, err := d.ImagePull(context.Background(), image, dti.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("error pulling image [%s]: %w", image, err)
+	}
+	defer reader.Close()
+	err = readDockerJsonStream(reader)
+	if err != nil {
+		return fmt.Errorf("error pulling image [%s]: %w", image, err)
+	}
+	return nil
+}
+
+// Save Docker images to a tar archive in the format used by `docker save`
+func (c *HyperdriveClient) SaveImages(images []string, output io.Writer) error {
+	d, err := c.GetDocker()
+	if err != nil {
+		return err
+	}
+	reader, err := d.ImageSave(context.Background(), images)
+	if err != nil {
+		return fmt.Errorf("error saving images: %w", err)
+	}
+	defer reader.Close()
+	_, err = io.Copy(output, reader)
+	if err != nil {
+		return fmt.Errorf("error saving images: %w", err)
+	}
+	return nil
+}
+
+// Load Docker images from a tar archive created by `docker save`
+func (c *HyperdriveClient) LoadImages(input io.Reader) error {
+	d, err := c.GetDocker()
+	if err != nil {
+		return err
+	}
+	response, err := d.ImageLoad(context.Background(), input, true)
+	if err != nil {
+		return fmt.Errorf("error loading images: %w", err)
+	}
+	defer response.Body.Close()
+	if !response.JSON {
+		_, err = io.Copy(io.Discard, response.Body)
+		return err
+	}
+	err = readDockerJsonStream(response.Body)
+	if err != nil {
+		return fmt.Errorf("error loading images: %w", err)
+	}
+	return nil
+}
+
+// Read a JSON message stream from the Docker daemon to completion, returning the first error it reports
+func readDockerJsonStream(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	for {
+		var message struct {
+			Error string `json:"error"`
+		}
+		err := decoder.Decode(&message)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading Docker response: %w", err)
+		}
+		if message.Error != "" {
+			return errors.New(message.Error)
+		}
+	}
+}
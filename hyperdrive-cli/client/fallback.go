@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	fallbackProbeTimeout time.Duration = 10 * time.Second
+
+	nodeVersionPath string = "/eth/v1/node/version"
+	nodeSyncingPath string = "/eth/v1/node/syncing"
+)
+
+// The result of probing a fallback client
+type FallbackProbeResult struct {
+	// The URL that was probed
+	Url string
+
+	// True if the client responded to requests
+	IsReachable bool
+
+	// The round-trip time of a simple request to the client
+	Latency time.Duration
+
+	// True if the client is on the expected network
+	NetworkMatches bool
+
+	// A description of the client's network
+	NetworkDetails string
+
+	// True if the client is synced
+	IsSynced bool
+
+	// A description of the client's sync state
+	SyncDetails string
+
+	// True if the client can be used by the selected Validator Client
+	IsCompatible bool
+
+	// A description of the compatibility check
+	CompatibilityDetails string
+
+	// Any error that occurred while probing the client
+	Error error
+}
+
+// Check if the daemon is using its fallback client, based on its client manager's status.
+// The daemon only uses the fallback while its primary client is down or not synced and the fallback is working and synced.
+func IsUsingFallbackClient(status *types.ClientManagerStatus) bool {
+	if !status.FallbackEnabled {
+		return false
+	}
+	primary := status.PrimaryClientStatus
+	fallback := status.FallbackClientStatus
+	primaryReady := primary.IsWorking && primary.IsSynced && primary.Error == ""
+	fallbackReady := fallback.IsWorking && fallback.IsSynced && fallback.Error == ""
+	return !primaryReady && fallbackReady
+}
+
+// Probe a fallback Execution Client for reachability, network, and sync state
+func ProbeFallbackExecutionClient(ecUrl string, resources *config.NetworkResources) *FallbackProbeResult {
+	result := &FallbackProbeResult{
+		Url:          ecUrl,
+		IsCompatible: true,
+	}
+	httpClient := &http.Client{
+		Timeout: fallbackProbeTimeout,
+	}
+
+	// Check the chain ID, timing it for the latency
+	var chainID struct {
+		Result hexutil.Uint64 `json:"result"`
+	}
+	start := time.Now()
+	err := PostJsonRpc(httpClient, ecUrl, "eth_chainId", &chainID)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Latency = time.Since(start)
+	result.IsReachable = true
+	result.NetworkMatches = uint(chainID.Result) == resources.ChainID
+	result.NetworkDetails = fmt.Sprintf("chain ID %d", chainID.Result)
+	if !result.NetworkMatches {
+		result.NetworkDetails += fmt.Sprintf(", expected %d", resources.ChainID)
+	}
+
+	// Check the sync state; eth_syncing returns false once the client is synced
+	var syncing struct {
+		Result json.RawMessage `json:"result"`
+	}
+	err = PostJsonRpc(httpClient, ecUrl, "eth_syncing", &syncing)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	var isSyncing bool
+	if json.Unmarshal(syncing.Result, &isSyncing) == nil && !isSyncing {
+		result.IsSynced = true
+		result.SyncDetails = "synced"
+		return result
+	}
+	var progress struct {
+		CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+		HighestBlock hexutil.Uint64 `json:"highestBlock"`
+	}
+	err = json.Unmarshal(syncing.Result, &progress)
+	if err != nil {
+		result.Error = fmt.Errorf("error deserializing eth_syncing response: %w", err)
+		return result
+	}
+	result.SyncDetails = fmt.Sprintf("syncing (block %d of %d)", progress.CurrentBlock, progress.HighestBlock)
+	return result
+}
+
+// Probe a fallback Beacon Node for reachability, network, sync state, and compatibility with the selected Validator Client.
+// Prysm's Validator Client connects over gRPC instead of the Beacon API, so its RPC URL is checked as well.
+func ProbeFallbackBeaconNode(bnUrl string, prysmRpcUrl string, vcType config.BeaconNode, resources *config.NetworkResources) *FallbackProbeResult {
+	result := &FallbackProbeResult{
+		Url: bnUrl,
+	}
+	httpClient := &http.Client{
+		Timeout: fallbackProbeTimeout,
+	}
+
+	// Get the version, timing it for the latency
+	var version struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	start := time.Now()
+	err := getBeaconApiResponse(httpClient, bnUrl, nodeVersionPath, &version)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Latency = time.Since(start)
+	result.IsReachable = true
+
+	// Check the network
+	result.NetworkMatches, result.NetworkDetails, err = checkProviderNetwork(httpClient, bnUrl, resources)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if result.NetworkMatches {
+		result.NetworkDetails = fmt.Sprintf("chain ID %d", resources.ChainID)
+	}
+
+	// Check the sync state
+	var syncing struct {
+		Data struct {
+			HeadSlot     string `json:"head_slot"`
+			SyncDistance string `json:"sync_distance"`
+			IsSyncing    bool   `json:"is_syncing"`
+		} `json:"data"`
+	}
+	err = getBeaconApiResponse(httpClient, bnUrl, nodeSyncingPath, &syncing)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.IsSynced = !syncing.Data.IsSyncing
+	if result.IsSynced {
+		result.SyncDetails = fmt.Sprintf("synced (head slot %s)", syncing.Data.HeadSlot)
+	} else {
+		result.SyncDetails = fmt.Sprintf("syncing (head slot %s, %s slots behind)", syncing.Data.HeadSlot, syncing.Data.SyncDistance)
+	}
+
+	// Check compatibility with the VC
+	if vcType != config.BeaconNode_Prysm {
+		result.IsCompatible = true
+		result.CompatibilityDetails = fmt.Sprintf("Beacon API is available (%s)", version.Data.Version)
+		return result
+	}
+	if prysmRpcUrl == "" {
+		result.CompatibilityDetails = "your Validator Client is Prysm, which needs a gRPC URL for the fallback Beacon Node, but none is configured"
+		return result
+	}
+	err = checkTcpEndpoint(prysmRpcUrl)
+	if err != nil {
+		result.CompatibilityDetails = fmt.Sprintf("your Validator Client is Prysm, but the fallback gRPC endpoint %s isn't reachable: %s", prysmRpcUrl, err.Error())
+		return result
+	}
+	result.IsCompatible = true
+	result.CompatibilityDetails = fmt.Sprintf("Prysm gRPC endpoint %s is reachable", prysmRpcUrl)
+	return result
+}
+
+// Make sure a TCP connection can be opened to the host and port of a URL
+func checkTcpEndpoint(endpoint string) error {
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		parsedUrl, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("error parsing URL: %w", err)
+		}
+		host = parsedUrl.Host
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		return fmt.Errorf("URL doesn't have a port")
+	}
+
+	conn, err := net.DialTimeout("tcp", host, fallbackProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Run a JSON-RPC request with no parameters against an Execution Client
+func PostJsonRpc(httpClient *http.Client, ecUrl string, method string, result any) error {
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","method":"%s","params":[],"id":1}`, method)
+	resp, err := httpClient.Post(ecUrl, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		return fmt.Errorf("error running %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected http status running %s: %d", method, resp.StatusCode)
+	}
+	err = json.Unmarshal(responseBody, result)
+	if err != nil {
+		return fmt.Errorf("error deserializing %s response: %w", method, err)
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 type GlobalConfig struct {
 	Hyperdrive *hdconfig.HyperdriveConfig
 	Stakewise  *swconfig.StakewiseConfig
+	Cli        *CliConfig
 }
 
 // Make a new global config
@@ -20,8 +21,10 @@ func NewGlobalConfig(hdCfg *hdconfig.HyperdriveConfig) *GlobalConfig {
 	cfg := &GlobalConfig{
 		Hyperdrive: hdCfg,
 		Stakewise:  swconfig.NewStakewiseConfig(hdCfg),
+		Cli:        NewCliConfig(),
 	}
 
+	config.ApplyDefaults(cfg.Cli, hdCfg.Network.Value)
 	for _, module := range cfg.GetAllModuleConfigs() {
 		config.ApplyDefaults(module, hdCfg.Network.Value)
 	}
@@ -37,7 +40,9 @@ func (c *GlobalConfig) GetAllModuleConfigs() []hdconfig.IModuleConfig {
 
 // Serialize the config and all modules
 func (c *GlobalConfig) Serialize() map[string]any {
-	return c.Hyperdrive.Serialize(c.GetAllModuleConfigs(), false)
+	masterMap := c.Hyperdrive.Serialize(c.GetAllModuleConfigs(), false)
+	masterMap[CliConfigName] = config.Serialize(c.Cli)
+	return masterMap
 }
 
 // Deserialize the config's modules (assumes the Hyperdrive config itself has already been deserialized)
@@ -66,9 +71,13 @@ func (c *GlobalConfig) CreateCopy() *GlobalConfig {
 	// Stakewise
 	swCopy := c.Stakewise.Clone().(*swconfig.StakewiseConfig)
 
+	// CLI
+	cliCopy := c.Cli.Clone()
+
 	return &GlobalConfig{
 		Hyperdrive: hdCopy,
 		Stakewise:  swCopy,
+		Cli:        cliCopy,
 	}
 }
 
@@ -132,8 +141,24 @@ func (c *GlobalConfig) Validate() []string {
 	portMap, errors = addAndCheckForDuplicate(portMap, c.Hyperdrive.Metrics.ExporterMetricsPort, errors)
 	portMap, errors = addAndCheckForDuplicate(portMap, c.Hyperdrive.Metrics.Grafana.Port, errors)
 	portMap, errors = addAndCheckForDuplicate(portMap, c.Hyperdrive.Metrics.DaemonMetricsPort, errors)
+	if c.Hyperdrive.Metrics.EnableMetrics.Value && c.Cli.Alerting.EnableAlerting.Value {
+		portMap, errors = addAndCheckForDuplicate(portMap, c.Cli.Alerting.Port, errors)
+	}
+	if c.Cli.MevBoost.IsLocal() {
+		portMap, errors = addAndCheckForDuplicate(portMap, c.Cli.MevBoost.Port, errors)
+		if c.Hyperdrive.Metrics.EnableMetrics.Value {
+			portMap, errors = addAndCheckForDuplicate(portMap, c.Cli.MevBoost.MetricsPort, errors)
+		}
+	}
 	_, errors = addAndCheckForDuplicate(portMap, c.Hyperdrive.LocalBeaconClient.Lighthouse.P2pQuicPort, errors)
 
+	// Make sure the backup destinations, alert receivers, notification webhooks, MEV relays, and container limits are complete
+	errors = append(errors, c.Cli.Backup.Validate()...)
+	errors = append(errors, c.Cli.Alerting.Validate()...)
+	errors = append(errors, c.Cli.Notifications.Validate()...)
+	errors = append(errors, c.Cli.MevBoost.Validate(c.Hyperdrive.Network.Value)...)
+	errors = append(errors, c.Cli.Resources.Validate()...)
+
 	return errors
 }
 
@@ -145,6 +170,7 @@ func (c *GlobalConfig) GetChanges(oldConfig *GlobalConfig) ([]*config.ChangedSec
 	// Process all configs for changes
 	sectionList = getChanges(oldConfig.Hyperdrive, c.Hyperdrive, sectionList, changedContainers)
 	sectionList = getChanges(oldConfig.Stakewise, c.Stakewise, sectionList, changedContainers)
+	sectionList = getChanges(oldConfig.Cli, c.Cli, sectionList, changedContainers)
 
 	// Add all VCs to the list of changed containers if any change requires a VC change
 	if changedContainers[config.ContainerID_ValidatorClient] {
@@ -0,0 +1,384 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	swconfig "github.com/nodeset-org/hyperdrive-stakewise/shared/config"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	mevBoostConfigID          string = "mevBoost"
+	mevBoostEnableID          string = "enableMevBoost"
+	mevBoostModeID            string = "mode"
+	mevBoostContainerTagID    string = "containerTag"
+	mevBoostPortID            string = "port"
+	mevBoostMetricsPortID     string = "metricsPort"
+	mevBoostFlashbotsID       string = "flashbotsRelay"
+	mevBoostBloxrouteMaxID    string = "bloxrouteMaxProfitRelay"
+	mevBoostBloxrouteRegID    string = "bloxrouteRegulatedRelay"
+	mevBoostUltrasoundID      string = "ultrasoundRelay"
+	mevBoostAestusID          string = "aestusRelay"
+	mevBoostTitanID           string = "titanRelay"
+	mevBoostAgnosticID        string = "agnosticRelay"
+	mevBoostCustomRelaysID    string = "customRelays"
+	mevBoostExternalUrlID     string = "externalUrl"
+	mevBoostAdditionalFlagsID string = "additionalFlags"
+
+	mevBoostTag string = "flashbots/mev-boost:1.9"
+)
+
+// A MEV relay that can be selected, along with its URL on each network it supports
+type mevRelay struct {
+	// The relay's parameter
+	parameter *config.Parameter[bool]
+
+	// The relay's URL on each network, including its public key
+	urls map[config.Network]string
+}
+
+// Settings for MEV-Boost, which lets the Beacon Node get blocks from MEV relays when one of your validators proposes.
+// The daemon doesn't know about it, so it lives in the CLI's section.
+type MevBoostConfig struct {
+	// True to have the Beacon Node and Validator Clients use MEV-Boost
+	Enable config.Parameter[bool]
+
+	// Whether to run the MEV-Boost container or use one that's already running
+	Mode config.Parameter[config.ClientMode]
+
+	// The MEV-Boost container tag
+	ContainerTag config.Parameter[string]
+
+	// The port MEV-Boost listens on
+	Port config.Parameter[uint16]
+
+	// The port MEV-Boost serves its metrics on
+	MetricsPort config.Parameter[uint16]
+
+	// The relays to use
+	FlashbotsRelay          config.Parameter[bool]
+	BloxrouteMaxProfitRelay config.Parameter[bool]
+	BloxrouteRegulatedRelay config.Parameter[bool]
+	UltrasoundRelay         config.Parameter[bool]
+	AestusRelay             config.Parameter[bool]
+	TitanRelay              config.Parameter[bool]
+	AgnosticRelay           config.Parameter[bool]
+
+	// Extra relay URLs, separated by commas
+	CustomRelays config.Parameter[string]
+
+	// The URL of an externally managed MEV-Boost instance
+	ExternalUrl config.Parameter[string]
+
+	// Custom command line flags for MEV-Boost
+	AdditionalFlags config.Parameter[string]
+}
+
+// Generates a new MEV-Boost config
+func NewMevBoostConfig() *MevBoostConfig {
+	return &MevBoostConfig{
+		Enable: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostEnableID,
+				Name:               "Enable MEV-Boost",
+				Description:        "Have your Beacon Node get blocks from MEV relays through MEV-Boost when one of your validators proposes, and have your Validator Clients register with them. Blocks from relays usually pay a much larger priority fee than the ones your Execution Client builds itself.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost, config.ContainerID_BeaconNode, swconfig.ContainerID_StakewiseValidator, config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: false,
+			},
+		},
+
+		Mode: config.Parameter[config.ClientMode]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostModeID,
+				Name:               "MEV-Boost Mode",
+				Description:        "Choose whether Hyperdrive runs MEV-Boost for you, or you'd like to use an instance you already run yourself.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost, config.ContainerID_BeaconNode, config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Options: []*config.ParameterOption[config.ClientMode]{
+				{
+					ParameterOptionCommon: &config.ParameterOptionCommon{
+						Name:        "Locally Managed",
+						Description: "Run MEV-Boost in a container alongside your Beacon Node.\n\nIt only listens on this machine, so an externally managed Beacon Node can only use it if it runs on this machine too and you point it at MEV-Boost's port yourself. If your Beacon Node runs somewhere else, run MEV-Boost next to it and choose Externally Managed instead.",
+					},
+					Value: config.ClientMode_Local,
+				}, {
+					ParameterOptionCommon: &config.ParameterOptionCommon{
+						Name:        "Externally Managed",
+						Description: "Use a MEV-Boost instance that you run yourself.",
+					},
+					Value: config.ClientMode_External,
+				},
+			},
+			Default: map[config.Network]config.ClientMode{
+				config.Network_All: config.ClientMode_Local,
+			},
+		},
+
+		ContainerTag: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostContainerTagID,
+				Name:               "Container Tag",
+				Description:        "The tag name of the MEV-Boost container you want to use on Docker Hub.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: true,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: mevBoostTag,
+			},
+		},
+
+		Port: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostPortID,
+				Name:               "MEV-Boost Port",
+				Description:        "The port MEV-Boost listens on for your Beacon Node. It's only reachable from this machine and Hyperdrive's containers.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost, config.ContainerID_BeaconNode},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 18550,
+			},
+		},
+
+		MetricsPort: config.Parameter[uint16]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostMetricsPortID,
+				Name:               "MEV-Boost Metrics Port",
+				Description:        "The port MEV-Boost serves its metrics on for Prometheus, if metrics are enabled.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost, config.ContainerID_Prometheus},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint16{
+				config.Network_All: 18551,
+			},
+		},
+
+		FlashbotsRelay:          newMevRelayParameter(mevBoostFlashbotsID, "Flashbots", "Flashbots is the developer of MEV-Boost and one of the best-known relays. It only relays blocks that comply with OFAC sanctions."),
+		BloxrouteMaxProfitRelay: newMevRelayParameter(mevBoostBloxrouteMaxID, "bloXroute Max Profit", "The bloXroute Max Profit relay doesn't filter any transactions."),
+		BloxrouteRegulatedRelay: newMevRelayParameter(mevBoostBloxrouteRegID, "bloXroute Regulated", "The bloXroute Regulated relay only relays blocks that comply with OFAC sanctions."),
+		UltrasoundRelay:         newMevRelayParameter(mevBoostUltrasoundID, "Ultra Sound", "The Ultra Sound relay doesn't filter any transactions."),
+		AestusRelay:             newMevRelayParameter(mevBoostAestusID, "Aestus", "The Aestus relay doesn't filter any transactions."),
+		TitanRelay:              newMevRelayParameter(mevBoostTitanID, "Titan", "The Titan relay doesn't filter any transactions."),
+		AgnosticRelay:           newMevRelayParameter(mevBoostAgnosticID, "Agnostic Gnosis", "The Agnostic Gnosis relay doesn't filter any transactions."),
+
+		CustomRelays: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostCustomRelaysID,
+				Name:               "Custom Relays",
+				Description:        "Any other relays you'd like to use, separated by commas. Each one must include the relay's public key, such as `https://0xabc...@relay.example.com`.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		ExternalUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostExternalUrlID,
+				Name:               "External URL",
+				Description:        "The URL of your MEV-Boost instance, such as `http://192.168.1.40:18550`. Your Beacon Node must be able to reach it.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_BeaconNode},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		AdditionalFlags: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 mevBoostAdditionalFlagsID,
+				Name:               "Additional Flags",
+				Description:        "Additional custom command line flags you want to pass to MEV-Boost, to take advantage of other settings that Hyperdrive's configuration doesn't cover.",
+				AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+	}
+}
+
+// Create the parameter for selecting a MEV relay
+func newMevRelayParameter(id string, name string, description string) config.Parameter[bool] {
+	return config.Parameter[bool]{
+		ParameterCommon: &config.ParameterCommon{
+			ID:                 id,
+			Name:               fmt.Sprintf("Enable %s", name),
+			Description:        description,
+			AffectsContainers:  []config.ContainerID{config.ContainerID_MevBoost},
+			CanBeBlank:         false,
+			OverwriteOnUpgrade: false,
+		},
+		Default: map[config.Network]bool{
+			config.Network_All: false,
+		},
+	}
+}
+
+// The title for the config
+func (cfg *MevBoostConfig) GetTitle() string {
+	return "MEV-Boost"
+}
+
+// Get the parameters for this config
+func (cfg *MevBoostConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.Enable,
+		&cfg.Mode,
+		&cfg.ContainerTag,
+		&cfg.Port,
+		&cfg.MetricsPort,
+		&cfg.FlashbotsRelay,
+		&cfg.BloxrouteMaxProfitRelay,
+		&cfg.BloxrouteRegulatedRelay,
+		&cfg.UltrasoundRelay,
+		&cfg.AestusRelay,
+		&cfg.TitanRelay,
+		&cfg.AgnosticRelay,
+		&cfg.CustomRelays,
+		&cfg.ExternalUrl,
+		&cfg.AdditionalFlags,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *MevBoostConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
+
+// Check if Hyperdrive should run the MEV-Boost container
+func (cfg *MevBoostConfig) IsLocal() bool {
+	return cfg.Enable.Value && cfg.Mode.Value == config.ClientMode_Local
+}
+
+// Get the parameters of the relays that support the given network
+func (cfg *MevBoostConfig) GetAvailableRelays(network config.Network) []*config.Parameter[bool] {
+	params := []*config.Parameter[bool]{}
+	for _, relay := range cfg.getRelays() {
+		if relay.urls[network] != "" {
+			params = append(params, relay.parameter)
+		}
+	}
+	return params
+}
+
+// Get the URLs of the selected and custom relays for the given network
+func (cfg *MevBoostConfig) GetRelayUrls(network config.Network) []string {
+	urls := []string{}
+	for _, relay := range cfg.getRelays() {
+		relayUrl := relay.urls[network]
+		if relay.parameter.Value && relayUrl != "" {
+			urls = append(urls, relayUrl)
+		}
+	}
+	return append(urls, cfg.getCustomRelays()...)
+}
+
+// Checks to see if the MEV-Boost settings are valid for the given network; if not, returns a list of errors
+func (cfg *MevBoostConfig) Validate(network config.Network) []string {
+	errors := []string{}
+	if !cfg.Enable.Value {
+		return errors
+	}
+	if cfg.Mode.Value == config.ClientMode_External {
+		externalUrl, err := url.Parse(cfg.ExternalUrl.Value)
+		if err != nil || (externalUrl.Scheme != "http" && externalUrl.Scheme != "https") || externalUrl.Host == "" {
+			errors = append(errors, fmt.Sprintf("[%s - %s] must be an http or https URL.", cfg.GetTitle(), cfg.ExternalUrl.Name))
+		}
+		return errors
+	}
+
+	for _, relay := range cfg.getCustomRelays() {
+		relayUrl, err := url.Parse(relay)
+		if err != nil || (relayUrl.Scheme != "http" && relayUrl.Scheme != "https") || relayUrl.Host == "" || relayUrl.User.Username() == "" {
+			errors = append(errors, fmt.Sprintf("[%s - %s] has an invalid relay [%s]; relays must be http or https URLs that include the relay's public key, such as `https://0xabc...@relay.example.com`.", cfg.GetTitle(), cfg.CustomRelays.Name, relay))
+		}
+	}
+	if len(cfg.GetRelayUrls(network)) == 0 {
+		errors = append(errors, fmt.Sprintf("[%s] needs at least one relay; please enable one or add a custom relay.", cfg.GetTitle()))
+	}
+	return errors
+}
+
+// Get the custom relays, without any blanks
+func (cfg *MevBoostConfig) getCustomRelays() []string {
+	relays := []string{}
+	for _, relay := range strings.Split(cfg.CustomRelays.Value, ",") {
+		relay = strings.TrimSpace(relay)
+		if relay != "" {
+			relays = append(relays, relay)
+		}
+	}
+	return relays
+}
+
+// Get the relays Hyperdrive knows about, along with their URLs on each network
+func (cfg *MevBoostConfig) getRelays() []mevRelay {
+	return []mevRelay{
+		{
+			parameter: &cfg.FlashbotsRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet:      "https://0xac6e77dfe25ecd6110b8e780608cce0dab71fdd5ebea22a16c0205200f2f8e2e3ad3b71d3499c54ad14d6c21b41a37ae@boost-relay.flashbots.net",
+				config.Network_Holesky:      "https://0xafa4c6985aa049fb79dd37010438cfebeb0f2bd42b115b89dd678dab0670c1de38da0c4e9138c9290a398ecd9a0b3110@boost-relay-holesky.flashbots.net",
+				hdconfig.Network_HoleskyDev: "https://0xafa4c6985aa049fb79dd37010438cfebeb0f2bd42b115b89dd678dab0670c1de38da0c4e9138c9290a398ecd9a0b3110@boost-relay-holesky.flashbots.net",
+			},
+		}, {
+			parameter: &cfg.BloxrouteMaxProfitRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet:      "https://0x8b5d2e73e2a3a55c6c87b8b6eb92e0149a125c852751db1422fa951e42a09b82c142c3ea98d0d9930b056a3bc9896b8f@bloxroute.max-profit.blxrbdn.com",
+				config.Network_Holesky:      "https://0x821f2a65afb70e7f2e820a925a9b4c80a159620582c1766b1b09729fec178b11ea22abb3a51f07b288be815a1a2ff516@bloxroute.holesky.blxrbdn.com",
+				hdconfig.Network_HoleskyDev: "https://0x821f2a65afb70e7f2e820a925a9b4c80a159620582c1766b1b09729fec178b11ea22abb3a51f07b288be815a1a2ff516@bloxroute.holesky.blxrbdn.com",
+			},
+		}, {
+			parameter: &cfg.BloxrouteRegulatedRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet: "https://0xb0b07cd0abef743db4260b0ed50619cf6ad4d82064cb4fbec9d3ec530f7c5e6793d9f286c4e082c0244ffb9f2658fe88@bloxroute.regulated.blxrbdn.com",
+			},
+		}, {
+			parameter: &cfg.UltrasoundRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet:      "https://0xa1559ace749633b997cb3fdacffb890aeebdb0f5a3b6aaa7eeeaf1a38af0a8fe88b9e4b1f61f236d2e64d95733327a62@relay.ultrasound.money",
+				config.Network_Holesky:      "https://0xb1559beef7b5ba3127485bbbb090362d9f497ba64e177ee2c8e7db74746306efad687f2cf8574e38d70067d40ef136dc@relay-stag.ultrasound.money",
+				hdconfig.Network_HoleskyDev: "https://0xb1559beef7b5ba3127485bbbb090362d9f497ba64e177ee2c8e7db74746306efad687f2cf8574e38d70067d40ef136dc@relay-stag.ultrasound.money",
+			},
+		}, {
+			parameter: &cfg.AestusRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet:      "https://0xa15b52576bcbf1072f4a011c0f99f9fb6c66f3e1ff321f11f461d15e31b1cb359caa092c71bbded0bae5b5ea401aab7e@aestus.live",
+				config.Network_Holesky:      "https://0xab78bf8c781c58078c3beb5710c57940874dd96aef2835e7742c866b4c7c0406754376c2c8285a36c630346aa5c5f833@holesky.aestus.live",
+				hdconfig.Network_HoleskyDev: "https://0xab78bf8c781c58078c3beb5710c57940874dd96aef2835e7742c866b4c7c0406754376c2c8285a36c630346aa5c5f833@holesky.aestus.live",
+			},
+		}, {
+			parameter: &cfg.TitanRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet:      "https://0x8c4ed5e24fe5c6ae21018437bde147693f68cda427cd1122cf20819c30eda7ed74f72dece09bb313f2a1855595ab677d@global.titanrelay.xyz",
+				config.Network_Holesky:      "https://0xaa58208899c6105603b74396734a6263cc7d947f444f396a90f7b7d3e65d102aec7e5e5291b27e08d02c50a050825c2f@holesky.titanrelay.xyz",
+				hdconfig.Network_HoleskyDev: "https://0xaa58208899c6105603b74396734a6263cc7d947f444f396a90f7b7d3e65d102aec7e5e5291b27e08d02c50a050825c2f@holesky.titanrelay.xyz",
+			},
+		}, {
+			parameter: &cfg.AgnosticRelay,
+			urls: map[config.Network]string{
+				config.Network_Mainnet: "https://0xa7ab7a996c8584251c8f925da3170bdfd6ebc75d50f5ddc4050a6fdc77f2a3b5fce2cc750d0865e05d7228af97d69561@agnostic-relay.net",
+			},
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	notificationsConfigID       string = "notifications"
+	notificationsGenericUrlID   string = "genericWebhookUrl"
+	notificationsDiscordUrlID   string = "discordWebhookUrl"
+	notificationsSlackUrlID     string = "slackWebhookUrl"
+	notificationsNtfyUrlID      string = "ntfyUrl"
+	notificationsNtfyTokenID    string = "ntfyToken"
+	notificationsTransactionsID string = "notifyTransactions"
+)
+
+// Settings for the event notifications the CLI sends to webhooks
+type NotificationsConfig struct {
+	// The URL to send events to as Hyperdrive's own JSON payload
+	GenericWebhookUrl config.Parameter[string]
+
+	// The Discord webhook URL to send events to
+	DiscordWebhookUrl config.Parameter[string]
+
+	// The Slack incoming webhook URL to send events to
+	SlackWebhookUrl config.Parameter[string]
+
+	// The ntfy topic URL to send events to
+	NtfyUrl config.Parameter[string]
+
+	// The access token for the ntfy topic
+	NtfyToken config.Parameter[string]
+
+	// True to send an event for every transaction that's submitted, mined, or fails
+	NotifyTransactions config.Parameter[bool]
+}
+
+// Generates a new notifications config
+func NewNotificationsConfig() *NotificationsConfig {
+	return &NotificationsConfig{
+		GenericWebhookUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsGenericUrlID,
+				Name:               "Webhook URL",
+				Description:        "Send event notifications to this URL as JSON, with the event type, level, title, message, project name, time, and any details about the event. Leave this blank to not send them.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		DiscordWebhookUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsDiscordUrlID,
+				Name:               "Discord Webhook URL",
+				Description:        "Send event notifications to a Discord channel through this webhook URL. You can create one in the channel's Integrations settings. Leave this blank to not send them.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		SlackWebhookUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsSlackUrlID,
+				Name:               "Slack Webhook URL",
+				Description:        "Send event notifications to a Slack channel through this incoming webhook URL. Leave this blank to not send them.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		NtfyUrl: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsNtfyUrlID,
+				Name:               "ntfy Topic URL",
+				Description:        "Send event notifications to this ntfy topic, including the server, such as `https://ntfy.sh/my-node-alerts`. Anyone who knows a topic on the public server can read it, so pick a name that's hard to guess. Leave this blank to not send them.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		NtfyToken: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsNtfyTokenID,
+				Name:               "ntfy Access Token",
+				Description:        "The access token to publish to the ntfy topic with, if it's protected. Leave this blank if it isn't.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: "",
+			},
+		},
+
+		NotifyTransactions: config.Parameter[bool]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 notificationsTransactionsID,
+				Name:               "Notify Transactions",
+				Description:        "Send a notification whenever the CLI submits a transaction, and again when it's mined or fails. Failures are always sent.",
+				AffectsContainers:  []config.ContainerID{},
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]bool{
+				config.Network_All: true,
+			},
+		},
+	}
+}
+
+// The title for the config
+func (cfg *NotificationsConfig) GetTitle() string {
+	return "Notifications"
+}
+
+// Get the parameters for this config
+func (cfg *NotificationsConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.GenericWebhookUrl,
+		&cfg.DiscordWebhookUrl,
+		&cfg.SlackWebhookUrl,
+		&cfg.NtfyUrl,
+		&cfg.NtfyToken,
+		&cfg.NotifyTransactions,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *NotificationsConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
+
+// Get the webhooks that event notifications are sent to
+func (cfg *NotificationsConfig) GetTargets() []*WebhookTarget {
+	targets := []*WebhookTarget{}
+	if cfg.GenericWebhookUrl.Value != "" {
+		targets = append(targets, NewWebhookTarget(NotificationFormat_Generic, cfg.GenericWebhookUrl.Value, ""))
+	}
+	if cfg.DiscordWebhookUrl.Value != "" {
+		targets = append(targets, NewWebhookTarget(NotificationFormat_Discord, cfg.DiscordWebhookUrl.Value, ""))
+	}
+	if cfg.SlackWebhookUrl.Value != "" {
+		targets = append(targets, NewWebhookTarget(NotificationFormat_Slack, cfg.SlackWebhookUrl.Value, ""))
+	}
+	if cfg.NtfyUrl.Value != "" {
+		targets = append(targets, NewWebhookTarget(NotificationFormat_Ntfy, cfg.NtfyUrl.Value, cfg.NtfyToken.Value))
+	}
+	return targets
+}
+
+// Checks to see if the notification settings are valid; if not, returns a list of errors
+func (cfg *NotificationsConfig) Validate() []string {
+	errors := []string{}
+	for _, param := range []*config.Parameter[string]{&cfg.GenericWebhookUrl, &cfg.DiscordWebhookUrl, &cfg.SlackWebhookUrl, &cfg.NtfyUrl} {
+		if param.Value == "" {
+			continue
+		}
+		webhookUrl, err := url.Parse(param.Value)
+		if err != nil || (webhookUrl.Scheme != "http" && webhookUrl.Scheme != "https") || webhookUrl.Host == "" {
+			errors = append(errors, fmt.Sprintf("[%s - %s] must be an http or https URL.", cfg.GetTitle(), param.Name))
+		}
+	}
+	return errors
+}
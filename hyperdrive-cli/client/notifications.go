@@ -0,0 +1,359 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	NotificationStateFile string = "notification-state.json"
+
+	notificationTimeout time.Duration = 10 * time.Second
+
+	// How many restarts between two checks count as a restart loop, even if the container happens to be up when it's checked
+	restartLoopThreshold int = 3
+)
+
+// The kind of event a notification is for
+type NotificationEvent string
+
+const (
+	NotificationEvent_Test                 NotificationEvent = "test"
+	NotificationEvent_ServiceStarted       NotificationEvent = "service-started"
+	NotificationEvent_ServiceStopped       NotificationEvent = "service-stopped"
+	NotificationEvent_ContainerRestartLoop NotificationEvent = "container-restart-loop"
+	NotificationEvent_SlashingDelayStarted NotificationEvent = "slashing-delay-started"
+	NotificationEvent_SlashingDelayEnded   NotificationEvent = "slashing-delay-ended"
+	NotificationEvent_KeysGenerated        NotificationEvent = "keys-generated"
+	NotificationEvent_DepositDataUploaded  NotificationEvent = "deposit-data-uploaded"
+	NotificationEvent_ExitsBroadcast       NotificationEvent = "exits-broadcast"
+	NotificationEvent_RewardsClaimed       NotificationEvent = "rewards-claimed"
+	NotificationEvent_TxSubmitted          NotificationEvent = "tx-submitted"
+	NotificationEvent_TxMined              NotificationEvent = "tx-mined"
+	NotificationEvent_TxFailed             NotificationEvent = "tx-failed"
+)
+
+// How urgent a notification is
+type NotificationLevel string
+
+const (
+	NotificationLevel_Info     NotificationLevel = "info"
+	NotificationLevel_Warning  NotificationLevel = "warning"
+	NotificationLevel_Critical NotificationLevel = "critical"
+)
+
+// The payload format a webhook expects
+type NotificationFormat string
+
+const (
+	NotificationFormat_Generic NotificationFormat = "generic"
+	NotificationFormat_Discord NotificationFormat = "discord"
+	NotificationFormat_Slack   NotificationFormat = "slack"
+	NotificationFormat_Ntfy    NotificationFormat = "ntfy"
+)
+
+// All of the supported webhook formats
+var NotificationFormats []NotificationFormat = []NotificationFormat{
+	NotificationFormat_Generic,
+	NotificationFormat_Discord,
+	NotificationFormat_Slack,
+	NotificationFormat_Ntfy,
+}
+
+// An event sent to the notification webhooks. This is also the payload of the generic format.
+type Notification struct {
+	Event   NotificationEvent `json:"event"`
+	Level   NotificationLevel `json:"level"`
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Project string            `json:"project"`
+	Time    time.Time         `json:"time"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// A webhook that event notifications are sent to
+type WebhookTarget struct {
+	format NotificationFormat
+	url    string
+	token  string
+	client *http.Client
+}
+
+// The restart counts of the project's containers as of the last check, used to only notify once per restart loop
+type NotificationState struct {
+	Containers map[string]*ContainerRestartState `json:"containers"`
+}
+
+// The restart count of a container as of the last check
+type ContainerRestartState struct {
+	Restarts int  `json:"restarts"`
+	Looping  bool `json:"looping"`
+}
+
+// Create a new webhook target. The token is only used by ntfy.
+func NewWebhookTarget(format NotificationFormat, url string, token string) *WebhookTarget {
+	return &WebhookTarget{
+		format: format,
+		url:    url,
+		token:  token,
+		client: &http.Client{
+			Timeout: notificationTimeout,
+		},
+	}
+}
+
+// A description of the webhook for logging
+func (t *WebhookTarget) GetName() string {
+	return fmt.Sprintf("%s (%s)", t.format, t.url)
+}
+
+// Send a notification to the webhook
+func (t *WebhookTarget) Send(notification *Notification) error {
+	var body []byte
+	var err error
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	switch t.format {
+	case NotificationFormat_Generic:
+		body, err = json.Marshal(notification)
+	case NotificationFormat_Discord:
+		body, err = json.Marshal(createDiscordPayload(notification))
+	case NotificationFormat_Slack:
+		body, err = json.Marshal(createSlackPayload(notification))
+	case NotificationFormat_Ntfy:
+		// ntfy takes the message as the body and everything else as headers
+		body = []byte(notification.formatText(false))
+		headers = map[string]string{
+			"Content-Type": "text/plain",
+			"Title":        notification.Title,
+			"Priority":     getNtfyPriority(notification.Level),
+			"Tags":         fmt.Sprintf("%s,%s", notification.Level, notification.Event),
+		}
+		if t.token != "" {
+			headers["Authorization"] = "Bearer " + t.token
+		}
+	default:
+		return fmt.Errorf("unknown notification format [%s]", t.format)
+	}
+	if err != nil {
+		return fmt.Errorf("error serializing notification: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := t.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+	return nil
+}
+
+// Send a notification to all of the configured webhooks, filling in the project name and time.
+// Returns the webhooks it was sent to. A failure on one doesn't stop the others; all of them are joined in the returned error.
+func (c *HyperdriveClient) SendNotification(notification *Notification) ([]*WebhookTarget, error) {
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return []*WebhookTarget{}, nil
+	}
+	notificationsCfg := cfg.Cli.Notifications
+	if !notificationsCfg.NotifyTransactions.Value && (notification.Event == NotificationEvent_TxSubmitted || notification.Event == NotificationEvent_TxMined) {
+		return []*WebhookTarget{}, nil
+	}
+
+	notification.Project = cfg.Hyperdrive.ProjectName.Value
+	if notification.Time.IsZero() {
+		notification.Time = time.Now()
+	}
+	targets := notificationsCfg.GetTargets()
+	errs := []error{}
+	for _, target := range targets {
+		err := target.Send(notification)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error sending notification to %s: %w", target.GetName(), err))
+		}
+	}
+	return targets, errors.Join(errs...)
+}
+
+// Compare the containers' restart counts to the last check, and return the ones that just started restarting in a loop
+func (c *HyperdriveClient) FindNewRestartLoops(containers []ContainerHealth) ([]ContainerHealth, error) {
+	state, err := c.loadNotificationState()
+	if err != nil {
+		return nil, err
+	}
+
+	newLoops := []ContainerHealth{}
+	containerStates := map[string]*ContainerRestartState{}
+	for _, container := range containers {
+		current := &ContainerRestartState{
+			Restarts: container.Restarts,
+		}
+		previous, exists := state.Containers[container.Name]
+		if exists && (container.Restarting || container.Restarts-previous.Restarts >= restartLoopThreshold) {
+			current.Looping = true
+			if !previous.Looping {
+				newLoops = append(newLoops, container)
+			}
+		}
+		containerStates[container.Name] = current
+	}
+
+	state.Containers = containerStates
+	err = c.saveNotificationState(state)
+	if err != nil {
+		return nil, err
+	}
+	return newLoops, nil
+}
+
+// Load the notification state file from the config directory, returning an empty state if it doesn't exist yet
+func (c *HyperdriveClient) loadNotificationState() (*NotificationState, error) {
+	state := &NotificationState{
+		Containers: map[string]*ContainerRestartState{},
+	}
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, NotificationStateFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding notification state file path: %w", err)
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading notification state file [%s]: %w", path, err)
+	}
+
+	err = json.Unmarshal(bytes, state)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing notification state file [%s]: %w", path, err)
+	}
+	if state.Containers == nil {
+		state.Containers = map[string]*ContainerRestartState{}
+	}
+	return state, nil
+}
+
+// Save the notification state file to the config directory
+func (c *HyperdriveClient) saveNotificationState(state *NotificationState) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, NotificationStateFile))
+	if err != nil {
+		return fmt.Errorf("error expanding notification state file path: %w", err)
+	}
+
+	bytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing notification state: %w", err)
+	}
+	err = writeFileAtomically(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error saving notification state file [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Format the message and details as plain text, or as Slack's mrkdwn
+func (n *Notification) formatText(markdown bool) string {
+	lines := []string{n.Message}
+	if len(n.Details) > 0 {
+		lines = append(lines, "")
+	}
+	for _, key := range n.getDetailKeys() {
+		if markdown {
+			lines = append(lines, fmt.Sprintf("*%s:* %s", key, n.Details[key]))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %s", key, n.Details[key]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Get the names of the details in a stable order
+func (n *Notification) getDetailKeys() []string {
+	keys := make([]string, 0, len(n.Details))
+	for key := range n.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Create the body of a Discord webhook request
+func createDiscordPayload(n *Notification) map[string]any {
+	color := 0x2ECC71
+	switch n.Level {
+	case NotificationLevel_Warning:
+		color = 0xF1C40F
+	case NotificationLevel_Critical:
+		color = 0xE74C3C
+	}
+	fields := []map[string]any{}
+	for _, key := range n.getDetailKeys() {
+		fields = append(fields, map[string]any{
+			"name":   key,
+			"value":  n.Details[key],
+			"inline": true,
+		})
+	}
+	return map[string]any{
+		"username": "Hyperdrive",
+		"embeds": []map[string]any{
+			{
+				"title":       n.Title,
+				"description": n.Message,
+				"color":       color,
+				"fields":      fields,
+				"timestamp":   n.Time.Format(time.RFC3339),
+				"footer": map[string]any{
+					"text": n.Project,
+				},
+			},
+		},
+	}
+}
+
+// Create the body of a Slack incoming webhook request
+func createSlackPayload(n *Notification) map[string]any {
+	return map[string]any{
+		"text": fmt.Sprintf("*[%s] %s*\n%s", n.Project, n.Title, n.formatText(true)),
+	}
+}
+
+// Get the ntfy priority for a notification level
+func getNtfyPriority(level NotificationLevel) string {
+	switch level {
+	case NotificationLevel_Warning:
+		return "high"
+	case NotificationLevel_Critical:
+		return "urgent"
+	default:
+		return "default"
+	}
+}
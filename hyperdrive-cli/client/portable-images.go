@@ -0,0 +1,199 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/config/ids"
+	"github.com/rocket-pool/node-manager-core/utils/sys"
+)
+
+const (
+	PortableImagesFile string = "portable-images.json"
+
+	// The suffix clients that ship both image variants use for their modern tags
+	modernImageTagSuffix string = "-modern"
+
+	// The max number of automatic image changes to keep in the record
+	maxPortableImageChanges int = 20
+)
+
+// An automatic change from a modern image tag to its portable equivalent
+type PortableImageChange struct {
+	// The title of the config section the tag belongs to
+	Section string `json:"section"`
+
+	// The modern tag that was selected
+	OldTag string `json:"oldTag"`
+
+	// The portable tag that replaced it
+	NewTag string `json:"newTag"`
+
+	// When the change was made
+	Time time.Time `json:"time"`
+}
+
+// Record of the automatic image selection decisions, shown in the service version output
+type PortableImageRecord struct {
+	// The modern CPU features this machine was missing when the images were last checked
+	MissingFeatures []string `json:"missingFeatures"`
+
+	// The most recent automatic changes
+	Changes []PortableImageChange `json:"changes"`
+}
+
+// Get the modern image tags in the config that this machine's CPU can't run, along with their portable replacements
+func (c *GlobalConfig) GetUnsupportedImageTags() []PortableImageChange {
+	if len(sys.GetMissingModernCpuFeatures()) == 0 {
+		return nil
+	}
+
+	changes := []PortableImageChange{}
+	for _, section := range c.getImageSections() {
+		changes = findModernImageTags(section, changes)
+	}
+	return changes
+}
+
+// Switch any modern image tags this machine's CPU can't run to their portable equivalents, returning the changes made
+func (c *GlobalConfig) SelectPortableImages() []PortableImageChange {
+	changes := c.GetUnsupportedImageTags()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	for _, section := range c.getImageSections() {
+		replaceModernImageTags(section)
+	}
+	return changes
+}
+
+// Get the Hyperdrive section and all of the module sections, which may contain container tags
+func (c *GlobalConfig) getImageSections() []config.IConfigSection {
+	sections := []config.IConfigSection{c.Hyperdrive}
+	for _, module := range c.GetAllModuleConfigs() {
+		sections = append(sections, module)
+	}
+	return sections
+}
+
+// Switch the config to portable images if required and record the decision
+func (c *HyperdriveClient) ApplyPortableImages(cfg *GlobalConfig) ([]PortableImageChange, error) {
+	missingFeatures := sys.GetMissingModernCpuFeatures()
+	changes := cfg.SelectPortableImages()
+
+	// Only touch the record if something changed
+	record, err := c.LoadPortableImageRecord()
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 && strings.Join(record.MissingFeatures, ",") == strings.Join(missingFeatures, ",") {
+		return nil, nil
+	}
+
+	record.MissingFeatures = missingFeatures
+	record.Changes = append(record.Changes, changes...)
+	if len(record.Changes) > maxPortableImageChanges {
+		record.Changes = record.Changes[len(record.Changes)-maxPortableImageChanges:]
+	}
+	err = c.savePortableImageRecord(record)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Load the record of automatic image selections, returning an empty one if it doesn't exist yet
+func (c *HyperdriveClient) LoadPortableImageRecord() (*PortableImageRecord, error) {
+	record := &PortableImageRecord{
+		MissingFeatures: []string{},
+		Changes:         []PortableImageChange{},
+	}
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, PortableImagesFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding portable image record path: %w", err)
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return record, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading portable image record [%s]: %w", path, err)
+	}
+	err = json.Unmarshal(bytes, record)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing portable image record [%s]: %w", path, err)
+	}
+	return record, nil
+}
+
+// Save the record of automatic image selections
+func (c *HyperdriveClient) savePortableImageRecord(record *PortableImageRecord) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, PortableImagesFile))
+	if err != nil {
+		return fmt.Errorf("error expanding portable image record path: %w", err)
+	}
+	bytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing portable image record: %w", err)
+	}
+	err = os.WriteFile(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing portable image record [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Find all of the modern container tags in a section and its subsections
+func findModernImageTags(section config.IConfigSection, changes []PortableImageChange) []PortableImageChange {
+	now := time.Now()
+	for _, param := range section.GetParameters() {
+		tag, isModern := getModernImageTag(param)
+		if isModern {
+			changes = append(changes, PortableImageChange{
+				Section: section.GetTitle(),
+				OldTag:  tag,
+				NewTag:  strings.TrimSuffix(tag, modernImageTagSuffix),
+				Time:    now,
+			})
+		}
+	}
+	for _, subconfig := range section.GetSubconfigs() {
+		changes = findModernImageTags(subconfig, changes)
+	}
+	return changes
+}
+
+// Replace all of the modern container tags in a section and its subsections with their portable equivalents
+func replaceModernImageTags(section config.IConfigSection) {
+	for _, param := range section.GetParameters() {
+		tag, isModern := getModernImageTag(param)
+		if isModern {
+			param.SetValue(strings.TrimSuffix(tag, modernImageTagSuffix))
+		}
+	}
+	for _, subconfig := range section.GetSubconfigs() {
+		replaceModernImageTags(subconfig)
+	}
+}
+
+// Get the value of a container tag parameter if it's set to a modern image
+func getModernImageTag(param config.IParameter) (string, bool) {
+	if param.GetCommon().ID != ids.ContainerTagID {
+		return "", false
+	}
+	tag, isString := param.GetValueAsAny().(string)
+	if !isString || !strings.HasSuffix(tag, modernImageTagSuffix) {
+		return "", false
+	}
+	return tag, true
+}
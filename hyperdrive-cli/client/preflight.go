@@ -0,0 +1,524 @@
+package client
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/blang/semver/v4"
+	dt "github.com/docker/docker/api/types"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pbnjay/memory"
+	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/utils/sys"
+)
+
+// The result of a preflight check
+type PreflightStatus string
+
+const (
+	PreflightStatus_Pass PreflightStatus = "pass"
+	PreflightStatus_Warn PreflightStatus = "warn"
+	PreflightStatus_Fail PreflightStatus = "fail"
+)
+
+// Requirements used by the preflight checks
+const (
+	preflightRecommendedMemoryGiB uint64 = 32
+	preflightMinimumMemoryGiB     uint64 = 16
+
+	preflightRecommendedFreeDiskGiB uint64 = 2000
+	preflightMinimumFreeDiskGiB     uint64 = 1000
+
+	// Synchronous 4 KiB write latency thresholds for the disk benchmark
+	preflightDiskLatencyWarn time.Duration = 5 * time.Millisecond
+	preflightDiskLatencyFail time.Duration = 15 * time.Millisecond
+	preflightDiskWrites      int           = 200
+	preflightDiskBlockSize   int           = 4096
+
+	preflightClockOffsetWarn time.Duration = 500 * time.Millisecond
+	preflightClockOffsetFail time.Duration = 2 * time.Second
+	preflightNtpServer       string        = "pool.ntp.org:123"
+	preflightNtpTimeout      time.Duration = 5 * time.Second
+
+	preflightMinimumDockerVersion  string = "20.10.0"
+	preflightMinimumComposeVersion string = "2.0.0"
+	preflightMinimumPodmanVersion  string = "4.7.0"
+
+	// Seconds between the NTP epoch (1900) and the Unix epoch (1970)
+	ntpEpochOffset uint64 = 2208988800
+)
+
+// A single row of the preflight report
+type PreflightCheck struct {
+	Name    string          `json:"name"`
+	Status  PreflightStatus `json:"status"`
+	Details string          `json:"details"`
+}
+
+// Check the host for everything a node needs before installing or configuring Hyperdrive.
+// If dockerInstallPending is set, missing Docker components are reported as warnings since the installer will provide them.
+func (c *HyperdriveClient) RunPreflightChecks(cfg *GlobalConfig, dockerInstallPending bool) []*PreflightCheck {
+	checks := []*PreflightCheck{
+		checkArchitecture(),
+		checkCpuFeatures(),
+		checkMemory(),
+	}
+	checks = append(checks, c.checkDocker(cfg, dockerInstallPending)...)
+	if !cfg.Cli.IsRootless() {
+		// Rootless runtimes run as the current user, so they don't need the docker group
+		checks = append(checks, checkDockerGroup(dockerInstallPending))
+	}
+	checks = append(checks, c.checkDisk()...)
+	checks = append(checks, checkClockOffset())
+	checks = append(checks, c.checkPorts(cfg)...)
+	return checks
+}
+
+// Get the most severe status of a set of preflight checks
+func GetPreflightStatus(checks []*PreflightCheck) PreflightStatus {
+	status := PreflightStatus_Pass
+	for _, check := range checks {
+		switch check.Status {
+		case PreflightStatus_Fail:
+			return PreflightStatus_Fail
+		case PreflightStatus_Warn:
+			status = PreflightStatus_Warn
+		}
+	}
+	return status
+}
+
+// Check that the CPU architecture is one Hyperdrive supports
+func checkArchitecture() *PreflightCheck {
+	check := &PreflightCheck{
+		Name:    "Architecture",
+		Details: runtime.GOARCH,
+	}
+	switch runtime.GOARCH {
+	case "amd64", "arm64":
+		check.Status = PreflightStatus_Pass
+	default:
+		check.Status = PreflightStatus_Fail
+		check.Details += " is not supported; Hyperdrive requires amd64 or arm64"
+	}
+	return check
+}
+
+// Check that the CPU supports the features required by the modern client images
+func checkCpuFeatures() *PreflightCheck {
+	check := &PreflightCheck{
+		Name: "CPU Features",
+	}
+	missingFeatures := sys.GetMissingModernCpuFeatures()
+	if len(missingFeatures) == 0 {
+		check.Status = PreflightStatus_Pass
+		check.Details = "all features required for modern images are supported"
+		return check
+	}
+	check.Status = PreflightStatus_Warn
+	check.Details = fmt.Sprintf("missing %s; the portable images must be used", strings.Join(missingFeatures, ", "))
+	return check
+}
+
+// Check the total system memory
+func checkMemory() *PreflightCheck {
+	totalGiB := memory.TotalMemory() / 1024 / 1024 / 1024
+	check := &PreflightCheck{
+		Name:    "Memory",
+		Details: fmt.Sprintf("%d GiB total", totalGiB),
+	}
+	switch {
+	case totalGiB >= preflightRecommendedMemoryGiB:
+		check.Status = PreflightStatus_Pass
+	case totalGiB >= preflightMinimumMemoryGiB:
+		check.Status = PreflightStatus_Warn
+		check.Details += fmt.Sprintf(", %d GiB recommended", preflightRecommendedMemoryGiB)
+	default:
+		check.Status = PreflightStatus_Fail
+		check.Details += fmt.Sprintf(", at least %d GiB required", preflightMinimumMemoryGiB)
+	}
+	return check
+}
+
+// Check the container runtime and Compose plugin versions
+func (c *HyperdriveClient) checkDocker(cfg *GlobalConfig, dockerInstallPending bool) []*PreflightCheck {
+	missingStatus := PreflightStatus_Fail
+	if dockerInstallPending {
+		missingStatus = PreflightStatus_Warn
+	}
+
+	// Check the engine
+	dockerCheck := &PreflightCheck{
+		Name: "Docker",
+	}
+	minimumVersion := preflightMinimumDockerVersion
+	if cfg.Cli.ContainerRuntime.Value == ContainerRuntime_Podman {
+		dockerCheck.Name = "Podman"
+		minimumVersion = preflightMinimumPodmanVersion
+	}
+	d, err := c.GetDocker()
+	if err == nil {
+		var version dt.Version
+		version, err = d.ServerVersion(context.Background())
+		if err == nil {
+			dockerCheck.Status, dockerCheck.Details = checkMinimumVersion(version.Version, minimumVersion)
+		}
+	}
+	if err != nil {
+		dockerCheck.Status = missingStatus
+		dockerCheck.Details = fmt.Sprintf("not available (%s)", err.Error())
+		if dockerInstallPending {
+			dockerCheck.Details = "not available yet, the installer will set it up"
+		}
+	}
+
+	// Check compose
+	composeCheck := &PreflightCheck{
+		Name: "Docker Compose",
+	}
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		composeCheck.Status = PreflightStatus_Warn
+		composeCheck.Details = err.Error()
+		return []*PreflightCheck{dockerCheck, composeCheck}
+	}
+	output, err := c.readOutput(fmt.Sprintf("%s compose version --short", runtimeCmd))
+	if err != nil {
+		composeCheck.Status = missingStatus
+		composeCheck.Details = "the docker compose plugin is not available"
+		if dockerInstallPending {
+			composeCheck.Details += ", the installer will set it up"
+		}
+	} else {
+		composeCheck.Status, composeCheck.Details = checkMinimumVersion(strings.TrimSpace(string(output)), preflightMinimumComposeVersion)
+	}
+	return []*PreflightCheck{dockerCheck, composeCheck}
+}
+
+// Compare a version string against a minimum version
+func checkMinimumVersion(version string, minimum string) (PreflightStatus, string) {
+	parsed, err := semver.ParseTolerant(version)
+	if err != nil {
+		return PreflightStatus_Warn, fmt.Sprintf("couldn't parse version [%s]", version)
+	}
+	if parsed.LT(semver.MustParse(minimum)) {
+		return PreflightStatus_Fail, fmt.Sprintf("v%s, v%s or newer required", parsed, minimum)
+	}
+	return PreflightStatus_Pass, fmt.Sprintf("v%s", parsed)
+}
+
+// Check that the user can run Docker without escalating
+func checkDockerGroup(dockerInstallPending bool) *PreflightCheck {
+	check := &PreflightCheck{
+		Name: "Docker Group",
+	}
+	if os.Getuid() == 0 {
+		check.Status = PreflightStatus_Pass
+		check.Details = "running as root"
+		return check
+	}
+
+	currentUser, err := user.Current()
+	if err != nil {
+		check.Status = PreflightStatus_Warn
+		check.Details = fmt.Sprintf("couldn't get the current user: %s", err.Error())
+		return check
+	}
+	dockerGroup, err := user.LookupGroup("docker")
+	if err != nil {
+		check.Status = PreflightStatus_Fail
+		check.Details = "the docker group doesn't exist"
+		if dockerInstallPending {
+			check.Status = PreflightStatus_Warn
+			check.Details += " yet, the installer will create it"
+		}
+		return check
+	}
+	groupIDs, err := currentUser.GroupIds()
+	if err != nil {
+		check.Status = PreflightStatus_Warn
+		check.Details = fmt.Sprintf("couldn't get the groups for %s: %s", currentUser.Username, err.Error())
+		return check
+	}
+	if slices.Contains(groupIDs, dockerGroup.Gid) {
+		check.Status = PreflightStatus_Pass
+		check.Details = fmt.Sprintf("%s is a member of the docker group", currentUser.Username)
+		return check
+	}
+	check.Status = PreflightStatus_Fail
+	check.Details = fmt.Sprintf("%s is not a member of the docker group", currentUser.Username)
+	if dockerInstallPending {
+		check.Status = PreflightStatus_Warn
+		check.Details += " yet, the installer will add it"
+	}
+	return check
+}
+
+// Check the free space and write latency of the disk that will hold the chain data
+func (c *HyperdriveClient) checkDisk() []*PreflightCheck {
+	// Docker's root directory is where the volumes live; checking its free space only needs read access
+	dataDir, err := c.GetDockerRootDir()
+	dataDirKnown := (err == nil)
+	if !dataDirKnown {
+		dataDir, err = homedir.Dir()
+		if err != nil {
+			dataDir = os.TempDir()
+		}
+	}
+
+	spaceCheck := &PreflightCheck{
+		Name: "Free Disk Space",
+	}
+	var stat syscall.Statfs_t
+	err = syscall.Statfs(dataDir, &stat)
+	if err != nil {
+		spaceCheck.Status = PreflightStatus_Warn
+		spaceCheck.Details = fmt.Sprintf("couldn't check the free space of [%s]: %s", dataDir, err.Error())
+	} else {
+		freeGiB := stat.Bavail * uint64(stat.Bsize) / 1024 / 1024 / 1024
+		spaceCheck.Details = fmt.Sprintf("%d GiB free in %s", freeGiB, dataDir)
+		switch {
+		case freeGiB >= preflightRecommendedFreeDiskGiB:
+			spaceCheck.Status = PreflightStatus_Pass
+		case freeGiB >= preflightMinimumFreeDiskGiB:
+			spaceCheck.Status = PreflightStatus_Warn
+			spaceCheck.Details += fmt.Sprintf(", %d GiB recommended", preflightRecommendedFreeDiskGiB)
+		default:
+			spaceCheck.Status = PreflightStatus_Fail
+			spaceCheck.Details += fmt.Sprintf(", at least %d GiB required", preflightMinimumFreeDiskGiB)
+		}
+		if !dataDirKnown {
+			spaceCheck.Details += "; Docker's data folder couldn't be found, so this may not be the disk that will hold the chain data"
+			if spaceCheck.Status == PreflightStatus_Pass {
+				spaceCheck.Status = PreflightStatus_Warn
+			}
+		}
+	}
+
+	// The benchmark needs to write a file, which usually isn't allowed in Docker's root directory without root
+	benchmarkDir := dataDir
+	if !isWritableDir(benchmarkDir) {
+		benchmarkDir, err = homedir.Dir()
+		if err != nil {
+			benchmarkDir = os.TempDir()
+		}
+	}
+	benchmarkCheck := benchmarkDisk(benchmarkDir)
+	if !dataDirKnown || benchmarkDir != dataDir {
+		if !dataDirKnown {
+			benchmarkCheck.Details += fmt.Sprintf("; measured in %s since Docker's data folder couldn't be found", benchmarkDir)
+		} else {
+			benchmarkCheck.Details += fmt.Sprintf("; measured in %s since %s isn't writable", benchmarkDir, dataDir)
+		}
+		benchmarkCheck.Details += ", so this may not be the disk that will hold the chain data"
+		if benchmarkCheck.Status == PreflightStatus_Pass {
+			benchmarkCheck.Status = PreflightStatus_Warn
+		}
+	}
+	return []*PreflightCheck{spaceCheck, benchmarkCheck}
+}
+
+// Check if the current user can write to a directory
+func isWritableDir(dir string) bool {
+	file, err := os.CreateTemp(dir, ".hyperdrive-preflight-*")
+	if err != nil {
+		return false
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return true
+}
+
+// Run a quick synchronous write benchmark to estimate the disk's latency and IOPS
+func benchmarkDisk(dir string) *PreflightCheck {
+	check := &PreflightCheck{
+		Name: "Disk Performance",
+	}
+	file, err := os.CreateTemp(dir, ".hyperdrive-preflight-*")
+	if err != nil {
+		check.Status = PreflightStatus_Warn
+		check.Details = fmt.Sprintf("couldn't create a benchmark file in [%s]: %s", dir, err.Error())
+		return check
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	block := make([]byte, preflightDiskBlockSize)
+	start := time.Now()
+	for i := 0; i < preflightDiskWrites; i++ {
+		_, err = file.WriteAt(block, int64(i*preflightDiskBlockSize))
+		if err == nil {
+			err = file.Sync()
+		}
+		if err != nil {
+			check.Status = PreflightStatus_Warn
+			check.Details = fmt.Sprintf("error running the benchmark in [%s]: %s", filepath.Dir(file.Name()), err.Error())
+			return check
+		}
+	}
+	latency := time.Since(start) / time.Duration(preflightDiskWrites)
+	iops := uint64(math.Round(float64(time.Second) / float64(latency)))
+	check.Details = fmt.Sprintf("%s average synchronous write latency (~%d IOPS)", latency.Round(time.Microsecond), iops)
+
+	switch {
+	case latency < preflightDiskLatencyWarn:
+		check.Status = PreflightStatus_Pass
+	case latency < preflightDiskLatencyFail:
+		check.Status = PreflightStatus_Warn
+		check.Details += "; your disk may struggle to keep up with the chain, an NVMe SSD is recommended"
+	default:
+		check.Status = PreflightStatus_Fail
+		check.Details += "; your disk is too slow to keep up with the chain, an NVMe SSD is recommended"
+	}
+	return check
+}
+
+// Check the system clock against an NTP server
+func checkClockOffset() *PreflightCheck {
+	check := &PreflightCheck{
+		Name: "Clock Sync",
+	}
+	offset, err := getNtpOffset(preflightNtpServer)
+	if err != nil {
+		check.Status = PreflightStatus_Warn
+		check.Details = fmt.Sprintf("couldn't query %s: %s", preflightNtpServer, err.Error())
+		return check
+	}
+
+	check.Details = fmt.Sprintf("%s offset from %s", offset.Round(time.Millisecond), preflightNtpServer)
+	if offset < 0 {
+		offset = -offset
+	}
+	switch {
+	case offset < preflightClockOffsetWarn:
+		check.Status = PreflightStatus_Pass
+	case offset < preflightClockOffsetFail:
+		check.Status = PreflightStatus_Warn
+		check.Details += "; make sure your clock is being synchronized"
+	default:
+		check.Status = PreflightStatus_Fail
+		check.Details += "; your clock must be synchronized or your validators will miss duties"
+	}
+	return check
+}
+
+// Get the offset of the system clock from an NTP server using a single SNTP request
+func getNtpOffset(server string) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", server, preflightNtpTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(preflightNtpTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	// Client request: leap indicator 0, version 3, mode 3
+	request := make([]byte, 48)
+	request[0] = 0x1b
+	sendTime := time.Now()
+	_, err = conn.Write(request)
+	if err != nil {
+		return 0, err
+	}
+	response := make([]byte, 48)
+	_, err = conn.Read(response)
+	if err != nil {
+		return 0, err
+	}
+	receiveTime := time.Now()
+
+	serverReceive := ntpToTime(response[32:40])
+	serverTransmit := ntpToTime(response[40:48])
+	if serverTransmit.IsZero() {
+		return 0, errors.New("invalid response")
+	}
+	return (serverReceive.Sub(sendTime) + serverTransmit.Sub(receiveTime)) / 2, nil
+}
+
+// Convert an NTP timestamp to a time
+func ntpToTime(timestamp []byte) time.Time {
+	seconds := uint64(binary.BigEndian.Uint32(timestamp[0:4]))
+	fraction := uint64(binary.BigEndian.Uint32(timestamp[4:8]))
+	if seconds == 0 {
+		return time.Time{}
+	}
+	nanos := (fraction * uint64(time.Second)) >> 32
+	return time.Unix(int64(seconds-ntpEpochOffset), int64(nanos))
+}
+
+// Check that the ports the config binds on the host are free
+func (c *HyperdriveClient) checkPorts(cfg *GlobalConfig) []*PreflightCheck {
+	type portInfo struct {
+		name        string
+		port        uint16
+		checkUdp    bool
+		containerID config.ContainerID
+	}
+	hdCfg := cfg.Hyperdrive
+	ports := []portInfo{}
+	if hdCfg.IsLocalMode() {
+		ports = append(ports,
+			portInfo{"Execution Client P2P", hdCfg.LocalExecutionClient.P2pPort.Value, true, config.ContainerID_ExecutionClient},
+			portInfo{"Beacon Node P2P", hdCfg.LocalBeaconClient.P2pPort.Value, true, config.ContainerID_BeaconNode},
+		)
+	}
+	if hdCfg.Metrics.EnableMetrics.Value {
+		ports = append(ports, portInfo{"Grafana", hdCfg.Metrics.Grafana.Port.Value, false, config.ContainerID_Grafana})
+		if hdCfg.Metrics.Prometheus.OpenPort.Value != config.RpcPortMode_Closed {
+			ports = append(ports, portInfo{"Prometheus", hdCfg.Metrics.Prometheus.Port.Value, false, config.ContainerID_Prometheus})
+		}
+	}
+
+	checks := []*PreflightCheck{}
+	for _, port := range ports {
+		check := &PreflightCheck{
+			Name: fmt.Sprintf("%s Port", port.name),
+		}
+		err := checkPortFree(port.port, port.checkUdp)
+		if err == nil {
+			check.Status = PreflightStatus_Pass
+			check.Details = fmt.Sprintf("%d is free", port.port)
+		} else if status, _ := c.GetDockerStatus(hdCfg.GetDockerArtifactName(string(port.containerID))); status == "running" {
+			// Hyperdrive's own container is the one using it
+			check.Status = PreflightStatus_Pass
+			check.Details = fmt.Sprintf("%d is in use by Hyperdrive", port.port)
+		} else {
+			check.Status = PreflightStatus_Fail
+			check.Details = fmt.Sprintf("%d is already in use (%s)", port.port, err.Error())
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// Check if a port can be bound on the host
+func checkPortFree(port uint16, checkUdp bool) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	listener.Close()
+
+	if checkUdp {
+		conn, err := net.ListenPacket("udp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return err
+		}
+		conn.Close()
+	}
+	return nil
+}
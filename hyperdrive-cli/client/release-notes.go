@@ -0,0 +1,111 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+const (
+	releaseNotesFile string = "release-notes.json"
+)
+
+// The severity of a release note
+type ReleaseNoteSeverity string
+
+const (
+	ReleaseNoteSeverity_Info      ReleaseNoteSeverity = "info"
+	ReleaseNoteSeverity_Important ReleaseNoteSeverity = "important"
+	ReleaseNoteSeverity_Critical  ReleaseNoteSeverity = "critical"
+)
+
+// A single change in a release that node operators should know about
+type ReleaseNote struct {
+	// The module the change applies to, such as Hyperdrive or Stakewise
+	Module string `json:"module"`
+
+	// How important the change is
+	Severity ReleaseNoteSeverity `json:"severity"`
+
+	// A description of the change
+	Description string `json:"description"`
+
+	// Something the node operator must do after upgrading, if anything
+	RequiredAction string `json:"requiredAction,omitempty"`
+}
+
+// The notes for a single release
+type ReleaseNotesEntry struct {
+	Version string         `json:"version"`
+	Notes   []*ReleaseNote `json:"notes"`
+}
+
+// The notes for every release, which are shipped with the installer package
+type ReleaseNotes struct {
+	Releases []*ReleaseNotesEntry `json:"releases"`
+}
+
+// Load the release notes of the installed Hyperdrive package
+func LoadReleaseNotes() (*ReleaseNotes, error) {
+	path := filepath.Join(hyperdriveShareDir, releaseNotesFile)
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading release notes [%s]: %w", path, err)
+	}
+
+	var notes ReleaseNotes
+	err = json.Unmarshal(bytes, &notes)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing release notes [%s]: %w", path, err)
+	}
+	return &notes, nil
+}
+
+// Get the notes for every release after the since version, up to and including the until version, from oldest to newest.
+// If since is nil, only the notes for the until version are returned.
+func (n *ReleaseNotes) GetReleasesBetween(since *semver.Version, until semver.Version) ([]*ReleaseNotesEntry, error) {
+	type versionedEntry struct {
+		version semver.Version
+		entry   *ReleaseNotesEntry
+	}
+	matches := []versionedEntry{}
+	for _, entry := range n.Releases {
+		version, err := ParseReleaseVersion(entry.Version)
+		if err != nil {
+			return nil, err
+		}
+		if version.GT(until) {
+			continue
+		}
+		if since == nil && !version.EQ(until) {
+			continue
+		}
+		if since != nil && version.LTE(*since) {
+			continue
+		}
+		matches = append(matches, versionedEntry{version: version, entry: entry})
+	}
+
+	sort.Slice(matches, func(i int, j int) bool {
+		return matches[i].version.LT(matches[j].version)
+	})
+	entries := make([]*ReleaseNotesEntry, len(matches))
+	for i, match := range matches {
+		entries[i] = match.entry
+	}
+	return entries, nil
+}
+
+// Parse a Hyperdrive version string, with or without the leading v
+func ParseReleaseVersion(version string) (semver.Version, error) {
+	parsed, err := semver.Parse(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("error parsing version [%s]: %w", version, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,303 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+)
+
+const (
+	// The default feed used to check for new Hyperdrive releases
+	DefaultReleaseFeedUrl string = "https://api.github.com/repos/nodeset-org/hyperdrive/releases/latest"
+
+	// The extension of detached release artifact signatures
+	SignatureExtension string = ".sig"
+
+	// The version that refers to the most recent release
+	LatestReleaseVersion string = "latest"
+
+	// The prefix of the line in a checksum manifest that declares the release version it belongs to
+	manifestVersionPrefix string = "# version:"
+
+	cliAssetFormat         string        = "hyperdrive-cli-%s-%s"
+	releaseRequestTimeout  time.Duration = 30 * time.Second
+	releaseDownloadTimeout time.Duration = 10 * time.Minute
+)
+
+// The base64-encoded, DER-encoded Ed25519 public key used to sign release artifacts.
+// This is embedded at build time with `-ldflags "-X github.com/nodeset-org/hyperdrive/hyperdrive-cli/client.releaseSigningKey=..."`.
+var releaseSigningKey string
+
+// A signed checksum manifest for a release, in `sha256sum` format with a leading line declaring the release version
+type ChecksumManifest struct {
+	// The release version the manifest belongs to
+	Version string
+
+	// The hex-encoded SHA-256 hash of each artifact, by name
+	Checksums map[string]string
+}
+
+// A release published to the release feed
+type ReleaseInfo struct {
+	TagName string          `json:"tag_name"`
+	Assets  []*ReleaseAsset `json:"assets"`
+}
+
+// A single artifact attached to a release
+type ReleaseAsset struct {
+	Name        string `json:"name"`
+	DownloadUrl string `json:"browser_download_url"`
+}
+
+// Get the latest release from the release feed. The feed follows the format of GitHub's "latest release" API.
+func GetLatestRelease(feedUrl string) (*ReleaseInfo, error) {
+	httpClient := &http.Client{Timeout: releaseRequestTimeout}
+	resp, err := httpClient.Get(feedUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error querying release feed [%s]: %w", feedUrl, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release feed [%s] returned unexpected status %d", feedUrl, resp.StatusCode)
+	}
+
+	var release ReleaseInfo
+	err = json.NewDecoder(resp.Body).Decode(&release)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing release feed response: %w", err)
+	}
+	return &release, nil
+}
+
+// Resolve a version such as "latest" to the tag of a specific release, so all of its artifacts come from the same one
+func ResolveReleaseVersion(version string) (string, error) {
+	if version != LatestReleaseVersion {
+		return version, nil
+	}
+	release, err := GetLatestRelease(DefaultReleaseFeedUrl)
+	if err != nil {
+		return "", fmt.Errorf("error resolving the latest release: %w", err)
+	}
+	return release.TagName, nil
+}
+
+// Get the semantic version of the release from its tag
+func (r *ReleaseInfo) GetVersion() (semver.Version, error) {
+	return ParseReleaseVersion(r.TagName)
+}
+
+// Get the asset with the provided name, or nil if the release doesn't have it
+func (r *ReleaseInfo) GetAsset(name string) *ReleaseAsset {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset
+		}
+	}
+	return nil
+}
+
+// Get the name of the CLI binary asset for this machine
+func GetCliAssetName() string {
+	return fmt.Sprintf(cliAssetFormat, runtime.GOOS, runtime.GOARCH)
+}
+
+// Download a release artifact
+func DownloadReleaseArtifact(url string) ([]byte, error) {
+	httpClient := &http.Client{Timeout: releaseDownloadTimeout}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading [%s]: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected http status downloading [%s]: %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading [%s]: %w", url, err)
+	}
+	return data, nil
+}
+
+// Check that the signature of a checksum manifest was made by the release signing key, then parse it.
+// Since the version and the platform-specific artifact names are covered by the signature, a manifest can't be
+// passed off as belonging to a different release or platform.
+func ParseChecksumManifest(manifest []byte, signature []byte) (*ChecksumManifest, error) {
+	err := VerifyReleaseSignature(manifest, signature)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying checksum manifest: %w", err)
+	}
+	return parseChecksumManifest(manifest)
+}
+
+// Parse a checksum manifest whose signature has already been verified
+func parseChecksumManifest(manifest []byte) (*ChecksumManifest, error) {
+	parsed := &ChecksumManifest{
+		Checksums: map[string]string{},
+	}
+	for _, line := range strings.Split(string(manifest), "\n") {
+		if strings.HasPrefix(line, manifestVersionPrefix) {
+			parsed.Version = strings.TrimSpace(strings.TrimPrefix(line, manifestVersionPrefix))
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks files hashed in binary mode with a leading *
+		parsed.Checksums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	if parsed.Version == "" {
+		return nil, fmt.Errorf("checksum manifest doesn't declare which release it belongs to")
+	}
+	return parsed, nil
+}
+
+// Check that the manifest belongs to the given release version
+func (m *ChecksumManifest) VerifyVersion(version string) error {
+	manifestVersion, err := ParseReleaseVersion(m.Version)
+	if err != nil {
+		return fmt.Errorf("error parsing checksum manifest version: %w", err)
+	}
+	expectedVersion, err := ParseReleaseVersion(version)
+	if err != nil {
+		return err
+	}
+	if !manifestVersion.Equals(expectedVersion) {
+		return fmt.Errorf("checksum manifest belongs to %s, not %s", m.Version, version)
+	}
+	return nil
+}
+
+// Check that the artifact matches its entry in the manifest
+func (m *ChecksumManifest) VerifyArtifact(name string, data []byte) error {
+	checksum, exists := m.Checksums[name]
+	if !exists {
+		return fmt.Errorf("checksum manifest doesn't have an entry for %s", name)
+	}
+	return verifySha256(data, checksum)
+}
+
+// Check that a file matches its entry in the manifest, without reading all of it into memory
+func (m *ChecksumManifest) VerifyFile(name string, path string) error {
+	checksum, exists := m.Checksums[name]
+	if !exists {
+		return fmt.Errorf("checksum manifest doesn't have an entry for %s", name)
+	}
+	actual, err := getFileSha256(path)
+	if err != nil {
+		return err
+	}
+	if actual != strings.ToLower(checksum) {
+		return fmt.Errorf("checksum mismatch: expected %s but got %s", checksum, actual)
+	}
+	return nil
+}
+
+// Create a checksum manifest for the given release version and artifacts, in the same format as the release's
+func createChecksumManifest(version string, checksums map[string]string) []byte {
+	names := make([]string, 0, len(checksums))
+	for name := range checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var manifest strings.Builder
+	fmt.Fprintf(&manifest, "%s %s\n", manifestVersionPrefix, version)
+	for _, name := range names {
+		fmt.Fprintf(&manifest, "%s  %s\n", checksums[name], name)
+	}
+	return []byte(manifest.String())
+}
+
+// Get the fingerprint of the release signing key embedded in the CLI
+func GetReleaseSigningKeyFingerprint() (string, error) {
+	key, err := getReleaseSigningKey()
+	if err != nil {
+		return "", err
+	}
+	return getSigningKeyFingerprint(key), nil
+}
+
+// Get the fingerprint of an Ed25519 public key, in the same format as SSH uses
+func getSigningKeyFingerprint(key ed25519.PublicKey) string {
+	hash := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(hash[:])
+}
+
+// Check that the detached signature of an artifact was made by the release signing key
+func VerifyReleaseSignature(data []byte, signature []byte) error {
+	key, err := getReleaseSigningKey()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, data, signature) {
+		return fmt.Errorf("signature was not made by the release signing key")
+	}
+	return nil
+}
+
+// Check that the SHA-256 hash of the data matches the expected hex-encoded hash
+func verifySha256(data []byte, expectedHex string) error {
+	expected, err := hex.DecodeString(expectedHex)
+	if err != nil {
+		return fmt.Errorf("error decoding checksum [%s]: %w", expectedHex, err)
+	}
+
+	actual := sha256.Sum256(data)
+	if !bytes.Equal(actual[:], expected) {
+		return fmt.Errorf("checksum mismatch: expected %x but got %x", expected, actual)
+	}
+	return nil
+}
+
+// Get the hex-encoded SHA-256 hash of a file, without reading all of it into memory
+func getFileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening [%s]: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", fmt.Errorf("error reading [%s]: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Get the release signing key embedded in the CLI
+func getReleaseSigningKey() (ed25519.PublicKey, error) {
+	if releaseSigningKey == "" {
+		return nil, fmt.Errorf("this build of Hyperdrive doesn't have a release signing key, so it can't verify release artifacts")
+	}
+	der, err := base64.StdEncoding.DecodeString(releaseSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding release signing key: %w", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release signing key: %w", err)
+	}
+	edKey, isEd25519 := key.(ed25519.PublicKey)
+	if !isEd25519 {
+		return nil, fmt.Errorf("release signing key is a %T, not an Ed25519 key", key)
+	}
+	return edKey, nil
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Replace the embedded release signing key with a new one for the duration of a test, returning its private key
+func setTestReleaseSigningKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating signing key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("error serializing signing key: %v", err)
+	}
+	previousKey := releaseSigningKey
+	releaseSigningKey = base64.StdEncoding.EncodeToString(der)
+	t.Cleanup(func() {
+		releaseSigningKey = previousKey
+	})
+	return privateKey
+}
+
+func getTestChecksum(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestParseChecksumManifest(t *testing.T) {
+	privateKey := setTestReleaseSigningKey(t)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating signing key: %v", err)
+	}
+
+	scriptChecksum := getTestChecksum("script")
+	packageChecksum := getTestChecksum("package")
+	validManifest := []byte("# version: v1.2.3\n" +
+		scriptChecksum + "  install.sh\n" +
+		packageChecksum + " *install.tar.xz\n")
+
+	tests := []struct {
+		name      string
+		manifest  []byte
+		signer    ed25519.PrivateKey
+		tamper    bool
+		wantErr   bool
+		version   string
+		checksums map[string]string
+	}{
+		{
+			name:     "valid manifest",
+			manifest: validManifest,
+			signer:   privateKey,
+			version:  "v1.2.3",
+			checksums: map[string]string{
+				"install.sh":     scriptChecksum,
+				"install.tar.xz": packageChecksum,
+			},
+		},
+		{
+			name:     "generated manifest",
+			manifest: createChecksumManifest("v1.2.3", map[string]string{"install.sh": scriptChecksum}),
+			signer:   privateKey,
+			version:  "v1.2.3",
+			checksums: map[string]string{
+				"install.sh": scriptChecksum,
+			},
+		},
+		{
+			name:     "signed by another key",
+			manifest: validManifest,
+			signer:   otherKey,
+			wantErr:  true,
+		},
+		{
+			name:     "modified after signing",
+			manifest: validManifest,
+			signer:   privateKey,
+			tamper:   true,
+			wantErr:  true,
+		},
+		{
+			name:     "missing version",
+			manifest: []byte(scriptChecksum + "  install.sh\n"),
+			signer:   privateKey,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signature := ed25519.Sign(test.signer, test.manifest)
+			manifest := test.manifest
+			if test.tamper {
+				manifest = append([]byte{}, manifest...)
+				manifest = append(manifest, []byte(getTestChecksum("evil")+"  evil.sh\n")...)
+			}
+
+			parsed, err := ParseChecksumManifest(manifest, signature)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parsed.Version != test.version {
+				t.Errorf("expected version %s, got %s", test.version, parsed.Version)
+			}
+			if len(parsed.Checksums) != len(test.checksums) {
+				t.Errorf("expected %d checksums, got %d", len(test.checksums), len(parsed.Checksums))
+			}
+			for name, checksum := range test.checksums {
+				if parsed.Checksums[name] != checksum {
+					t.Errorf("expected checksum %s for %s, got %s", checksum, name, parsed.Checksums[name])
+				}
+			}
+		})
+	}
+}
+
+func TestParseChecksumManifestWithoutKey(t *testing.T) {
+	previousKey := releaseSigningKey
+	releaseSigningKey = ""
+	t.Cleanup(func() {
+		releaseSigningKey = previousKey
+	})
+
+	_, err := ParseChecksumManifest([]byte("# version: v1.2.3\n"), []byte("signature"))
+	if err == nil {
+		t.Fatalf("expected an error without a signing key, got none")
+	}
+}
+
+func TestChecksumManifestVerifyVersion(t *testing.T) {
+	manifest := &ChecksumManifest{
+		Version:   "v1.2.3",
+		Checksums: map[string]string{},
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "same version", version: "v1.2.3"},
+		{name: "same version without v", version: "1.2.3"},
+		{name: "older version", version: "v1.2.2", wantErr: true},
+		{name: "prerelease of the same version", version: "v1.2.3-b1", wantErr: true},
+		{name: "not a version", version: "latest", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := manifest.VerifyVersion(test.version)
+			if test.wantErr && err == nil {
+				t.Fatalf("expected an error, got none")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumManifestVerifyArtifact(t *testing.T) {
+	manifest := &ChecksumManifest{
+		Version: "v1.2.3",
+		Checksums: map[string]string{
+			"install.sh": getTestChecksum("script"),
+			"broken.sh":  "not-hex",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		artifact string
+		data     string
+		wantErr  bool
+	}{
+		{name: "matching artifact", artifact: "install.sh", data: "script"},
+		{name: "modified artifact", artifact: "install.sh", data: "script2", wantErr: true},
+		{name: "artifact not in the manifest", artifact: "other.sh", data: "script", wantErr: true},
+		{name: "malformed checksum", artifact: "broken.sh", data: "script", wantErr: true},
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := manifest.VerifyArtifact(test.artifact, []byte(test.data))
+			if test.wantErr && err == nil {
+				t.Fatalf("expected an error from VerifyArtifact, got none")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error from VerifyArtifact: %v", err)
+			}
+
+			// Files are checked the same way without being read into memory
+			path := filepath.Join(dir, test.artifact)
+			err = os.WriteFile(path, []byte(test.data), 0644)
+			if err != nil {
+				t.Fatalf("error writing artifact: %v", err)
+			}
+			err = manifest.VerifyFile(test.artifact, path)
+			if test.wantErr && err == nil {
+				t.Fatalf("expected an error from VerifyFile, got none")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error from VerifyFile: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+
+	swconfig "github.com/nodeset-org/hyperdrive-stakewise/shared/config"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+const (
+	resourcesConfigID        string = "resources"
+	resourcesCpuLimitID      string = "cpuLimit"
+	resourcesCpuSharesID     string = "cpuShares"
+	resourcesMemoryLimitID   string = "memoryLimit"
+	resourcesOomScoreAdjID   string = "oomScoreAdjust"
+	resourcesLogMaxSizeID    string = "logMaxSize"
+	resourcesLogMaxFileID    string = "logMaxFile"
+	defaultLogMaxSize        string = "100m"
+	defaultLogMaxFile        uint64 = 3
+	minimumMemoryLimit       uint64 = 6
+	minimumCpuShares         uint64 = 2
+	maximumOomScoreAdjust    int64  = 1000
+	logMaxSizePatternString  string = "^[1-9][0-9]*[kmg]?$"
+	logMaxSizeFormatExamples string = "`100k`, `20m`, or `1g`"
+	composeSettingsIndent    string = "    "
+)
+
+var logMaxSizePattern = regexp.MustCompile(logMaxSizePatternString)
+
+// The CPU, memory, and log settings for one of Hyperdrive's containers
+type ContainerResourcesConfig struct {
+	// The container these settings apply to
+	container config.ContainerID
+
+	// The container's human-readable name
+	title string
+
+	// The number of CPU cores the container can use, or 0 for no limit
+	CpuLimit config.Parameter[float64]
+
+	// The container's CPU weight relative to the others, or 0 for Docker's default
+	CpuShares config.Parameter[uint64]
+
+	// The most memory the container can use in MB, or 0 for no limit
+	MemoryLimit config.Parameter[uint64]
+
+	// How likely the kernel is to kill the container when the machine runs out of memory, or 0 for Docker's default
+	OomScoreAdjust config.Parameter[int64]
+
+	// The size the container's log file can grow to before it's rotated, or blank to use the Docker daemon's logging settings
+	LogMaxSize config.Parameter[string]
+
+	// How many rotated log files to keep
+	LogMaxFile config.Parameter[uint64]
+}
+
+// Settings for the resource limits and log rotation of each of Hyperdrive's containers, including the modules' containers
+type ResourcesConfig struct {
+	Daemon             *ContainerResourcesConfig
+	ExecutionClient    *ContainerResourcesConfig
+	BeaconNode         *ContainerResourcesConfig
+	MevBoost           *ContainerResourcesConfig
+	Prometheus         *ContainerResourcesConfig
+	Grafana            *ContainerResourcesConfig
+	Exporter           *ContainerResourcesConfig
+	Alertmanager       *ContainerResourcesConfig
+	StakewiseDaemon    *ContainerResourcesConfig
+	StakewiseOperator  *ContainerResourcesConfig
+	StakewiseValidator *ContainerResourcesConfig
+}
+
+// Generates a new resources config
+func NewResourcesConfig() *ResourcesConfig {
+	return &ResourcesConfig{
+		Daemon:             NewContainerResourcesConfig(config.ContainerID_Daemon, "Daemon"),
+		ExecutionClient:    NewContainerResourcesConfig(config.ContainerID_ExecutionClient, "Execution Client"),
+		BeaconNode:         NewContainerResourcesConfig(config.ContainerID_BeaconNode, "Beacon Node"),
+		MevBoost:           NewContainerResourcesConfig(config.ContainerID_MevBoost, "MEV-Boost"),
+		Prometheus:         NewContainerResourcesConfig(config.ContainerID_Prometheus, "Prometheus"),
+		Grafana:            NewContainerResourcesConfig(config.ContainerID_Grafana, "Grafana"),
+		Exporter:           NewContainerResourcesConfig(config.ContainerID_Exporter, "Node Exporter"),
+		Alertmanager:       NewContainerResourcesConfig(ContainerID_Alertmanager, "Alertmanager"),
+		StakewiseDaemon:    NewContainerResourcesConfig(swconfig.ContainerID_StakewiseDaemon, "StakeWise Daemon"),
+		StakewiseOperator:  NewContainerResourcesConfig(swconfig.ContainerID_StakewiseOperator, "StakeWise Operator"),
+		StakewiseValidator: NewContainerResourcesConfig(swconfig.ContainerID_StakewiseValidator, "StakeWise Validator Client"),
+	}
+}
+
+// The title for the config
+func (cfg *ResourcesConfig) GetTitle() string {
+	return "Resources"
+}
+
+// Get the parameters for this config
+func (cfg *ResourcesConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{}
+}
+
+// Get the sections underneath this one
+func (cfg *ResourcesConfig) GetSubconfigs() map[string]config.IConfigSection {
+	subconfigs := map[string]config.IConfigSection{}
+	for _, container := range cfg.GetContainers() {
+		subconfigs[string(container.container)] = container
+	}
+	return subconfigs
+}
+
+// Get the settings for every container, in the order they're shown
+func (cfg *ResourcesConfig) GetContainers() []*ContainerResourcesConfig {
+	return []*ContainerResourcesConfig{
+		cfg.Daemon,
+		cfg.ExecutionClient,
+		cfg.BeaconNode,
+		cfg.MevBoost,
+		cfg.Prometheus,
+		cfg.Grafana,
+		cfg.Exporter,
+		cfg.Alertmanager,
+		cfg.StakewiseDaemon,
+		cfg.StakewiseOperator,
+		cfg.StakewiseValidator,
+	}
+}
+
+// Checks to see if the resource settings are valid; if not, returns a list of errors
+func (cfg *ResourcesConfig) Validate() []string {
+	errors := []string{}
+	for _, container := range cfg.GetContainers() {
+		errors = append(errors, container.Validate()...)
+	}
+	return errors
+}
+
+// Generates a new resources config for the given container
+func NewContainerResourcesConfig(container config.ContainerID, title string) *ContainerResourcesConfig {
+	affectedContainers := []config.ContainerID{container}
+	return &ContainerResourcesConfig{
+		container: container,
+		title:     title,
+
+		CpuLimit: config.Parameter[float64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesCpuLimitID,
+				Name:               fmt.Sprintf("%s CPU Limit", title),
+				Description:        fmt.Sprintf("The number of CPU cores the %s container can use, such as `2` or `1.5`. Use 0 for no limit.", title),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]float64{
+				config.Network_All: 0,
+			},
+		},
+
+		CpuShares: config.Parameter[uint64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesCpuSharesID,
+				Name:               fmt.Sprintf("%s CPU Shares", title),
+				Description:        fmt.Sprintf("How much CPU time the %s container gets compared to the others when the machine is busy. Docker gives every container 1024 by default, so 2048 gets twice as much time and 512 gets half. It doesn't limit the container when the CPU is idle. Use 0 for Docker's default.", title),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint64{
+				config.Network_All: 0,
+			},
+		},
+
+		MemoryLimit: config.Parameter[uint64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesMemoryLimitID,
+				Name:               fmt.Sprintf("%s Memory Limit (MB)", title),
+				Description:        fmt.Sprintf("The most memory the %s container can use, in MB. If it goes over, the kernel kills it and Docker restarts it. Use 0 for no limit.", title),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint64{
+				config.Network_All: 0,
+			},
+		},
+
+		OomScoreAdjust: config.Parameter[int64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesOomScoreAdjID,
+				Name:               fmt.Sprintf("%s OOM Score Adjust", title),
+				Description:        fmt.Sprintf("How likely the kernel is to kill the %s container when the machine runs out of memory, from -1000 (never) to 1000 (first). Lowering it for the Validator Client and Beacon Node makes the kernel kill something else first; rootless runtimes can only raise it. Use 0 for Docker's default.", title),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]int64{
+				config.Network_All: 0,
+			},
+		},
+
+		LogMaxSize: config.Parameter[string]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesLogMaxSizeID,
+				Name:               fmt.Sprintf("%s Log Max Size", title),
+				Description:        fmt.Sprintf("The size the %s container's log file can grow to before Docker rotates it, such as %s. This uses Docker's json-file logging driver; clear it to use your Docker daemon's own logging settings instead.", title, logMaxSizeFormatExamples),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         true,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]string{
+				config.Network_All: defaultLogMaxSize,
+			},
+		},
+
+		LogMaxFile: config.Parameter[uint64]{
+			ParameterCommon: &config.ParameterCommon{
+				ID:                 resourcesLogMaxFileID,
+				Name:               fmt.Sprintf("%s Log Max Files", title),
+				Description:        fmt.Sprintf("How many log files to keep for the %s container, including the current one, when its logs are rotated.", title),
+				AffectsContainers:  affectedContainers,
+				CanBeBlank:         false,
+				OverwriteOnUpgrade: false,
+			},
+			Default: map[config.Network]uint64{
+				config.Network_All: defaultLogMaxFile,
+			},
+		},
+	}
+}
+
+// The title for the config
+func (cfg *ContainerResourcesConfig) GetTitle() string {
+	return fmt.Sprintf("%s Resources", cfg.title)
+}
+
+// Get the parameters for this config
+func (cfg *ContainerResourcesConfig) GetParameters() []config.IParameter {
+	return []config.IParameter{
+		&cfg.CpuLimit,
+		&cfg.CpuShares,
+		&cfg.MemoryLimit,
+		&cfg.OomScoreAdjust,
+		&cfg.LogMaxSize,
+		&cfg.LogMaxFile,
+	}
+}
+
+// Get the sections underneath this one
+func (cfg *ContainerResourcesConfig) GetSubconfigs() map[string]config.IConfigSection {
+	return map[string]config.IConfigSection{}
+}
+
+// Get the container these settings apply to
+func (cfg *ContainerResourcesConfig) GetContainer() config.ContainerID {
+	return cfg.container
+}
+
+// Get the container's resource limit and log rotation settings for its service in a compose file.
+// Each setting starts on a new line so templates can add them right after the service's other keys; the string is empty if nothing is set.
+func (cfg *ContainerResourcesConfig) ComposeSettings() string {
+	lines := []string{}
+	if cfg.CpuLimit.Value != 0 {
+		lines = append(lines, fmt.Sprintf("cpus: %s", cfg.CpuLimit.String()))
+	}
+	if cfg.CpuShares.Value != 0 {
+		lines = append(lines, fmt.Sprintf("cpu_shares: %d", cfg.CpuShares.Value))
+	}
+	if cfg.MemoryLimit.Value != 0 {
+		lines = append(lines, fmt.Sprintf("mem_limit: %dm", cfg.MemoryLimit.Value))
+	}
+	if cfg.OomScoreAdjust.Value != 0 {
+		lines = append(lines, fmt.Sprintf("oom_score_adj: %d", cfg.OomScoreAdjust.Value))
+	}
+	if cfg.LogMaxSize.Value != "" {
+		lines = append(lines,
+			"logging:",
+			"  driver: json-file",
+			"  options:",
+			fmt.Sprintf("    max-size: %q", cfg.LogMaxSize.Value),
+			fmt.Sprintf("    max-file: \"%d\"", cfg.LogMaxFile.Value),
+		)
+	}
+
+	settings := ""
+	for _, line := range lines {
+		settings += "\n" + composeSettingsIndent + line
+	}
+	return settings
+}
+
+// Checks to see if the container's resource settings are valid; if not, returns a list of errors
+func (cfg *ContainerResourcesConfig) Validate() []string {
+	errors := []string{}
+	if cfg.CpuLimit.Value < 0 {
+		errors = append(errors, fmt.Sprintf("[%s] can't be negative.", cfg.CpuLimit.Name))
+	}
+	if cfg.CpuShares.Value != 0 && cfg.CpuShares.Value < minimumCpuShares {
+		errors = append(errors, fmt.Sprintf("[%s] must be 0 or at least %d.", cfg.CpuShares.Name, minimumCpuShares))
+	}
+	if cfg.MemoryLimit.Value != 0 && cfg.MemoryLimit.Value < minimumMemoryLimit {
+		errors = append(errors, fmt.Sprintf("[%s] must be 0 or at least %d MB.", cfg.MemoryLimit.Name, minimumMemoryLimit))
+	}
+	if cfg.OomScoreAdjust.Value < -maximumOomScoreAdjust || cfg.OomScoreAdjust.Value > maximumOomScoreAdjust {
+		errors = append(errors, fmt.Sprintf("[%s] must be between %d and %d.", cfg.OomScoreAdjust.Name, -maximumOomScoreAdjust, maximumOomScoreAdjust))
+	}
+	if cfg.LogMaxSize.Value != "" {
+		if !logMaxSizePattern.MatchString(cfg.LogMaxSize.Value) {
+			errors = append(errors, fmt.Sprintf("[%s] must be a size such as %s.", cfg.LogMaxSize.Name, logMaxSizeFormatExamples))
+		}
+		if cfg.LogMaxFile.Value == 0 {
+			errors = append(errors, fmt.Sprintf("[%s] must be at least 1.", cfg.LogMaxFile.Name))
+		}
+	}
+	return errors
+}
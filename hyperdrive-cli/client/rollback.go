@@ -0,0 +1,272 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/blang/semver/v4"
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/config/ids"
+)
+
+const (
+	RollbackDir string = "rollback"
+
+	rollbackInfoFile string = "rollback.json"
+	rollbackShareDir string = "share"
+
+	// The first version whose CLI can roll back to itself; rolling back needs the previous version's CLI, so older installations aren't snapshotted
+	rollbackMinimumVersion string = "0.4.2-b1"
+)
+
+// The folders in the Hyperdrive system directory that are replaced by the installer
+var installedShareFolders = []string{"templates", "scripts", "override"}
+
+// A snapshot of an installation, taken before it was upgraded
+type InstallationSnapshot struct {
+	// The Hyperdrive version that was installed
+	Version string `json:"version"`
+
+	// When the snapshot was taken
+	Created time.Time `json:"created"`
+
+	// The container tags that were configured, by config section
+	ContainerTags map[string]string `json:"containerTags"`
+}
+
+// Snapshot the current installation before it's upgraded to a new version, so the upgrade can be rolled back.
+// This does nothing if Hyperdrive hasn't been installed and configured yet, or if the new version is the same as the current one.
+func (c *HyperdriveClient) SnapshotInstallation(newVersion string) error {
+	// Make sure there's something to snapshot
+	currentVersion, err := c.GetInstalledVersion()
+	if err != nil {
+		return err
+	}
+	if currentVersion == "" || currentVersion == "v"+strings.TrimPrefix(newVersion, "v") {
+		return nil
+	}
+	if !supportsRollback(currentVersion) {
+		fmt.Printf("%sNOTE: Hyperdrive %s can't roll itself back, so this upgrade won't be able to be undone with `hyperdrive service install --rollback`.%s\n", terminal.ColorYellow, currentVersion, terminal.ColorReset)
+		return nil
+	}
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	// Replace any old snapshot
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("error expanding config path: %w", err)
+	}
+	snapshotDir := filepath.Join(configPath, RollbackDir)
+	err = os.RemoveAll(snapshotDir)
+	if err != nil {
+		return fmt.Errorf("error removing old rollback snapshot [%s]: %w", snapshotDir, err)
+	}
+	err = os.MkdirAll(snapshotDir, 0700)
+	if err != nil {
+		return fmt.Errorf("error creating rollback snapshot folder [%s]: %w", snapshotDir, err)
+	}
+
+	// Copy the settings and the installation files
+	err = copyFile(filepath.Join(configPath, SettingsFile), filepath.Join(snapshotDir, SettingsFile))
+	if err != nil {
+		return fmt.Errorf("error saving user settings to the rollback snapshot: %w", err)
+	}
+	for _, folder := range installedShareFolders {
+		err = copyDir(filepath.Join(hyperdriveShareDir, folder), filepath.Join(snapshotDir, rollbackShareDir, folder))
+		if err != nil {
+			return fmt.Errorf("error saving %s to the rollback snapshot: %w", folder, err)
+		}
+	}
+	err = copyDir(filepath.Join(configPath, overrideDir), filepath.Join(snapshotDir, overrideDir))
+	if err != nil {
+		return fmt.Errorf("error saving overrides to the rollback snapshot: %w", err)
+	}
+
+	// Record what was installed
+	snapshot := &InstallationSnapshot{
+		Version:       currentVersion,
+		Created:       time.Now(),
+		ContainerTags: cfg.GetContainerTags(),
+	}
+	bytes, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing rollback snapshot: %w", err)
+	}
+	path := filepath.Join(snapshotDir, rollbackInfoFile)
+	err = os.WriteFile(path, bytes, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing rollback snapshot [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Check if a Hyperdrive version's CLI has `service install --rollback`, which is needed to roll back to that version
+func supportsRollback(version string) bool {
+	parsedVersion, err := semver.Parse(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return false
+	}
+	return parsedVersion.GE(semver.MustParse(rollbackMinimumVersion))
+}
+
+// Get the version of the installed Hyperdrive service, with a leading v.
+// Returns an empty string if Hyperdrive hasn't been installed and configured yet.
+func (c *HyperdriveClient) GetInstalledVersion() (string, error) {
+	cfg, isNew, err := c.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return "", nil
+	}
+	_, err = os.Stat(templatesDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error checking installation folder [%s]: %w", templatesDir, err)
+	}
+
+	// Prefer the version of the running service, since the config may not have been saved since the last install
+	version, err := c.GetServiceVersion()
+	if err != nil {
+		version = cfg.Hyperdrive.Version
+	}
+	return "v" + strings.TrimPrefix(version, "v"), nil
+}
+
+// Load the snapshot of the previous installation, returning nil if there isn't one
+func (c *HyperdriveClient) LoadInstallationSnapshot() (*InstallationSnapshot, error) {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, RollbackDir, rollbackInfoFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding rollback snapshot path: %w", err)
+	}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading rollback snapshot [%s]: %w", path, err)
+	}
+
+	var snapshot InstallationSnapshot
+	err = json.Unmarshal(bytes, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing rollback snapshot [%s]: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// Restore the installation files, overrides, and user settings from the snapshot of the previous installation
+func (c *HyperdriveClient) RestoreInstallationSnapshot() error {
+	configPath, err := homedir.Expand(c.Context.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("error expanding config path: %w", err)
+	}
+	snapshotDir := filepath.Join(configPath, RollbackDir)
+
+	// Restore the installation files as root
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	for _, folder := range installedShareFolders {
+		source := shellescape.Quote(filepath.Join(snapshotDir, rollbackShareDir, folder))
+		target := shellescape.Quote(filepath.Join(hyperdriveShareDir, folder))
+		err = c.printOutput(fmt.Sprintf("%[1]s rm -rf %[3]s && %[1]s cp -r %[2]s %[3]s", escalationCmd, source, target))
+		if err != nil {
+			return fmt.Errorf("error restoring %s: %w", folder, err)
+		}
+	}
+
+	// Restore the overrides
+	userOverrideDir := filepath.Join(configPath, overrideDir)
+	err = os.RemoveAll(userOverrideDir)
+	if err != nil {
+		return fmt.Errorf("error removing overrides [%s]: %w", userOverrideDir, err)
+	}
+	err = copyDir(filepath.Join(snapshotDir, overrideDir), userOverrideDir)
+	if err != nil {
+		return fmt.Errorf("error restoring overrides: %w", err)
+	}
+
+	// Restore the settings and drop the cached config so they get reloaded
+	err = copyFile(filepath.Join(snapshotDir, SettingsFile), filepath.Join(configPath, SettingsFile))
+	if err != nil {
+		return fmt.Errorf("error restoring user settings: %w", err)
+	}
+	c.cfg = nil
+	c.isNewCfg = false
+	return nil
+}
+
+// Load the user settings from the snapshot of the previous installation
+func (c *HyperdriveClient) LoadSnapshotConfig() (*GlobalConfig, error) {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, RollbackDir, SettingsFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding rollback settings path: %w", err)
+	}
+	return LoadConfigFromFile(path)
+}
+
+// Get the container tag of each config section that has one, keyed by the section's title
+func (c *GlobalConfig) GetContainerTags() map[string]string {
+	tags := map[string]string{}
+	for _, section := range c.getImageSections() {
+		addContainerTags(section, tags)
+	}
+	return tags
+}
+
+// Recursively copy a folder, skipping it if it doesn't exist
+func copyDir(source string, dest string) error {
+	_, err := os.Stat(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, relPath)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+// Add the container tags in a section and its subsections to the map
+func addContainerTags(section config.IConfigSection, tags map[string]string) {
+	for _, param := range section.GetParameters() {
+		if param.GetCommon().ID != ids.ContainerTagID {
+			continue
+		}
+		tag, isString := param.GetValueAsAny().(string)
+		if isString {
+			tags[section.GetTitle()] = tag
+		}
+	}
+	for _, subconfig := range section.GetSubconfigs() {
+		addContainerTags(subconfig, tags)
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/alessio/shellescape"
+)
+
+const (
+	CronDir string = "/etc/cron.d"
+
+	// node-exporter's textfile collector, which is how one-off jobs get their metrics into Prometheus
+	textfileCollectorDir string = "/var/lib/node_exporter/textfile_collector"
+)
+
+// A Hyperdrive CLI command that runs on a schedule, through either a systemd timer or a cron file
+type ScheduledJob struct {
+	// The name of the job's units and cron file, without an extension
+	Name string
+
+	// The command that generates the job, for the header of its files
+	Generator string
+
+	// A short description of what the job does
+	Description string
+
+	// The arguments to run the CLI with, after its global flags
+	Args []string
+
+	// The path of the Hyperdrive CLI binary
+	CliPath string
+
+	// The Hyperdrive config directory
+	ConfigPath string
+
+	// The user that runs the job; rootful runtimes need root to read the data folder unattended
+	User *user.User
+
+	// True if the job talks to a rootless runtime, so it needs the user's runtime directory
+	Rootless bool
+
+	// When the systemd timer fires, as an OnCalendar expression
+	OnCalendar string
+
+	// How long systemd can randomly delay the job by so it doesn't run at the same time as everything else, or empty for no delay
+	RandomizedDelay string
+
+	// When cron runs the job, as the schedule part of a cron line
+	CronSchedule string
+
+	// The file in the config directory that cron writes the job's output to
+	LogFile string
+}
+
+// Get the names of the systemd service and timer that run the job
+func (j *ScheduledJob) GetUnitNames() (string, string) {
+	return j.Name + ".service", j.Name + ".timer"
+}
+
+// Get the path of the cron file that runs the job
+func (j *ScheduledJob) GetCronPath() string {
+	// cron ignores files with dots in their names
+	return filepath.Join(CronDir, strings.ReplaceAll(j.Name, ".", "-"))
+}
+
+// Create the contents of the systemd service and timer that run the job
+func (j *ScheduledJob) CreateUnits() (string, string) {
+	serviceName, _ := j.GetUnitNames()
+	header := []string{
+		fmt.Sprintf("# Autogenerated by `%s` - DO NOT MODIFY THIS FILE DIRECTLY", j.Generator),
+		"# Rerun that command to regenerate it after changing your settings.",
+		"",
+	}
+
+	service := append([]string{}, header...)
+	service = append(service,
+		"[Unit]",
+		fmt.Sprintf("Description=%s", j.Description),
+		"Wants=network-online.target",
+		"After=network-online.target",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"User="+j.User.Username,
+	)
+	if j.Rootless {
+		service = append(service, "Environment="+quoteSystemdArg(runtimeDirEnvVar+"="+fmt.Sprintf(runtimeDirFormat, j.User.Uid)))
+	}
+	service = append(service, "ExecStart="+joinSystemdArgs(j.getArgs()), "")
+
+	timer := append([]string{}, header...)
+	timer = append(timer,
+		"[Unit]",
+		fmt.Sprintf("Description=Scheduled %s", j.Description),
+		"",
+		"[Timer]",
+		"OnCalendar="+j.OnCalendar,
+	)
+	if j.RandomizedDelay != "" {
+		timer = append(timer, "RandomizedDelaySec="+j.RandomizedDelay)
+	}
+	timer = append(timer,
+		"Persistent=true",
+		"Unit="+serviceName,
+		"",
+		"[Install]",
+		"WantedBy=timers.target",
+		"",
+	)
+	return strings.Join(service, "\n"), strings.Join(timer, "\n")
+}
+
+// Create the contents of the cron file that runs the job, for systems without systemd
+func (j *ScheduledJob) CreateCronEntry() string {
+	args := j.getArgs()
+	for i, arg := range args {
+		args[i] = shellescape.Quote(arg)
+	}
+	logPath := shellescape.Quote(filepath.Join(j.ConfigPath, j.LogFile))
+
+	lines := []string{
+		fmt.Sprintf("# Autogenerated by `%s` - DO NOT MODIFY THIS FILE DIRECTLY", j.Generator),
+		"# Rerun that command to regenerate it after changing your settings.",
+		"SHELL=/bin/sh",
+	}
+	if j.Rootless {
+		lines = append(lines, runtimeDirEnvVar+"="+fmt.Sprintf(runtimeDirFormat, j.User.Uid))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%s %s %s >> %s 2>&1", j.CronSchedule, j.User.Username, strings.Join(args, " "), logPath),
+		"",
+	)
+	return strings.Join(lines, "\n")
+}
+
+// Get the full CLI invocation that runs the job
+func (j *ScheduledJob) getArgs() []string {
+	args := []string{j.CliPath, "--config-path", j.ConfigPath}
+	if j.User.Uid == "0" {
+		args = append(args, "--allow-root")
+	}
+	return append(args, j.Args...)
+}
+
+// Install a cron file
+func (c *HyperdriveClient) InstallCronEntry(path string, contents string) error {
+	tmp, err := os.CreateTemp("", "hyperdrive-cron-*")
+	if err != nil {
+		return fmt.Errorf("error creating temporary cron file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(contents)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing temporary cron file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("error closing temporary cron file: %w", err)
+	}
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	err = c.printOutput(fmt.Sprintf("%s install -m 0644 %s %s", escalationCmd, shellescape.Quote(tmp.Name()), shellescape.Quote(path)))
+	if err != nil {
+		return fmt.Errorf("error installing cron file [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Remove a cron file
+func (c *HyperdriveClient) UninstallCronEntry(path string) error {
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	err = c.printOutput(fmt.Sprintf("%s rm -f %s", escalationCmd, shellescape.Quote(path)))
+	if err != nil {
+		return fmt.Errorf("error removing cron file [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Write a file under a temporary name and move it into place, so readers never see a partial file
+func writeFileAtomically(path string, contents []byte, mode os.FileMode) error {
+	tmpPath := path + ".tmp"
+	err := os.WriteFile(tmpPath, contents, mode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
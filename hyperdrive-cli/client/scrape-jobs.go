@@ -0,0 +1,344 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	dtc "github.com/docker/docker/api/types/container"
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	scrapeJobFileSuffix    string = ".yml"
+	scrapeJobHeader        string = "# Managed by `hyperdrive service metrics scrape` - remove it with `hyperdrive service metrics scrape remove %s`\n"
+	metricsPathLabel       string = "__metrics_path__"
+	jobLabel               string = "job"
+	composeServiceLabel    string = "com.docker.compose.service"
+	dockerHostGatewayName  string = "host.docker.internal"
+	defaultComposeNetwork  string = "net"
+	prometheusTargetsRoute string = "api/v1/targets?state=active"
+
+	// The job that scrapes the extra-scrape-jobs folder, which targets without a job label belong to
+	customScrapeJobName string = "custom_jobs"
+)
+
+var scrapeJobNamePattern = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_-]*$")
+
+// A custom Prometheus scrape job in the extra-scrape-jobs folder
+type ScrapeJob struct {
+	// The job's name, which Prometheus uses as its job label
+	Name string
+
+	// The host:port addresses to scrape
+	Targets []string
+
+	// The HTTP path to scrape, or empty for Prometheus' default of /metrics
+	MetricsPath string
+
+	// The file the job is saved in
+	File string
+
+	// True if the file was written by `hyperdrive service metrics scrape add` instead of by hand
+	Managed bool
+}
+
+// A target group in Prometheus' file_sd format
+type fileSdTargetGroup struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// A scrape target as reported by Prometheus' targets API
+type PrometheusTarget struct {
+	Labels     map[string]string `json:"labels"`
+	ScrapePool string            `json:"scrapePool"`
+	ScrapeUrl  string            `json:"scrapeUrl"`
+	LastError  string            `json:"lastError"`
+	Health     string            `json:"health"`
+}
+
+// The response from Prometheus' targets API
+type prometheusTargetsResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ActiveTargets []PrometheusTarget `json:"activeTargets"`
+	} `json:"data"`
+}
+
+// Check that a scrape job name can be used as a Prometheus job label and a file name, and doesn't clash with one of Hyperdrive's own jobs
+func (c *GlobalConfig) ValidateScrapeJobName(name string) error {
+	if !scrapeJobNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid job name [%s]; it must start with a letter or underscore and only contain letters, numbers, underscores, and dashes", name)
+	}
+	builtInJobs := []string{"prometheus", "node", c.GetEcMetricsJob(), c.Hyperdrive.BeaconNodeContainerName(), c.MevBoostContainerName(), "hyperdrive", customScrapeJobName}
+	for _, module := range c.GetAllModuleConfigs() {
+		for _, container := range module.GetContainersToDeploy() {
+			builtInJobs = append(builtInJobs, string(container))
+		}
+	}
+	for _, job := range builtInJobs {
+		if strings.EqualFold(name, job) {
+			return fmt.Errorf("[%s] is already used by one of Hyperdrive's scrape jobs; please pick a different name", name)
+		}
+	}
+	return nil
+}
+
+// Check that a scrape target is a host:port address
+func ValidateScrapeTarget(target string) error {
+	if strings.Contains(target, "://") {
+		return fmt.Errorf("invalid target [%s]; leave out the scheme and write it as host:port, using --path for the metrics path", target)
+	}
+	host, portString, err := net.SplitHostPort(target)
+	if err != nil {
+		return fmt.Errorf("invalid target [%s]; it must be written as host:port: %w", target, err)
+	}
+	if host == "" {
+		return fmt.Errorf("invalid target [%s]; it's missing a host", target)
+	}
+	if strings.ContainsAny(host, "/ ") {
+		return fmt.Errorf("invalid target [%s]; [%s] isn't a valid host", target, host)
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil || port == 0 {
+		return fmt.Errorf("invalid target [%s]; [%s] isn't a valid port", target, portString)
+	}
+	return nil
+}
+
+// Check that a metrics path is an absolute HTTP path
+func ValidateMetricsPath(path string) error {
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " ?#") {
+		return fmt.Errorf("invalid metrics path [%s]; it must start with a / and can't contain spaces, query strings, or fragments", path)
+	}
+	return nil
+}
+
+// Save a custom scrape job to the extra-scrape-jobs folder, replacing it if it already exists.
+// Returns the path of the file.
+func (c *HyperdriveClient) SaveScrapeJob(job *ScrapeJob) (string, error) {
+	dir, err := c.getScrapeJobsDir()
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("error creating extra-scrape-jobs folder: %w", err)
+	}
+
+	labels := map[string]string{
+		jobLabel: job.Name,
+	}
+	if job.MetricsPath != "" {
+		labels[metricsPathLabel] = job.MetricsPath
+	}
+	bytes, err := yaml.Marshal([]fileSdTargetGroup{
+		{
+			Targets: job.Targets,
+			Labels:  labels,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error serializing scrape job [%s]: %w", job.Name, err)
+	}
+	contents := append([]byte(fmt.Sprintf(scrapeJobHeader, job.Name)), bytes...)
+
+	// Prometheus watches the folder, so make sure it never sees a partial file
+	path := filepath.Join(dir, job.Name+scrapeJobFileSuffix)
+	err = writeFileAtomically(path, contents, 0644)
+	if err != nil {
+		return "", fmt.Errorf("error saving scrape job [%s]: %w", path, err)
+	}
+	return path, nil
+}
+
+// Load all of the scrape jobs in the extra-scrape-jobs folder, including ones written by hand
+func (c *HyperdriveClient) LoadScrapeJobs() ([]*ScrapeJob, error) {
+	dir, err := c.getScrapeJobsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*ScrapeJob{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading extra-scrape-jobs folder: %w", err)
+	}
+
+	jobs := []*ScrapeJob{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != scrapeJobFileSuffix {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading scrape job [%s]: %w", path, err)
+		}
+		var groups []fileSdTargetGroup
+		err = yaml.Unmarshal(bytes, &groups)
+		if err != nil {
+			return nil, fmt.Errorf("scrape job [%s] isn't a valid file_sd file: %w", path, err)
+		}
+
+		// Each group is its own job as far as Prometheus is concerned
+		name := strings.TrimSuffix(entry.Name(), scrapeJobFileSuffix)
+		managed := strings.HasPrefix(string(bytes), fmt.Sprintf(scrapeJobHeader, name))
+		for _, group := range groups {
+			jobName := group.Labels[jobLabel]
+			if jobName == "" {
+				jobName = customScrapeJobName
+			}
+			jobs = append(jobs, &ScrapeJob{
+				Name:        jobName,
+				Targets:     group.Targets,
+				MetricsPath: group.Labels[metricsPathLabel],
+				File:        path,
+				Managed:     managed,
+			})
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs, nil
+}
+
+// Delete a custom scrape job that was saved with SaveScrapeJob
+func (c *HyperdriveClient) DeleteScrapeJob(name string) error {
+	dir, err := c.getScrapeJobsDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name+scrapeJobFileSuffix)
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("there's no scrape job file named [%s]", path)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting scrape job [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Get the Docker networks that Prometheus can reach a container through, using the container's name, compose service name, or network alias.
+// Returns the networks that both share, and the networks Prometheus is on.
+func (c *HyperdriveClient) GetPrometheusNetworksForHost(host string) ([]string, []string, error) {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	projectName := cfg.Hyperdrive.ProjectName.Value
+
+	// Get Prometheus' networks, falling back to the project's network if it isn't running
+	prometheusNetworks := []string{}
+	ci, err := inspectContainer(c, fmt.Sprintf("%s_%s", projectName, cfg.Hyperdrive.PrometheusContainerName()))
+	if err == nil && ci.NetworkSettings != nil {
+		for network := range ci.NetworkSettings.Networks {
+			prometheusNetworks = append(prometheusNetworks, network)
+		}
+	}
+	if len(prometheusNetworks) == 0 {
+		prometheusNetworks = append(prometheusNetworks, fmt.Sprintf("%s_%s", projectName, defaultComposeNetwork))
+	}
+	sort.Strings(prometheusNetworks)
+
+	// Find the networks that have a container by that name
+	d, err := c.GetDocker()
+	if err != nil {
+		return nil, nil, err
+	}
+	cl, err := d.ContainerList(context.Background(), dtc.ListOptions{All: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting container list: %w", err)
+	}
+	shared := []string{}
+	for _, container := range cl {
+		if container.NetworkSettings == nil {
+			continue
+		}
+		isContainer := container.Labels[composeServiceLabel] == host
+		for _, name := range container.Names {
+			if strings.TrimPrefix(name, "/") == host {
+				isContainer = true
+			}
+		}
+		for network, endpoint := range container.NetworkSettings.Networks {
+			if !slices.Contains(prometheusNetworks, network) || slices.Contains(shared, network) {
+				continue
+			}
+			if isContainer || slices.Contains(endpoint.Aliases, host) || slices.Contains(endpoint.DNSNames, host) {
+				shared = append(shared, network)
+			}
+		}
+	}
+	sort.Strings(shared)
+	return shared, prometheusNetworks, nil
+}
+
+// Get the targets Prometheus is currently scraping from its targets API
+func (c *HyperdriveClient) GetPrometheusTargets() ([]PrometheusTarget, error) {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask from inside the container, since its port may not be open to the host
+	container := fmt.Sprintf("%s_%s", cfg.Hyperdrive.ProjectName.Value, cfg.Hyperdrive.PrometheusContainerName())
+	url := fmt.Sprintf("http://localhost:%d/%s", cfg.Hyperdrive.Metrics.Prometheus.Port.Value, prometheusTargetsRoute)
+	cmd := fmt.Sprintf("%s exec %s wget -qO- %s", runtimeCmd, shellescape.Quote(container), shellescape.Quote(url))
+	output, err := c.readOutput(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("error querying the Prometheus targets API: %w", err)
+	}
+	var response prometheusTargetsResponse
+	err = json.Unmarshal(output, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing the Prometheus targets API response: %w", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("the Prometheus targets API returned status [%s]", response.Status)
+	}
+	return response.Data.ActiveTargets, nil
+}
+
+// Find a target by its job and address in a list from the Prometheus targets API, or nil if Prometheus hasn't discovered it
+func FindPrometheusTarget(targets []PrometheusTarget, job string, address string) *PrometheusTarget {
+	for i, target := range targets {
+		if target.Labels[jobLabel] == job && target.Labels["instance"] == address {
+			return &targets[i]
+		}
+	}
+	return nil
+}
+
+// Check if a scrape target's host is a container name, rather than an IP address, a DNS name, or the Docker host itself
+func IsContainerHost(host string) bool {
+	return net.ParseIP(host) == nil && !strings.Contains(host, ".") && host != "localhost" && host != dockerHostGatewayName
+}
+
+// Get the path of the extra-scrape-jobs folder
+func (c *HyperdriveClient) getScrapeJobsDir() (string, error) {
+	dir, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, extraScrapeJobsDir))
+	if err != nil {
+		return "", fmt.Errorf("error expanding extra-scrape-jobs path: %w", err)
+	}
+	return dir, nil
+}
@@ -5,9 +5,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -15,6 +14,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/fatih/color"
 	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
 )
 
 const (
@@ -22,65 +22,186 @@ const (
 	nethermindPruneStarterCommand string          = "DELETE_ME"
 	nethermindAdminUrl            string          = "http://127.0.0.1:7434"
 
-	templatesDir       string = "/usr/share/hyperdrive/templates"
-	overrideSourceDir  string = "/usr/share/hyperdrive/override"
+	hyperdriveShareDir string = "/usr/share/hyperdrive"
+	templatesDir       string = hyperdriveShareDir + "/templates"
+	overrideSourceDir  string = hyperdriveShareDir + "/override"
 	overrideDir        string = "override"
 	runtimeDir         string = "runtime"
 	extraScrapeJobsDir string = "extra-scrape-jobs"
 )
 
+// The install script and installer package for a release, after they've been verified
+type InstallerArtifacts struct {
+	// The release version they belong to
+	Version string
+
+	// The install script
+	Script []byte
+
+	// The installer package the script installs from
+	Package []byte
+}
+
 // Install Hyperdrive
-func (c *HyperdriveClient) InstallService(verbose bool, noDeps bool, version string, path string, useLocalInstaller bool) error {
-	// Get installation script flags
-	flags := []string{
-		"-v", shellescape.Quote(version),
-	}
-	if path != "" {
-		flags = append(flags, fmt.Sprintf("-p %s", shellescape.Quote(path)))
+func (c *HyperdriveClient) InstallService(verbose bool, noDeps bool, version string, path string, useLocalInstaller bool, insecure bool) error {
+	var installer *InstallerArtifacts
+	var err error
+	if useLocalInstaller {
+		installer, err = LoadLocalInstaller(".", version, insecure)
+	} else {
+		installer, err = DownloadInstaller(version)
 	}
-	if noDeps {
-		flags = append(flags, "-d")
+	if err != nil {
+		return err
 	}
+	return c.RunInstaller(verbose, noDeps, path, installer)
+}
 
-	var script []byte
-	if useLocalInstaller {
-		// Make sure it exists
-		_, err := os.Stat(InstallerName)
-		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("local install script [%s] does not exist", InstallerName)
-		}
-		if err != nil {
-			return fmt.Errorf("error checking install script [%s]: %w", InstallerName, err)
-		}
+// Load the install script and installer package from a local directory, verifying them against the checksum manifest next to them unless insecure is set
+func LoadLocalInstaller(dir string, version string, insecure bool) (*InstallerArtifacts, error) {
+	// Make sure it exists
+	scriptPath := filepath.Join(dir, InstallerName)
+	_, err := os.Stat(scriptPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("local install script [%s] does not exist", scriptPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking install script [%s]: %w", scriptPath, err)
+	}
 
-		// Read it
-		script, err = os.ReadFile(InstallerName)
-		if err != nil {
-			return fmt.Errorf("error reading local install script [%s]: %w", InstallerName, err)
-		}
+	// Read it and the package
+	script, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local install script [%s]: %w", scriptPath, err)
+	}
+	pkgPath := filepath.Join(dir, InstallerPackageName)
+	pkg, err := os.ReadFile(pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local installer package [%s]: %w", pkgPath, err)
+	}
 
-		// Set the "local mode" flag
-		flags = append(flags, "-l")
+	// Verify them against the local checksum manifest
+	if insecure {
+		fmt.Printf("%sWARNING: Skipping verification of the local install script. Make sure you absolutely trust it.%s\n", terminal.ColorYellow, terminal.ColorReset)
 	} else {
-		// Download the installation script
-		resp, err := http.Get(fmt.Sprintf(InstallerURL, version))
+		manifest, err := loadLocalChecksumManifest(dir)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("unexpected http status downloading installation script: %d", resp.StatusCode)
+		err = verifyInstallerArtifact(InstallerName, script, manifest)
+		if err != nil {
+			return nil, err
 		}
-
-		// Sanity check that the script octet length matches content-length
-		script, err = io.ReadAll(resp.Body)
+		err = verifyInstallerArtifact(InstallerPackageName, pkg, manifest)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		if fmt.Sprint(len(script)) != resp.Header.Get("content-length") {
-			return fmt.Errorf("downloaded script length %d did not match content-length header %s", len(script), resp.Header.Get("content-length"))
-		}
+	return &InstallerArtifacts{
+		Version: version,
+		Script:  script,
+		Package: pkg,
+	}, nil
+}
+
+// Download the install script and installer package for a release from GitHub, verifying them against the release's signed checksum manifest
+func DownloadInstaller(version string) (*InstallerArtifacts, error) {
+	// Pin the version so the artifacts and the manifest all come from the same release
+	version, err := ResolveReleaseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestUrl := fmt.Sprintf(ChecksumManifestURL, version)
+	manifestData, err := DownloadReleaseArtifact(manifestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading checksum manifest: %w", err)
+	}
+	signature, err := DownloadReleaseArtifact(manifestUrl + SignatureExtension)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading checksum manifest signature: %w", err)
+	}
+	manifest, err := ParseChecksumManifest(manifestData, signature)
+	if err != nil {
+		return nil, err
+	}
+	err = manifest.VerifyVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying checksum manifest: %w", err)
+	}
+	return downloadVerifiedInstaller(version, fmt.Sprintf(InstallerURL, version), fmt.Sprintf(InstallerPackageURL, version), manifest)
+}
+
+// Download the install script and installer package from a release's assets, verifying them against its checksum manifest.
+// The manifest must already have been checked against the release with VerifyVersion.
+func DownloadInstallerFromRelease(release *ReleaseInfo, manifest *ChecksumManifest) (*InstallerArtifacts, error) {
+	scriptAsset := release.GetAsset(InstallerName)
+	packageAsset := release.GetAsset(InstallerPackageName)
+	if scriptAsset == nil || packageAsset == nil {
+		return nil, fmt.Errorf("release %s doesn't have an installer", release.TagName)
+	}
+	return downloadVerifiedInstaller(release.TagName, scriptAsset.DownloadUrl, packageAsset.DownloadUrl, manifest)
+}
+
+// Download the install script and installer package from the given URLs and verify them against a checksum manifest
+func downloadVerifiedInstaller(version string, scriptUrl string, packageUrl string, manifest *ChecksumManifest) (*InstallerArtifacts, error) {
+	fmt.Printf("Downloading %s...\n", InstallerName)
+	script, err := DownloadReleaseArtifact(scriptUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading installation script: %w", err)
+	}
+	err = verifyInstallerArtifact(InstallerName, script, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Downloading %s...\n", InstallerPackageName)
+	pkg, err := DownloadReleaseArtifact(packageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading installer package: %w", err)
+	}
+	err = verifyInstallerArtifact(InstallerPackageName, pkg, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InstallerArtifacts{
+		Version: version,
+		Script:  script,
+		Package: pkg,
+	}, nil
+}
+
+// Run a verified install script, which installs Hyperdrive from its installer package
+func (c *HyperdriveClient) RunInstaller(verbose bool, noDeps bool, path string, installer *InstallerArtifacts) error {
+	// Get installation script flags; the script always installs from the package next to it instead of downloading its own
+	flags := []string{
+		"-v", shellescape.Quote(installer.Version),
+	}
+	if path != "" {
+		flags = append(flags, fmt.Sprintf("-p %s", shellescape.Quote(path)))
+	}
+	if noDeps {
+		flags = append(flags, "-d")
+	}
+	flags = append(flags, "-l")
+
+	// Save the verified package so the script can install it
+	installDir, err := os.MkdirTemp("", "hyperdrive-install-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory for the installer package: %w", err)
+	}
+	defer os.RemoveAll(installDir)
+	err = os.WriteFile(filepath.Join(installDir, InstallerPackageName), installer.Package, 0644)
+	if err != nil {
+		return fmt.Errorf("error saving installer package: %w", err)
+	}
+
+	// Save the current installation so the upgrade can be rolled back
+	err = c.SnapshotInstallation(installer.Version)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't save the current installation for rollback: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
 	}
 
 	// Get the escalation command
@@ -89,11 +210,12 @@ func (c *HyperdriveClient) InstallService(verbose bool, noDeps bool, version str
 		return fmt.Errorf("error getting escalation command: %w", err)
 	}
 
-	// Initialize installation command
-	cmd := c.newCommand(fmt.Sprintf("%s sh -s -- %s", escalationCmd, strings.Join(flags, " ")))
+	// Initialize installation command; the script looks for the local package in its working directory
+	cmdText := fmt.Sprintf("cd %s && %s sh -s -- %s", shellescape.Quote(installDir), escalationCmd, strings.Join(flags, " "))
+	cmd := c.newCommand(cmdText)
 
 	// Pass the script to sh via its stdin fd
-	cmd.SetStdin(bytes.NewReader(script))
+	cmd.SetStdin(bytes.NewReader(installer.Script))
 
 	// Get command output pipes
 	cmdOut, err := cmd.StdoutPipe()
@@ -134,6 +256,36 @@ func (c *HyperdriveClient) InstallService(verbose bool, noDeps bool, version str
 	return nil
 }
 
+// Load and check the signed checksum manifest in a local directory
+func loadLocalChecksumManifest(dir string) (*ChecksumManifest, error) {
+	manifestPath := filepath.Join(dir, ChecksumManifestName)
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local checksum manifest [%s]: %w", manifestPath, err)
+	}
+	signature, err := os.ReadFile(manifestPath + SignatureExtension)
+	if err != nil {
+		return nil, fmt.Errorf("error reading local checksum manifest signature [%s]: %w", manifestPath+SignatureExtension, err)
+	}
+	return ParseChecksumManifest(manifest, signature)
+}
+
+// Verify an installer artifact against a signed checksum manifest, failing closed on any mismatch
+func verifyInstallerArtifact(name string, data []byte, manifest *ChecksumManifest) error {
+	fingerprint, err := GetReleaseSigningKeyFingerprint()
+	if err != nil {
+		return fmt.Errorf("can't verify %s: %w", name, err)
+	}
+	fmt.Printf("Verifying %s with release signing key %s... ", name, fingerprint)
+	err = manifest.VerifyArtifact(name, data)
+	if err != nil {
+		fmt.Println()
+		return fmt.Errorf("%s failed verification, refusing to run it: %w", name, err)
+	}
+	fmt.Println("verified.")
+	return nil
+}
+
 // Start the Hyperdrive service
 func (c *HyperdriveClient) StartService(composeFiles []string) error {
 	cmd, err := c.compose(composeFiles, "up -d --remove-orphans --quiet-pull")
@@ -161,41 +313,6 @@ func (c *HyperdriveClient) StopService(composeFiles []string) error {
 	return c.printOutput(cmd)
 }
 
-// Stop Hyperdrive and remove the config folder
-func (c *HyperdriveClient) TerminateService(composeFiles []string, configPath string) error {
-	// Get the command to run with root privileges
-	rootCmd, err := c.getEscalationCommand()
-	if err != nil {
-		return fmt.Errorf("could not get privilege escalation command: %w", err)
-	}
-
-	// Terminate the Docker containers
-	cmd, err := c.compose(composeFiles, "down -v")
-	if err != nil {
-		return fmt.Errorf("error creating Docker artifact removal command: %w", err)
-	}
-	err = c.printOutput(cmd)
-	if err != nil {
-		return fmt.Errorf("error removing Docker artifacts: %w", err)
-	}
-
-	// Delete the Hyperdrive directory
-	path, err := homedir.Expand(configPath)
-	if err != nil {
-		return fmt.Errorf("error loading Hyperdrive directory: %w", err)
-	}
-	fmt.Printf("Deleting Hyperdrive directory (%s)...\n", path)
-	cmd = fmt.Sprintf("%s rm -rf %s", rootCmd, path)
-	_, err = c.readOutput(cmd)
-	if err != nil {
-		return fmt.Errorf("error deleting Hyperdrive directory: %w", err)
-	}
-
-	fmt.Println("Termination complete.")
-
-	return nil
-}
-
 // Print the Hyperdrive service status
 func (c *HyperdriveClient) PrintServiceStatus(composeFiles []string) error {
 	cmd, err := c.compose(composeFiles, "ps")
@@ -238,7 +355,11 @@ func (c *HyperdriveClient) PrintServiceStats(composeFiles []string) error {
 	containerIds := strings.Split(strings.TrimSpace(string(containers)), "\n")
 
 	// Print stats
-	return c.printOutput(fmt.Sprintf("docker stats %s", strings.Join(containerIds, " ")))
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return err
+	}
+	return c.printOutput(fmt.Sprintf("%s stats %s", runtimeCmd, strings.Join(containerIds, " ")))
 }
 
 // Print the Hyperdrive service compose config
@@ -271,8 +392,8 @@ func (c *HyperdriveClient) GetServiceVersion() (string, error) {
 
 // Deletes the data directory, including the node wallet and all validator keys, and restarts the Docker containers
 func (c *HyperdriveClient) PurgeData(composeFiles []string) error {
-	// Get the command to run with root privileges
-	rootCmd, err := c.getEscalationCommand()
+	// Get the command to run with the privileges of the container runtime
+	rootCmd, err := c.getRuntimeEscalationCommand()
 	if err != nil {
 		return fmt.Errorf("could not get privilege escalation command: %w", err)
 	}
@@ -316,7 +437,11 @@ func (c *HyperdriveClient) PurgeData(composeFiles []string) error {
 // Runs the prune provisioner
 func (c *HyperdriveClient) RunPruneProvisioner(container string, volume string, image string) error {
 	// Run the prune provisioner
-	cmd := fmt.Sprintf("docker run --rm --name %s -v %s:/ethclient %s", container, volume, image)
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("%s run --rm --name %s -v %s:/ethclient %s", runtimeCmd, container, volume, image)
 	output, err := c.readOutput(cmd)
 	if err != nil {
 		return err
@@ -332,8 +457,12 @@ func (c *HyperdriveClient) RunPruneProvisioner(container string, volume string,
 
 // Runs the prune provisioner
 func (c *HyperdriveClient) RunNethermindPruneStarter(container string) error {
-	cmd := fmt.Sprintf("docker exec %s %s %s", container, nethermindPruneStarterCommand, nethermindAdminUrl)
-	err := c.printOutput(cmd)
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("%s exec %s %s %s", runtimeCmd, container, nethermindPruneStarterCommand, nethermindAdminUrl)
+	err = c.printOutput(cmd)
 	if err != nil {
 		return err
 	}
@@ -342,8 +471,12 @@ func (c *HyperdriveClient) RunNethermindPruneStarter(container string) error {
 
 // Runs the EC migrator
 func (c *HyperdriveClient) RunEcMigrator(container string, volume string, targetDir string, mode string, image string) error {
-	cmd := fmt.Sprintf("docker run --rm --name %s -v %s:/ethclient -v %s:/mnt/external -e EC_MIGRATE_MODE='%s' %s", container, volume, targetDir, mode, image)
-	err := c.printOutput(cmd)
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("%s run --rm --name %s -v %s:/ethclient -v %s:/mnt/external -e EC_MIGRATE_MODE='%s' %s", runtimeCmd, container, volume, targetDir, mode, image)
+	err = c.printOutput(cmd)
 	if err != nil {
 		return err
 	}
@@ -353,7 +486,11 @@ func (c *HyperdriveClient) RunEcMigrator(container string, volume string, target
 
 // Gets the size of the target directory via the EC migrator for importing, which should have the same permissions as exporting
 func (c *HyperdriveClient) GetDirSizeViaEcMigrator(container string, targetDir string, image string) (uint64, error) {
-	cmd := fmt.Sprintf("docker run --rm --name %s -v %s:/mnt/external -e OPERATION='size' %s", container, targetDir, image)
+	runtimeCmd, err := c.getRuntimeCommand()
+	if err != nil {
+		return 0, err
+	}
+	cmd := fmt.Sprintf("%s run --rm --name %s -v %s:/mnt/external -e OPERATION='size' %s", runtimeCmd, container, targetDir, image)
 	output, err := c.readOutput(cmd)
 	if err != nil {
 		return 0, fmt.Errorf("error getting source directory size: %w", err)
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	SyncHistoryFile string = "sync-history.json"
+
+	// The max number of samples to keep for each client
+	maxSyncHistorySamples int = 120
+)
+
+// A single sync progress sample
+type SyncSample struct {
+	// The time the sample was taken
+	Time time.Time `json:"time"`
+
+	// The sync progress, from 0 to 1
+	Progress float64 `json:"progress"`
+}
+
+// Recent sync progress samples for each client, used to estimate sync rates across separate runs
+type SyncHistory struct {
+	Clients map[string][]SyncSample `json:"clients"`
+}
+
+// Add a sample for a client, discarding its history if the client's progress went backwards (e.g. after a resync)
+func (h *SyncHistory) AddSample(clientKey string, progress float64, sampleTime time.Time) {
+	samples := h.Clients[clientKey]
+	if len(samples) > 0 && progress < samples[len(samples)-1].Progress {
+		samples = nil
+	}
+	samples = append(samples, SyncSample{
+		Time:     sampleTime,
+		Progress: progress,
+	})
+	if len(samples) > maxSyncHistorySamples {
+		samples = samples[len(samples)-maxSyncHistorySamples:]
+	}
+	h.Clients[clientKey] = samples
+}
+
+// Load the sync history file from the config directory, returning an empty history if it doesn't exist yet
+func (c *HyperdriveClient) LoadSyncHistory() (*SyncHistory, error) {
+	history := &SyncHistory{
+		Clients: map[string][]SyncSample{},
+	}
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, SyncHistoryFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding sync history file path: %w", err)
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading sync history file [%s]: %w", path, err)
+	}
+
+	err = json.Unmarshal(bytes, history)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing sync history file [%s]: %w", path, err)
+	}
+	if history.Clients == nil {
+		history.Clients = map[string][]SyncSample{}
+	}
+	return history, nil
+}
+
+// Save the sync history file to the config directory
+func (c *HyperdriveClient) SaveSyncHistory(history *SyncHistory) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, SyncHistoryFile))
+	if err != nil {
+		return fmt.Errorf("error expanding sync history file path: %w", err)
+	}
+
+	bytes, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("error serializing sync history: %w", err)
+	}
+	err = os.WriteFile(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing sync history file [%s]: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+)
+
+const (
+	SystemdUnitDir string = "/etc/systemd/system"
+
+	systemdAskPasswordCommand string = "systemd-ask-password"
+	systemdStopTimeout        string = "10min"
+)
+
+// The settings used to generate Hyperdrive's systemd unit
+type SystemdUnitOptions struct {
+	// The path of the Hyperdrive CLI binary
+	CliPath string
+
+	// The Hyperdrive config directory
+	ConfigPath string
+
+	// The user that runs the unit, which must be able to use the container runtime
+	User *user.User
+
+	// Additional compose files to include
+	ComposeFiles []string
+
+	// How long to wait for the daemon to respond after starting before the unit fails
+	HealthTimeout time.Duration
+}
+
+// Get the name of the systemd unit for the configured project
+func (c *GlobalConfig) GetSystemdUnitName() string {
+	return fmt.Sprintf("%s.service", c.Hyperdrive.ProjectName.Value)
+}
+
+// Get the name of the systemd unit that prompts for the node wallet password at boot
+func (c *GlobalConfig) GetSystemdUnlockUnitName() string {
+	return fmt.Sprintf("%s-unlock.service", c.Hyperdrive.ProjectName.Value)
+}
+
+// Create the contents of a systemd unit that starts the project's containers at boot.
+// The unit counts as started as soon as the CLI runs, so boot doesn't wait on a slashing prevention delay or a slow daemon.
+func (c *GlobalConfig) CreateSystemdUnit(opts SystemdUnitOptions) string {
+	projectName := c.Hyperdrive.ProjectName.Value
+
+	// Build the CLI invocations, forwarding the global settings
+	globalArgs := []string{opts.CliPath, "--config-path", opts.ConfigPath}
+	if opts.User.Uid == "0" {
+		globalArgs = append(globalArgs, "--allow-root")
+	}
+	serviceArgs := append(globalArgs, "service")
+	for _, composeFile := range opts.ComposeFiles {
+		serviceArgs = append(serviceArgs, "--compose-file", composeFile)
+	}
+	startArgs := append(append([]string{}, serviceArgs...), "systemd", "run", "--timeout", opts.HealthTimeout.String())
+	stopArgs := append(append([]string{}, serviceArgs...), "stop", "--yes")
+
+	// Rootless runtimes belong to the user's service manager instead of a system service
+	after := []string{"network-online.target"}
+	environment := []string{}
+	if c.Cli.IsRootless() {
+		after = append(after, fmt.Sprintf("user@%s.service", opts.User.Uid))
+		environment = append(environment, runtimeDirEnvVar+"="+fmt.Sprintf(runtimeDirFormat, opts.User.Uid))
+	} else {
+		after = append(after, "docker.service")
+	}
+
+	lines := []string{
+		"# Autogenerated by `hyperdrive service systemd install` - DO NOT MODIFY THIS FILE DIRECTLY",
+		"# Rerun that command to regenerate it after changing your settings.",
+		"",
+		"[Unit]",
+		fmt.Sprintf("Description=Hyperdrive node (%s)", projectName),
+		"Wants=" + strings.Join(after, " "),
+		"After=" + strings.Join(after, " "),
+		"",
+		"[Service]",
+		"Type=exec",
+		"RemainAfterExit=yes",
+		"User=" + opts.User.Username,
+	}
+	for _, variable := range environment {
+		lines = append(lines, "Environment="+quoteSystemdArg(variable))
+	}
+	lines = append(lines,
+		"ExecStart="+joinSystemdArgs(startArgs),
+		"ExecStop="+joinSystemdArgs(stopArgs),
+		"TimeoutStopSec="+systemdStopTimeout,
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+		"",
+	)
+	return strings.Join(lines, "\n")
+}
+
+// Create the contents of a systemd unit that prompts for the node wallet password once the project's unit has started the daemon.
+// It's pulled in by the project's unit instead of multi-user.target, so boot doesn't wait for someone to answer the prompt.
+func (c *GlobalConfig) CreateSystemdUnlockUnit(opts SystemdUnitOptions) string {
+	projectName := c.Hyperdrive.ProjectName.Value
+	serviceUnit := c.GetSystemdUnitName()
+
+	// Prompting through the system's password agents requires root, so this unit runs with full privileges
+	unlockArgs := []string{opts.CliPath, "--allow-root", "--config-path", opts.ConfigPath, "service", "systemd", "unlock", "--timeout", opts.HealthTimeout.String()}
+	lines := []string{
+		"# Autogenerated by `hyperdrive service systemd install` - DO NOT MODIFY THIS FILE DIRECTLY",
+		"# Rerun that command to regenerate it after changing your settings.",
+		"",
+		"[Unit]",
+		fmt.Sprintf("Description=Hyperdrive node wallet unlock (%s)", projectName),
+		"After=" + serviceUnit,
+		"",
+		"[Service]",
+		"Type=exec",
+		"ExecStart=" + joinSystemdArgs(unlockArgs),
+		"",
+		"[Install]",
+		"WantedBy=" + serviceUnit,
+		"",
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Get the user that should run the systemd unit, which is the user that invoked sudo if there is one
+func GetSystemdUnitUser() (*user.User, error) {
+	sudoUser := os.Getenv("SUDO_USER")
+	if sudoUser != "" {
+		unitUser, err := user.Lookup(sudoUser)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up user [%s]: %w", sudoUser, err)
+		}
+		return unitUser, nil
+	}
+	unitUser, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current user: %w", err)
+	}
+	return unitUser, nil
+}
+
+// Install a systemd unit and enable it so it runs at boot
+func (c *HyperdriveClient) InstallSystemdUnit(name string, contents string) error {
+	tmpPath, err := stageSystemdFile(contents)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	unitPath := shellescape.Quote(filepath.Join(SystemdUnitDir, name))
+	err = c.printOutput(fmt.Sprintf("%[1]s install -m 0644 %[2]s %[3]s && %[1]s systemctl daemon-reload && %[1]s systemctl enable %[4]s", escalationCmd, shellescape.Quote(tmpPath), unitPath, shellescape.Quote(name)))
+	if err != nil {
+		return fmt.Errorf("error installing systemd unit [%s]: %w", name, err)
+	}
+	return nil
+}
+
+// Install a systemd service and the timer that triggers it, then start the timer
+func (c *HyperdriveClient) InstallSystemdTimer(serviceName string, serviceContents string, timerName string, timerContents string) error {
+	servicePath, err := stageSystemdFile(serviceContents)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(servicePath)
+	timerPath, err := stageSystemdFile(timerContents)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(timerPath)
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	err = c.printOutput(fmt.Sprintf("%[1]s install -m 0644 %[2]s %[3]s && %[1]s install -m 0644 %[4]s %[5]s && %[1]s systemctl daemon-reload && %[1]s systemctl enable --now %[6]s",
+		escalationCmd,
+		shellescape.Quote(servicePath), shellescape.Quote(filepath.Join(SystemdUnitDir, serviceName)),
+		shellescape.Quote(timerPath), shellescape.Quote(filepath.Join(SystemdUnitDir, timerName)),
+		shellescape.Quote(timerName),
+	))
+	if err != nil {
+		return fmt.Errorf("error installing systemd timer [%s]: %w", timerName, err)
+	}
+	return nil
+}
+
+// Disable a systemd unit and remove it
+func (c *HyperdriveClient) UninstallSystemdUnit(name string) error {
+	unitPath := filepath.Join(SystemdUnitDir, name)
+	_, err := os.Stat(unitPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("there's no systemd unit at %s", unitPath)
+	}
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	err = c.printOutput(fmt.Sprintf("%[1]s systemctl disable %[2]s && %[1]s rm -f %[3]s && %[1]s systemctl daemon-reload", escalationCmd, shellescape.Quote(name), shellescape.Quote(unitPath)))
+	if err != nil {
+		return fmt.Errorf("error removing systemd unit [%s]: %w", name, err)
+	}
+	return nil
+}
+
+// Stop and remove a systemd timer and the service it triggers
+func (c *HyperdriveClient) UninstallSystemdTimer(serviceName string, timerName string) error {
+	timerPath := filepath.Join(SystemdUnitDir, timerName)
+	_, err := os.Stat(timerPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("there's no systemd timer at %s", timerPath)
+	}
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	servicePath := filepath.Join(SystemdUnitDir, serviceName)
+	err = c.printOutput(fmt.Sprintf("%[1]s systemctl disable --now %[2]s && %[1]s rm -f %[3]s %[4]s && %[1]s systemctl daemon-reload", escalationCmd, shellescape.Quote(timerName), shellescape.Quote(timerPath), shellescape.Quote(servicePath)))
+	if err != nil {
+		return fmt.Errorf("error removing systemd timer [%s]: %w", timerName, err)
+	}
+	return nil
+}
+
+// Ask for a password through systemd's password agents, which works without a terminal (e.g. during boot).
+// The prompt shows up on the console and can be answered remotely with `sudo systemd-tty-ask-password-agent`.
+func AskSystemdPassword(id string, prompt string) (string, error) {
+	cmd := exec.Command(systemdAskPasswordCommand, "--id="+id, "--timeout=0", prompt)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error asking for password with %s: %w", systemdAskPasswordCommand, err)
+	}
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
+
+// Write the contents of a systemd file to the temp dir so it can be moved into place as root, returning its path
+func stageSystemdFile(contents string) (string, error) {
+	tmp, err := os.CreateTemp("", "hyperdrive-unit-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary systemd file: %w", err)
+	}
+	_, err = tmp.WriteString(contents)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing temporary systemd file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error closing temporary systemd file: %w", err)
+	}
+	return tmp.Name(), nil
+}
+
+// Join a command line for a systemd Exec directive
+func joinSystemdArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteSystemdArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Quote an argument for a systemd directive if it needs it, and escape systemd's specifiers and variables
+func quoteSystemdArg(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	arg = strings.ReplaceAll(arg, "$", "$$")
+	if arg == "" || strings.ContainsAny(arg, " \t\"'\\;") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
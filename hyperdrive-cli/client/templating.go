@@ -1,6 +1,12 @@
 package client
 
-import "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+import (
+	"fmt"
+	"strings"
+
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+	"github.com/rocket-pool/node-manager-core/config"
+)
 
 // Get the configs for all of the modules in the system that are enabled
 func (c *GlobalConfig) GetEnabledModuleConfigNames() []string {
@@ -14,9 +20,119 @@ func (c *GlobalConfig) GetEnabledModuleConfigNames() []string {
 }
 
 func (c *GlobalConfig) ModulesDirectory() string {
-	return config.ModulesName
+	return hdconfig.ModulesName
 }
 
 func (c *GlobalConfig) ValidatorsDirectory() string {
-	return config.ValidatorsDirectory
+	return hdconfig.ValidatorsDirectory
+}
+
+func (c *GlobalConfig) AlertmanagerContainerName() string {
+	return string(ContainerID_Alertmanager)
+}
+
+func (c *GlobalConfig) MevBoostContainerName() string {
+	return string(config.ContainerID_MevBoost)
+}
+
+func (c *GlobalConfig) ExtraAlertingRulesDirectory() string {
+	return extraAlertingRulesDir
+}
+
+func (c *GlobalConfig) GrafanaDashboardsDirectory() string {
+	return grafanaDashboardsDir
+}
+
+func (c *GlobalConfig) CustomDashboardsDirectory() string {
+	return customDashboardsDir
+}
+
+// Get the URL the Beacon Node should use to reach MEV-Boost, or a blank string if it's disabled
+func (c *GlobalConfig) GetMevBoostUrl() string {
+	mevBoost := c.Cli.MevBoost
+	if !mevBoost.Enable.Value {
+		return ""
+	}
+	if mevBoost.Mode.Value == config.ClientMode_External {
+		return mevBoost.ExternalUrl.Value
+	}
+	return fmt.Sprintf("http://%s:%d", c.MevBoostContainerName(), mevBoost.Port.Value)
+}
+
+// Get the URLs of the relays MEV-Boost should use, separated by commas
+func (c *GlobalConfig) GetMevBoostRelays() string {
+	return strings.Join(c.Cli.MevBoost.GetRelayUrls(c.Hyperdrive.Network.Value), ",")
+}
+
+// Get the name of the Prometheus job that scrapes the Execution Client
+func (c *GlobalConfig) GetEcMetricsJob() string {
+	if c.Hyperdrive.GetSelectedExecutionClient() == config.ExecutionClient_Geth {
+		return "geth"
+	}
+	return c.Hyperdrive.ExecutionClientContainerName()
+}
+
+// Get the metric with the selected Execution Client's latest block number
+func (c *GlobalConfig) GetEcHeadMetric() string {
+	switch c.Hyperdrive.GetSelectedExecutionClient() {
+	case config.ExecutionClient_Nethermind:
+		return "nethermind_blocks"
+	case config.ExecutionClient_Besu:
+		return "ethereum_blockchain_height"
+	case config.ExecutionClient_Reth:
+		return "reth_blockchain_tree_canonical_chain_height"
+	default:
+		return "chain_head_block"
+	}
+}
+
+// Get the metric with the selected Execution Client's peer count
+func (c *GlobalConfig) GetEcPeersMetric() string {
+	switch c.Hyperdrive.GetSelectedExecutionClient() {
+	case config.ExecutionClient_Nethermind:
+		return "nethermind_sync_peers"
+	case config.ExecutionClient_Besu:
+		return "ethereum_peer_count"
+	case config.ExecutionClient_Reth:
+		return "reth_network_connected_peers"
+	default:
+		return "p2p_peers"
+	}
+}
+
+// Get the query for the selected Beacon Node's peer count
+func (c *GlobalConfig) GetBnPeersQuery() string {
+	job := c.Hyperdrive.BeaconNodeContainerName()
+	if c.Hyperdrive.GetSelectedBeaconNode() == config.BeaconNode_Prysm {
+		return `sum by (job) (p2p_peer_count{job="` + job + `",state="Connected"})`
+	}
+	return `libp2p_peers{job="` + job + `"}`
+}
+
+// Get the human-readable name of the selected Beacon Node, such as Lighthouse
+func (c *GlobalConfig) GetBnName() string {
+	bn := c.Hyperdrive.GetSelectedBeaconNode()
+	for _, option := range c.Hyperdrive.LocalBeaconClient.BeaconNode.Options {
+		if option.Value == bn {
+			return option.Name
+		}
+	}
+	return string(bn)
+}
+
+// Get the selector for the counter of attestations the selected Validator Client has successfully published
+func (c *GlobalConfig) GetVcAttestationsSelector() string {
+	job := c.Stakewise.VcContainerName()
+	switch c.Hyperdrive.GetSelectedBeaconNode() {
+	case config.BeaconNode_Lodestar:
+		return `vc_published_attestations_total{job="` + job + `"}`
+	case config.BeaconNode_Nimbus:
+		return `beacon_attestations_sent_total{job="` + job + `"}`
+	case config.BeaconNode_Prysm:
+		return `validator_successful_attestations{job="` + job + `"}`
+	case config.BeaconNode_Teku:
+		return `validator_duties_performed_total{job="` + job + `",type="attestation",result="success"}`
+	default:
+		return `vc_signed_attestations_total{job="` + job + `",status="success"}`
+	}
 }
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/mitchellh/go-homedir"
+	hdconfig "github.com/nodeset-org/hyperdrive-daemon/shared/config"
+)
+
+// Options for which parts of the node to keep when terminating the service
+type TerminateOptions struct {
+	// Keep the Execution Client and Beacon Node chain data volumes
+	KeepChainData bool
+
+	// Keep the user data folder with the node wallet, validator keys, and slashing protection databases
+	KeepKeys bool
+
+	// Only remove the containers and networks, keeping every volume and the whole Hyperdrive directory
+	ContainersOnly bool
+}
+
+// What terminating the service will delete and what it will leave on disk
+type TerminatePlan struct {
+	// The options the plan was made with
+	Options TerminateOptions
+
+	// The Docker volumes that will be deleted
+	DeletedVolumes []string
+
+	// The Docker volumes that will be kept
+	KeptVolumes []string
+
+	// The files and directories that will be deleted
+	DeletedPaths []string
+
+	// The files and directories that will be kept
+	KeptPaths []string
+}
+
+// Work out what terminating the service with the given options will delete and what it will keep
+func (c *HyperdriveClient) GetTerminatePlan(configPath string, opts TerminateOptions) (*TerminatePlan, error) {
+	cfg, _, err := c.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading user settings: %w", err)
+	}
+	plan := &TerminatePlan{
+		Options:        opts,
+		DeletedVolumes: []string{},
+		KeptVolumes:    []string{},
+		DeletedPaths:   []string{},
+		KeptPaths:      []string{},
+	}
+
+	// Sort the volumes
+	volumes, err := c.listProjectVolumes(cfg.Hyperdrive.ProjectName.Value)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes {
+		composeName := volume.Labels[composeVolumeLabel]
+		isChainData := composeName == hdconfig.ExecutionClientDataVolume || composeName == hdconfig.BeaconNodeDataVolume
+		if opts.ContainersOnly || (opts.KeepChainData && isChainData) {
+			plan.KeptVolumes = append(plan.KeptVolumes, volume.Name)
+		} else {
+			plan.DeletedVolumes = append(plan.DeletedVolumes, volume.Name)
+		}
+	}
+
+	// Sort the Hyperdrive directory
+	expandedConfigPath, err := homedir.Expand(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading Hyperdrive directory: %w", err)
+	}
+	dataPath, err := homedir.Expand(cfg.Hyperdrive.UserDataPath.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error loading data path: %w", err)
+	}
+	if opts.ContainersOnly {
+		plan.KeptPaths = append(plan.KeptPaths, expandedConfigPath)
+		return plan, nil
+	}
+	relDataPath, err := filepath.Rel(expandedConfigPath, dataPath)
+	dataInConfig := err == nil && relDataPath != "." && !strings.HasPrefix(relDataPath, "..")
+	if !dataInConfig {
+		// The data folder lives somewhere else, so it's never deleted
+		plan.DeletedPaths = append(plan.DeletedPaths, expandedConfigPath)
+		plan.KeptPaths = append(plan.KeptPaths, dataPath)
+		return plan, nil
+	}
+	if !opts.KeepKeys {
+		plan.DeletedPaths = append(plan.DeletedPaths, expandedConfigPath)
+		return plan, nil
+	}
+
+	// Delete everything but the folder holding the data
+	keptEntry := strings.Split(relDataPath, string(filepath.Separator))[0]
+	entries, err := os.ReadDir(expandedConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Hyperdrive directory [%s]: %w", expandedConfigPath, err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(expandedConfigPath, entry.Name())
+		if entry.Name() == keptEntry {
+			plan.KeptPaths = append(plan.KeptPaths, path)
+		} else {
+			plan.DeletedPaths = append(plan.DeletedPaths, path)
+		}
+	}
+	return plan, nil
+}
+
+// Stop Hyperdrive and remove the parts of the node that the plan doesn't keep
+func (c *HyperdriveClient) TerminateService(composeFiles []string, plan *TerminatePlan) error {
+	// Get the command to run with the privileges of the container runtime
+	rootCmd, err := c.getRuntimeEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("could not get privilege escalation command: %w", err)
+	}
+
+	// Terminate the Docker containers, removing the volumes along with them if none are being kept
+	args := "down -v"
+	if len(plan.KeptVolumes) > 0 {
+		args = "down"
+	}
+	cmd, err := c.compose(composeFiles, args)
+	if err != nil {
+		return fmt.Errorf("error creating Docker artifact removal command: %w", err)
+	}
+	err = c.printOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("error removing Docker artifacts: %w", err)
+	}
+
+	// Delete the rest of the volumes individually
+	if len(plan.KeptVolumes) > 0 {
+		for _, volume := range plan.DeletedVolumes {
+			fmt.Printf("Deleting volume %s...\n", volume)
+			err = c.DeleteVolume(volume)
+			if err != nil {
+				return fmt.Errorf("error deleting volume [%s]: %w", volume, err)
+			}
+		}
+	}
+
+	// Delete the Hyperdrive directory
+	for _, path := range plan.DeletedPaths {
+		fmt.Printf("Deleting %s...\n", path)
+		cmd = fmt.Sprintf("%s rm -rf %s", rootCmd, shellescape.Quote(path))
+		_, err = c.readOutput(cmd)
+		if err != nil {
+			return fmt.Errorf("error deleting [%s]: %w", path, err)
+		}
+	}
+
+	fmt.Println("Termination complete.")
+
+	return nil
+}
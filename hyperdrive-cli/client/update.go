@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alessio/shellescape"
+)
+
+const (
+	// The suffix of the previous CLI binary, kept after an update for rollback
+	CliBackupSuffix string = ".old"
+)
+
+// Atomically replace the CLI binary at the given path with a new one, keeping the previous binary next to it for rollback.
+// Returns the path of the previous binary.
+func (c *HyperdriveClient) ReplaceCliBinary(path string, binary []byte) (string, error) {
+	backupPath := path + CliBackupSuffix
+	dir := filepath.Dir(path)
+	if isWritableDir(dir) {
+		// Stage the new binary next to the old one so the rename is atomic
+		tmp, err := stageCliBinary(dir, binary)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(tmp)
+
+		err = copyFile(path, backupPath)
+		if err != nil {
+			return "", fmt.Errorf("error backing up the current binary to [%s]: %w", backupPath, err)
+		}
+		err = os.Rename(tmp, path)
+		if err != nil {
+			return "", fmt.Errorf("error replacing [%s]: %w", path, err)
+		}
+		return backupPath, nil
+	}
+
+	// The binary is in a protected directory, so stage it in the temp dir and move it into place as root
+	tmp, err := stageCliBinary("", binary)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return "", fmt.Errorf("error getting escalation command: %w", err)
+	}
+	newPath := shellescape.Quote(path + ".new")
+	quotedPath := shellescape.Quote(path)
+	cmd := fmt.Sprintf("%[1]s cp %[2]s %[3]s && %[1]s chmod 755 %[3]s && %[1]s cp -p %[4]s %[5]s && %[1]s mv -f %[3]s %[4]s",
+		escalationCmd, shellescape.Quote(tmp), newPath, quotedPath, shellescape.Quote(backupPath),
+	)
+	err = c.printOutput(cmd)
+	if err != nil {
+		return "", fmt.Errorf("error replacing [%s]: %w", path, err)
+	}
+	return backupPath, nil
+}
+
+// Write a new CLI binary to a temporary executable file in the given directory, returning its path
+func stageCliBinary(dir string, binary []byte) (string, error) {
+	file, err := os.CreateTemp(dir, ".tmp-hyperdrive-cli-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file for the new binary: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(binary)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("error writing new binary to [%s]: %w", file.Name(), err)
+	}
+	err = file.Chmod(0755)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("error making [%s] executable: %w", file.Name(), err)
+	}
+	return file.Name(), nil
+}
+
+// Copy a file, preserving its permissions
+func copyFile(source string, dest string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Move the previous CLI binary that ReplaceCliBinary saved back into place, undoing the replacement
+func (c *HyperdriveClient) RestoreCliBinary(path string) error {
+	backupPath := path + CliBackupSuffix
+	if isWritableDir(filepath.Dir(path)) {
+		err := os.Rename(backupPath, path)
+		if err != nil {
+			return fmt.Errorf("error restoring [%s] from [%s]: %w", path, backupPath, err)
+		}
+		return nil
+	}
+
+	escalationCmd, err := c.getEscalationCommand()
+	if err != nil {
+		return fmt.Errorf("error getting escalation command: %w", err)
+	}
+	cmd := fmt.Sprintf("%s mv -f %s %s", escalationCmd, shellescape.Quote(backupPath), shellescape.Quote(path))
+	err = c.printOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("error restoring [%s] from [%s]: %w", path, backupPath, err)
+	}
+	return nil
+}
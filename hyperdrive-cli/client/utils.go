@@ -39,9 +39,42 @@ func LoadConfigFromFile(path string) (*GlobalConfig, error) {
 		return nil, fmt.Errorf("error loading module configs from [%s]: %w", path, err)
 	}
 
+	// Load the CLI settings, which the Hyperdrive config doesn't know about
+	configBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read Hyperdrive settings file at %s: %w", shellescape.Quote(path), err)
+	}
+	var settings struct {
+		Cli map[string]any `yaml:"cli"`
+	}
+	err = yaml.Unmarshal(configBytes, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse settings file: %w", err)
+	}
+	err = cfg.Cli.Deserialize(convertYamlMap(settings.Cli), hdCfg.Network.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error loading CLI config from [%s]: %w", path, err)
+	}
+
 	return cfg, nil
 }
 
+// Convert the nested maps that yaml.v2 produces into the string-keyed maps the config deserializer expects
+func convertYamlMap(in map[string]any) map[string]any {
+	for key, value := range in {
+		nested, ok := value.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		converted := map[string]any{}
+		for nestedKey, nestedValue := range nested {
+			converted[fmt.Sprint(nestedKey)] = nestedValue
+		}
+		in[key] = convertYamlMap(converted)
+	}
+	return in
+}
+
 // Saves a config
 func SaveConfig(cfg *GlobalConfig, directory string, filename string) error {
 	path := filepath.Join(directory, filename)
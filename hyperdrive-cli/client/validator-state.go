@@ -0,0 +1,152 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	ValidatorStateFile string = "vc-state.json"
+)
+
+// The last known state of a Validator Client container, used to enforce the anti-slashing delay
+// even if the container has been removed or recreated since it was stopped
+type ValidatorClientState struct {
+	// The client type (image name) the VC was last running
+	ClientType string `json:"clientType"`
+
+	// The full image tag the VC was last running
+	Image string `json:"image"`
+
+	// The time the VC was stopped, or zero if it was last seen running
+	StopTime time.Time `json:"stopTime"`
+
+	// The time at which a pending client change can safely be started, or zero if there isn't one
+	SafeStartTime time.Time `json:"safeStartTime"`
+}
+
+// Persistent record of all of the project's Validator Clients
+type ValidatorState struct {
+	Clients map[string]*ValidatorClientState `json:"clients"`
+}
+
+// Load the VC state file from the config directory, returning an empty state if it doesn't exist yet
+func (c *HyperdriveClient) LoadValidatorState() (*ValidatorState, error) {
+	state := &ValidatorState{
+		Clients: map[string]*ValidatorClientState{},
+	}
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, ValidatorStateFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding validator state file path: %w", err)
+	}
+
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading validator state file [%s]: %w", path, err)
+	}
+
+	err = json.Unmarshal(bytes, state)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing validator state file [%s]: %w", path, err)
+	}
+	if state.Clients == nil {
+		state.Clients = map[string]*ValidatorClientState{}
+	}
+	return state, nil
+}
+
+// Save the VC state file to the config directory
+func (c *HyperdriveClient) SaveValidatorState(state *ValidatorState) error {
+	path, err := homedir.Expand(filepath.Join(c.Context.ConfigPath, ValidatorStateFile))
+	if err != nil {
+		return fmt.Errorf("error expanding validator state file path: %w", err)
+	}
+
+	bytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing validator state: %w", err)
+	}
+
+	// Write to a temp file first so an interruption can't leave a partial file behind
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing validator state file [%s]: %w", tmpPath, err)
+	}
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("error replacing validator state file [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Record that the given VCs have been stopped, along with the client type they were running
+func (c *HyperdriveClient) RecordValidatorsStopped(vcNames []string, stopTime time.Time, clientTypes map[string]string, images map[string]string) error {
+	state, err := c.LoadValidatorState()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range vcNames {
+		vcState, exists := state.Clients[name]
+		if !exists {
+			vcState = &ValidatorClientState{}
+			state.Clients[name] = vcState
+		}
+		if clientType := clientTypes[name]; clientType != "" {
+			vcState.ClientType = clientType
+		}
+		if image := images[name]; image != "" {
+			vcState.Image = image
+		}
+		// Never move a recorded stop time backwards, the most recent one is the one that matters
+		if stopTime.After(vcState.StopTime) {
+			vcState.StopTime = stopTime
+		}
+	}
+	return c.SaveValidatorState(state)
+}
+
+// Record that the given VCs are running the provided client types, clearing any pending delays
+func (c *HyperdriveClient) RecordValidatorsStarted(clientTypes map[string]string, images map[string]string) error {
+	state, err := c.LoadValidatorState()
+	if err != nil {
+		return err
+	}
+
+	for name, clientType := range clientTypes {
+		state.Clients[name] = &ValidatorClientState{
+			ClientType: clientType,
+			Image:      images[name],
+		}
+	}
+	return c.SaveValidatorState(state)
+}
+
+// Record the time at which the given VCs can be safely started after a client change
+func (c *HyperdriveClient) RecordValidatorSafeStartTime(vcNames []string, safeStartTime time.Time) error {
+	state, err := c.LoadValidatorState()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range vcNames {
+		vcState, exists := state.Clients[name]
+		if !exists {
+			vcState = &ValidatorClientState{}
+			state.Clients[name] = vcState
+		}
+		vcState.SafeStartTime = safeStartTime
+	}
+	return c.SaveValidatorState(state)
+}
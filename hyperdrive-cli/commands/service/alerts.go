@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	alertsCronFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "cron",
+		Usage: "Schedule the collection with cron instead of a systemd timer",
+	}
+)
+
+// Collect the metrics for the alerting rules that Prometheus can't scrape directly
+func collectAlertMetrics(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	sw := client.NewStakewiseClientFromCtx(c)
+	metrics, err := hd.CollectAlertMetrics(sw)
+	if err != nil {
+		return err
+	}
+	notifyRestartLoops(hd, metrics.Containers)
+
+	// Print what was collected
+	if metrics.DaemonUp {
+		fmt.Printf("Hyperdrive daemon: %sresponding%s\n", terminal.ColorGreen, terminal.ColorReset)
+	} else {
+		fmt.Printf("Hyperdrive daemon: %snot responding%s\n", terminal.ColorRed, terminal.ColorReset)
+	}
+	if metrics.WalletBalance != nil {
+		fmt.Printf("Node wallet balance: %.6f ETH\n", *metrics.WalletBalance)
+	}
+	if metrics.RequiredBalance != nil {
+		fmt.Printf("Required wallet balance: %.6f ETH\n", *metrics.RequiredBalance)
+	}
+	for _, container := range metrics.Containers {
+		if container.Running {
+			fmt.Printf("%s: %srunning%s (%d restarts)\n", container.Name, terminal.ColorGreen, terminal.ColorReset, container.Restarts)
+		} else if container.Restarting {
+			fmt.Printf("%s: %srestarting%s (%d restarts)\n", container.Name, terminal.ColorRed, terminal.ColorReset, container.Restarts)
+		} else {
+			fmt.Printf("%s: %snot running%s\n", container.Name, terminal.ColorRed, terminal.ColorReset)
+		}
+	}
+	return nil
+}
+
+// Install a systemd timer or cron entry that collects the alerting metrics every 5 minutes
+func scheduleAlertMetrics(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before scheduling the alerting metrics")
+	}
+	if !cfg.Hyperdrive.Metrics.EnableMetrics.Value || !cfg.Cli.Alerting.EnableAlerting.Value {
+		fmt.Printf("%sWARNING: Metrics or alerting are disabled, so nothing will alert on these metrics until you enable both in the Monitoring / Metrics section of `hyperdrive service config`.%s\n\n", terminal.ColorYellow, terminal.ColorReset)
+	}
+
+	// Get the settings for the job
+	cliPath, configPath, jobUser, err := getScheduledJobEnvironment(hd, cfg)
+	if err != nil {
+		return err
+	}
+	job := cfg.GetAlertMetricsJob(cliPath, configPath, jobUser)
+
+	// Show what will be installed
+	useSystemd := useSystemdForJob(c.Bool(alertsCronFlag.Name))
+	printScheduledJob(job, useSystemd)
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("Hyperdrive will check your daemons and node wallet every 5 minutes as %s. Are you sure you want to continue?", jobUser.Username))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Install the job
+	return installScheduledJob(hd, job, useSystemd)
+}
+
+// Remove the systemd timer or cron entry that collects the alerting metrics
+func unscheduleAlertMetrics(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	job := cfg.GetAlertMetricsJob("", "", nil)
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Hyperdrive will stop collecting the wallet balance and daemon metrics, so their alerts will no longer fire. Are you sure you want to continue?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	removed, err := uninstallScheduledJob(hd, job)
+	if err != nil {
+		return err
+	}
+	if removed {
+		return nil
+	}
+	fmt.Println("The alerting metrics aren't scheduled.")
+	return nil
+}
+
+// Install the job that collects the alerting metrics if alerting is enabled and it isn't installed yet, so the wallet balance and daemon alerts work without a separate step
+func scheduleAlertMetricsIfMissing(hd *client.HyperdriveClient, cfg *client.GlobalConfig) {
+	if !cfg.Hyperdrive.Metrics.EnableMetrics.Value || !cfg.Cli.Alerting.EnableAlerting.Value {
+		return
+	}
+	if isScheduledJobInstalled(cfg.GetAlertMetricsJob("", "", nil)) {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Alerting is enabled, so Hyperdrive will check your daemons and node wallet every 5 minutes for its alerts.")
+	cliPath, configPath, jobUser, err := getScheduledJobEnvironment(hd, cfg)
+	if err == nil {
+		err = installScheduledJob(hd, cfg.GetAlertMetricsJob(cliPath, configPath, jobUser), useSystemdForJob(false))
+	}
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't schedule the alerting metrics, so the wallet balance and daemon alerts won't fire: %s\nRun `hyperdrive service alerts schedule` to try again.%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+	}
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	cliwallet "github.com/nodeset-org/hyperdrive/hyperdrive-cli/commands/wallet"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	restoreDaemonTimeout time.Duration = 2 * time.Minute
+)
+
+var (
+	backupOutputFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   fmt.Sprintf("The path to save the backup to. Defaults to a file named after the project, network, and time in the current directory, ending with %s", client.BackupExtension),
+	}
+	backupIncludePasswordFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "include-password",
+		Usage: "Include the saved node wallet password in the backup, so the wallet loads automatically after it's restored",
+	}
+	backupScheduleFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "schedule",
+		Aliases: []string{"s"},
+		Usage:   fmt.Sprintf("How often to back up the node (%s, %s, or %s)", client.BackupSchedule_Hourly, client.BackupSchedule_Daily, client.BackupSchedule_Weekly),
+		Value:   string(client.BackupSchedule_Daily),
+	}
+	backupCronFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "cron",
+		Usage: "Schedule backups with cron instead of a systemd timer",
+	}
+	restoreForceFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "force",
+		Aliases: []string{"f"},
+		Usage:   fmt.Sprintf("%sRestore the backup even if there's already a node wallet on this machine or it's configured for a different network. The existing wallet will be overwritten!%s", terminal.ColorRed, terminal.ColorReset),
+	}
+)
+
+// Create an encrypted backup of the node
+func createBackup(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before backing it up")
+	}
+
+	// Get the output path
+	outputPath := c.String(backupOutputFlag.Name)
+	if outputPath == "" {
+		outputPath = cfg.GetDefaultBackupFilename()
+	}
+	_, err = os.Stat(outputPath)
+	if err == nil {
+		return fmt.Errorf("[%s] already exists, refusing to overwrite it", outputPath)
+	}
+	if c.Bool(backupIncludePasswordFlag.Name) {
+		fmt.Printf("%sNOTE: The backup will include your saved node wallet password, so anyone with the backup and its passphrase can use your node wallet.%s\n\n", terminal.ColorYellow, terminal.ColorReset)
+	}
+
+	// Create the backup
+	passphrase := utils.PromptNewBackupPassphrase()
+	manifest, err := hd.CreateBackup(outputPath, passphrase, c.Bool(backupIncludePasswordFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("%sSaved the backup to %s.%s\n", terminal.ColorGreen, outputPath, terminal.ColorReset)
+	printBackupManifest(manifest)
+	fmt.Println("Keep it, and its passphrase, somewhere safe and off of this machine. Restore it with `hyperdrive service restore`.")
+	return nil
+}
+
+// Create a backup with the saved passphrase and send it to the configured destinations
+func runBackup(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	_, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before backing it up")
+	}
+	return hd.RunScheduledBackup()
+}
+
+// Install a systemd timer or cron entry that backs up the node regularly
+func scheduleBackups(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before scheduling backups")
+	}
+	if len(cfg.Cli.Backup.GetTargets()) == 0 {
+		return fmt.Errorf("no backup destinations are configured. Please set a backup directory or S3 bucket in the CLI section of `hyperdrive service config` first")
+	}
+	errors := cfg.Cli.Backup.Validate()
+	if len(errors) > 0 {
+		return fmt.Errorf("your backup settings have errors, run `hyperdrive service config` to correct them:\n%s", strings.Join(errors, "\n"))
+	}
+	schedule := client.BackupSchedule(c.String(backupScheduleFlag.Name))
+	switch schedule {
+	case client.BackupSchedule_Hourly, client.BackupSchedule_Daily, client.BackupSchedule_Weekly:
+	default:
+		return fmt.Errorf("invalid schedule [%s]; it must be %s, %s, or %s", schedule, client.BackupSchedule_Hourly, client.BackupSchedule_Daily, client.BackupSchedule_Weekly)
+	}
+
+	// Get the settings for the job
+	cliPath, configPath, jobUser, err := getScheduledJobEnvironment(hd, cfg)
+	if err != nil {
+		return err
+	}
+	job := cfg.GetBackupJob(client.BackupScheduleOptions{
+		CliPath:    cliPath,
+		ConfigPath: configPath,
+		User:       jobUser,
+		Schedule:   schedule,
+	})
+
+	// Show what will be installed
+	useSystemd := useSystemdForJob(c.Bool(backupCronFlag.Name))
+	printScheduledJob(job, useSystemd)
+	for _, target := range cfg.Cli.Backup.GetTargets() {
+		fmt.Printf("Backups will be sent to %s.\n", target.GetName())
+	}
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("Hyperdrive will back up your node %s as %s. Are you sure you want to continue?", schedule, jobUser.Username))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Save the passphrase for the job to use
+	_, err = hd.LoadBackupPassphrase()
+	if err != nil || utils.Confirm("A backup passphrase has already been saved. Would you like to replace it?") {
+		passphrase := utils.PromptNewBackupPassphrase()
+		err = hd.SaveBackupPassphrase(passphrase)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved the passphrase to %s.\n", filepath.Join(configPath, client.BackupPassphraseFile))
+	}
+
+	// Install the job
+	err = installScheduledJob(hd, job, useSystemd)
+	if err != nil {
+		return err
+	}
+	fmt.Println("The result of the last backup is shown in `hyperdrive service status`.")
+	return nil
+}
+
+// Remove the systemd timer or cron entry that backs up the node
+func unscheduleBackups(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	job := cfg.GetBackupJob(client.BackupScheduleOptions{})
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Hyperdrive will no longer back up your node automatically. Existing backups won't be deleted. Are you sure you want to continue?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	removed, err := uninstallScheduledJob(hd, job)
+	if err != nil {
+		return err
+	}
+	if removed {
+		return nil
+	}
+	fmt.Println("Backups aren't scheduled.")
+	return nil
+}
+
+// Print the outcome of the last scheduled backup
+func printBackupStatus(hd *client.HyperdriveClient) {
+	status, err := hd.LoadBackupStatus()
+	if err != nil {
+		fmt.Printf("%sWARNING: couldn't get the backup status: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		return
+	}
+	if status == nil {
+		return
+	}
+
+	fmt.Println()
+	if status.LastError == "" {
+		fmt.Printf("Last backup: %ssucceeded%s at %s (%s)\n", terminal.ColorGreen, terminal.ColorReset, status.LastAttempt.Format(time.RFC1123), status.LastBackup)
+		return
+	}
+	fmt.Printf("Last backup: %sFAILED%s at %s\n", terminal.ColorRed, terminal.ColorReset, status.LastAttempt.Format(time.RFC1123))
+	fmt.Println(status.LastError)
+	if status.LastSuccess.IsZero() {
+		fmt.Println("No scheduled backup has succeeded yet.")
+	} else {
+		fmt.Printf("The last successful backup was %s at %s.\n", status.LastBackup, status.LastSuccess.Format(time.RFC1123))
+	}
+}
+
+// Restore an encrypted backup of the node
+func restoreBackup(c *cli.Context, backupPath string) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	force := c.Bool(restoreForceFlag.Name)
+
+	// Decrypt the backup
+	passphrase := utils.PromptPassword("Please enter the passphrase for the backup:", "^.+$", "Please enter the passphrase:")
+	fmt.Println("Decrypting backup...")
+	backup, err := client.OpenBackup(backupPath, passphrase)
+	if err != nil {
+		return err
+	}
+	manifest := backup.Manifest
+	printBackupManifest(manifest)
+	fmt.Println()
+
+	// Make sure it belongs on this machine
+	if !isNew && cfg.Hyperdrive.Network.Value != manifest.Network {
+		if !force {
+			return fmt.Errorf("this node is configured for %s but the backup is for %s; use `--force` if you want to switch to the backup's network", cfg.Hyperdrive.Network.Value, manifest.Network)
+		}
+		fmt.Printf("%sWARNING: This node will be switched from %s to %s.%s\n", terminal.ColorYellow, cfg.Hyperdrive.Network.Value, manifest.Network, terminal.ColorReset)
+	}
+	existingAddress, err := hd.GetNodeWalletAddressOnDisk()
+	if err != nil {
+		return err
+	}
+	if existingAddress != (common.Address{}) {
+		if !force {
+			return fmt.Errorf("there's already a node wallet on this machine (%s); use `--force` if you want to overwrite it with the backup", existingAddress.Hex())
+		}
+		if existingAddress != manifest.NodeAddress {
+			fmt.Printf("%sWARNING: The node wallet on this machine (%s) will be replaced with the one in the backup. Make sure you have its recovery mnemonic!%s\n", terminal.ColorRed, existingAddress.Hex(), terminal.ColorReset)
+		}
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("%sWARNING: Restoring this backup will replace your settings and overrides, and Hyperdrive will be restarted to start validating with the keys in it.\nMake sure these keys aren't running on any other machine, or you WILL be slashed!%s\nAre you sure you want to continue?", terminal.ColorRed, terminal.ColorReset))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Stop the containers so nothing writes to the data folder during the restore
+	if !isNew {
+		fmt.Println("Stopping containers...")
+		err = hd.PauseService(getComposeFiles(c))
+		if err != nil {
+			return fmt.Errorf("error stopping Docker containers: %w", err)
+		}
+	}
+	previousDataPath, err := hd.RestoreBackup(backup)
+	if err != nil {
+		return err
+	}
+	if previousDataPath != "" {
+		fmt.Printf("The previous data folder was moved to %s. Delete it once you're sure you don't need its node wallet or keys.\n", previousDataPath)
+	}
+
+	// The restored keys may have been validating on another machine until just now, so they have to wait out the slashing prevention delay
+	cfg, _, err = hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading restored user settings: %w", err)
+	}
+	err = delayRestoredValidators(hd, cfg)
+	if err != nil {
+		return err
+	}
+	_, remainingTime, err := checkForValidatorChange(hd, cfg)
+	if err != nil {
+		return fmt.Errorf("error verifying the Validator Clients can be safely started: %w", err)
+	}
+	if remainingTime > 0 {
+		notifySlashingDelayStarted(hd, remainingTime)
+		fmt.Printf("%sThe keys in this backup may have been validating on another machine until just now, so Hyperdrive will wait before starting them to prevent slashing.\n", terminal.ColorYellow)
+		fmt.Printf("If you stop this command, the delay still applies the next time you run `hyperdrive service start`.%s\n\n", terminal.ColorReset)
+		waitForSlashingDelay(remainingTime)
+		notifySlashingDelayEnded(hd)
+	}
+
+	// Start the containers with the restored settings
+	fmt.Println("Starting containers...")
+	err = hd.StartService(getComposeFiles(c))
+	if err != nil {
+		return fmt.Errorf("error starting Docker containers: %w", err)
+	}
+	recordValidatorsStarted(hd, cfg)
+	fmt.Println()
+	fmt.Printf("%sRestored the backup.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	if manifest.NodeAddress == (common.Address{}) {
+		return nil
+	}
+	return verifyRestoredWallet(hd, manifest)
+}
+
+// Record a slashing prevention delay for each of the enabled modules' Validator Clients, starting now
+func delayRestoredValidators(hd *client.HyperdriveClient, cfg *client.GlobalConfig) error {
+	vcNames := []string{}
+	for _, module := range cfg.GetAllModuleConfigs() {
+		if !module.IsEnabled() {
+			continue
+		}
+		for name := range module.GetValidatorContainerTagInfo() {
+			vcNames = append(vcNames, cfg.Hyperdrive.GetDockerArtifactName(string(name)))
+		}
+	}
+	err := hd.RecordValidatorSafeStartTime(vcNames, time.Now().Add(slashingDelay))
+	if err != nil {
+		return fmt.Errorf("error saving the slashing prevention delay for the restored keys: %w", err)
+	}
+	return nil
+}
+
+// Make sure the daemon loads the restored wallet, and optionally check it against the recovery mnemonic
+func verifyRestoredWallet(hd *client.HyperdriveClient, manifest *client.BackupManifest) error {
+	fmt.Println("Verifying the restored node wallet...")
+	startTime := time.Now()
+	for {
+		isReady, _, description := checkDaemonReady(hd)
+		if isReady {
+			break
+		}
+		if time.Since(startTime)+waitPollInterval > restoreDaemonTimeout {
+			return fmt.Errorf("timed out after %s waiting for the daemon (%s); check the wallet with `hyperdrive wallet status` once it's up", restoreDaemonTimeout, description)
+		}
+		time.Sleep(waitPollInterval)
+	}
+
+	// Load the wallet if its password wasn't restored
+	response, err := hd.Api.Wallet.Status()
+	if err != nil {
+		return fmt.Errorf("error getting wallet status: %w", err)
+	}
+	status := response.Data.WalletStatus
+	if !status.Wallet.IsLoaded && status.Wallet.IsOnDisk {
+		fmt.Println("The backup doesn't include the node wallet password, so it needs to be entered to load the wallet.")
+		password := cliwallet.PromptExistingPassword()
+		savePassword := utils.Confirm("Would you like to save the password to disk? If you do, your node will be able to handle transactions automatically after a client restart; otherwise, you will have to enter the password with `hyperdrive wallet set-password` after each restart.")
+		_, err = hd.Api.Wallet.SetPassword(password, savePassword)
+		if err != nil {
+			return fmt.Errorf("error setting password: %w", err)
+		}
+		response, err = hd.Api.Wallet.Status()
+		if err != nil {
+			return fmt.Errorf("error getting wallet status: %w", err)
+		}
+		status = response.Data.WalletStatus
+	}
+	if !status.Wallet.IsLoaded {
+		return fmt.Errorf("the restored node wallet couldn't be loaded; check the password and try `hyperdrive wallet set-password`")
+	}
+	if status.Wallet.WalletAddress != manifest.NodeAddress {
+		return fmt.Errorf("the daemon loaded node wallet %s, but the backup is for %s", status.Wallet.WalletAddress.Hex(), manifest.NodeAddress.Hex())
+	}
+	fmt.Printf("%sThe node wallet %s was restored and loaded successfully.%s\n", terminal.ColorGreen, manifest.NodeAddress.Hex(), terminal.ColorReset)
+
+	// Check it against the mnemonic like `wallet test-recovery` does
+	if !utils.Confirm("Would you like to check that the restored wallet matches your recovery mnemonic? This doesn't write any files.") {
+		return nil
+	}
+	mnemonic := strings.TrimSpace(cliwallet.PromptMnemonic())
+	fmt.Printf("Searching for the derivation path and index for wallet %s...\nNOTE: this may take several minutes depending on how large your wallet's index is.\n", manifest.NodeAddress.Hex())
+	recoverResponse, err := hd.Api.Wallet.TestSearchAndRecover(mnemonic, manifest.NodeAddress)
+	if err != nil {
+		return fmt.Errorf("the restored wallet couldn't be recovered from that mnemonic: %w", err)
+	}
+	fmt.Println("The restored node wallet matches your mnemonic.")
+	fmt.Printf("Derivation path: %s\n", recoverResponse.Data.DerivationPath)
+	fmt.Printf("Wallet index:    %d\n", recoverResponse.Data.Index)
+	return nil
+}
+
+// Print the details of a backup
+func printBackupManifest(manifest *client.BackupManifest) {
+	fmt.Printf("Created:      %s (Hyperdrive %s)\n", manifest.Created.Format(time.RFC1123), manifest.Version)
+	fmt.Printf("Project:      %s\n", manifest.ProjectName)
+	fmt.Printf("Network:      %s\n", manifest.Network)
+	if manifest.NodeAddress == (common.Address{}) {
+		fmt.Println("Node wallet:  none")
+	} else {
+		fmt.Printf("Node wallet:  %s\n", manifest.NodeAddress.Hex())
+	}
+	if manifest.IncludesPassword {
+		fmt.Println("Password:     included")
+	} else {
+		fmt.Println("Password:     not included")
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	bundleVersionFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "version",
+		Aliases: []string{"v"},
+		Usage:   "The Hyperdrive package version to bundle. This should match the version of the CLI that will install it.",
+		Value:   fmt.Sprintf("v%s", shared.HyperdriveVersion),
+	}
+	bundleOutputFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "The path to write the bundle to",
+		Value:   fmt.Sprintf("hyperdrive-bundle-v%s.tar", shared.HyperdriveVersion),
+	}
+	bundleFingerprintFlag *cli.StringFlag = &cli.StringFlag{
+		Name:  "bundle-fingerprint",
+		Usage: "The signing key fingerprint that was printed when the bundle was created. If this isn't provided, you'll be asked to confirm the bundle's fingerprint instead.",
+	}
+)
+
+// Create an offline installation bundle
+func createBundle(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+
+	version := c.String(bundleVersionFlag.Name)
+	outputPath := c.String(bundleOutputFlag.Name)
+	fmt.Printf("Creating an offline installation bundle for Hyperdrive %s with the images used by your current configuration.\n\n", version)
+	manifest, fingerprint, err := hd.CreateBundle(version, outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("%sCreated %s with %d images:%s\n", terminal.ColorGreen, outputPath, len(manifest.Images), terminal.ColorReset)
+	for _, image := range manifest.Images {
+		fmt.Printf("\t%s\n", image)
+	}
+	fmt.Println()
+	fmt.Printf("The bundle was signed with a new key, whose fingerprint is:\n\t%s%s%s\n", terminal.ColorGreen, fingerprint, terminal.ColorReset)
+	fmt.Println("Write it down somewhere other than the bundle itself; it's checked before the bundle's images are loaded, so a bundle that was changed after it was created will be rejected.")
+	fmt.Println()
+	fmt.Printf("Copy it and the Hyperdrive CLI to the offline machine, then run `hyperdrive service install --%s %s --%s %s`.\n", installBundleFlag.Name, outputPath, bundleFingerprintFlag.Name, fingerprint)
+	return nil
+}
+
+// Load the Docker images from an offline installation bundle
+func loadBundle(c *cli.Context, bundlePath string) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+
+	bundleDir, manifest, err := extractBundle(c, bundlePath, false)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(bundleDir)
+
+	err = hd.LoadBundleImages(bundleDir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d images from %s.\n", len(manifest.Images), bundlePath)
+	return nil
+}
+
+// Extract an offline installation bundle to a temporary directory and verify it, then return the directory, which the caller must remove
+func extractBundle(c *cli.Context, bundlePath string, insecure bool) (string, *client.BundleManifest, error) {
+	bundleDir, err := os.MkdirTemp("", "hyperdrive-bundle-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating temporary directory for bundle: %w", err)
+	}
+	fmt.Printf("Extracting %s...\n", bundlePath)
+	manifest, err := client.ExtractBundle(bundlePath, bundleDir)
+	if err == nil {
+		err = verifyBundle(c, bundleDir, manifest, insecure)
+	}
+	if err != nil {
+		os.RemoveAll(bundleDir)
+		return "", nil, err
+	}
+	return bundleDir, manifest, nil
+}
+
+// Verify an extracted bundle against the signing key fingerprint that was printed when it was created
+func verifyBundle(c *cli.Context, bundleDir string, manifest *client.BundleManifest, insecure bool) error {
+	if insecure {
+		fmt.Printf("%sWARNING: Skipping verification of the bundle. Make sure you absolutely trust its images.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		return nil
+	}
+
+	// Get the fingerprint to check against, asking the user to compare it if they didn't provide one
+	fingerprint := c.String(bundleFingerprintFlag.Name)
+	if fingerprint == "" {
+		bundleFingerprint, err := client.GetBundleSigningKeyFingerprint(bundleDir)
+		if err != nil {
+			return err
+		}
+		if c.Bool(utils.YesFlag.Name) {
+			return fmt.Errorf("the bundle's signing key fingerprint must be provided with --%s when using --%s", bundleFingerprintFlag.Name, utils.YesFlag.Name)
+		}
+		if !utils.Confirm(fmt.Sprintf("The bundle was signed by the key with the fingerprint %s.\nDoes this match the fingerprint that was printed when the bundle was created?", bundleFingerprint)) {
+			return fmt.Errorf("the bundle's signing key wasn't confirmed, so it can't be trusted")
+		}
+		fingerprint = bundleFingerprint
+	}
+
+	err := client.VerifyBundle(bundleDir, fingerprint, manifest)
+	if err != nil {
+		return fmt.Errorf("bundle failed verification, refusing to use it: %w", err)
+	}
+	fmt.Printf("%sVerified the bundle's manifest and images.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	return nil
+}
@@ -15,7 +15,7 @@ func checkCpuFeatures() error {
 			fmt.Printf("  - %s\n", name)
 		}
 
-		fmt.Println("\nYou must use the 'portable' image.")
+		fmt.Println("\nYou must use the 'portable' image. Hyperdrive will automatically switch any 'modern' images to their portable versions when it saves your configuration or starts the service.")
 		return nil
 	}
 
@@ -95,6 +95,10 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 					installPathFlag,
 					installVersionFlag,
 					installLocalFlag,
+					installInsecureFlag,
+					installBundleFlag,
+					bundleFingerprintFlag,
+					installRollbackFlag,
 				},
 				Action: func(c *cli.Context) error {
 					// Validate args
@@ -107,6 +111,51 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:  "bundle",
+				Usage: "Manage offline installation bundles",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "create",
+						Aliases: []string{"c"},
+						Usage:   "Create a bundle with the installer and all of the Docker images used by your configuration, for installing Hyperdrive without network access",
+						Flags: []cli.Flag{
+							bundleVersionFlag,
+							bundleOutputFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return createBundle(c)
+						},
+					},
+					{
+						Name:      "load",
+						Aliases:   []string{"l"},
+						Usage:     "Load the Docker images from a bundle without reinstalling Hyperdrive",
+						ArgsUsage: "bundle-file",
+						Flags: []cli.Flag{
+							utils.YesFlag,
+							bundleFingerprintFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 1); err != nil {
+								return err
+							}
+							bundlePath := c.Args().Get(0)
+
+							// Run command
+							return loadBundle(c, bundlePath)
+						},
+					},
+				},
+			},
+
 			{
 				Name:    "config",
 				Aliases: []string{"c"},
@@ -127,6 +176,9 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Name:    "sync",
 				Aliases: []string{"y"},
 				Usage:   "Get the sync progress of the Execution and Beacon Nodes",
+				Flags: []cli.Flag{
+					syncWatchFlag,
+				},
 				Action: func(c *cli.Context) error {
 					// Validate args
 					if err := utils.ValidateArgCount(c, 0); err != nil {
@@ -138,6 +190,49 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:  "wait",
+				Usage: "Wait until a condition is met, such as the clients being synced or the node wallet being loaded. Useful for scripts.",
+				Flags: []cli.Flag{
+					waitForFlag,
+					waitTimeoutFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run
+					return waitForCondition(c)
+				},
+			},
+
+			{
+				Name:  "fallback",
+				Usage: "Manage the fallback Execution Client and Beacon Node",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "test",
+						Aliases: []string{"t"},
+						Usage:   "Check that the fallback clients are reachable, on the right network, synced, and compatible with your Validator Client",
+						Flags: []cli.Flag{
+							fallbackDrillFlag,
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run
+							return testFallbackClients(c)
+						},
+					},
+				},
+			},
+
 			{
 				Name:    "status",
 				Aliases: []string{"u"},
@@ -159,6 +254,7 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Usage:   "Start the Hyperdrive service",
 				Flags: []cli.Flag{
 					ignoreSlashTimerFlag,
+					startDetachFlag,
 					wallet.PasswordFlag,
 					wallet.SavePasswordFlag,
 					utils.YesFlag,
@@ -174,6 +270,328 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:   scheduledStartCommand,
+				Usage:  "Start the Hyperdrive service once the pending slashing prevention delay has elapsed",
+				Hidden: true,
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run command
+					return runScheduledStart(c)
+				},
+			},
+
+			{
+				Name:  "systemd",
+				Usage: "Manage the systemd unit that starts Hyperdrive at boot",
+				Subcommands: []*cli.Command{
+					{
+						Name:    "install",
+						Aliases: []string{"i"},
+						Usage:   "Install and enable a systemd unit that starts Hyperdrive at boot, checks that it came up, and optionally prompts for the node wallet password",
+						Flags: []cli.Flag{
+							systemdAskPasswordFlag,
+							systemdHealthTimeoutFlag,
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return installSystemdUnit(c)
+						},
+					},
+					{
+						Name:    "uninstall",
+						Aliases: []string{"u"},
+						Usage:   "Disable and remove the systemd unit",
+						Flags: []cli.Flag{
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return uninstallSystemdUnit(c)
+						},
+					},
+					{
+						Name:   "run",
+						Usage:  "Start the Hyperdrive service and check that it came up",
+						Hidden: true,
+						Flags: []cli.Flag{
+							systemdHealthTimeoutFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return runSystemdStart(c)
+						},
+					},
+					{
+						Name:   "unlock",
+						Usage:  "Prompt for the node wallet password through systemd and load the wallet with it",
+						Hidden: true,
+						Flags: []cli.Flag{
+							systemdHealthTimeoutFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return unlockWalletAtBoot(c)
+						},
+					},
+				},
+			},
+
+			{
+				Name:  "backup",
+				Usage: "Create an encrypted backup of your node wallet, validator keys, deposit data, slashing protection databases, settings, and overrides",
+				Flags: []cli.Flag{
+					backupOutputFlag,
+					backupIncludePasswordFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run command
+					return createBackup(c)
+				},
+				Subcommands: []*cli.Command{
+					{
+						Name:  "run",
+						Usage: "Create a backup with the saved passphrase, send it to the destinations configured in the CLI section of `hyperdrive service config`, and delete expired backups",
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return runBackup(c)
+						},
+					},
+					{
+						Name:  "schedule",
+						Usage: "Install a systemd timer (or cron entry) that runs `hyperdrive service backup run` regularly",
+						Flags: []cli.Flag{
+							backupScheduleFlag,
+							backupCronFlag,
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return scheduleBackups(c)
+						},
+					},
+					{
+						Name:  "unschedule",
+						Usage: "Remove the systemd timer or cron entry that backs up the node",
+						Flags: []cli.Flag{
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return unscheduleBackups(c)
+						},
+					},
+				},
+			},
+
+			{
+				Name:  "alerts",
+				Usage: "Manage the metrics that the wallet balance and daemon alerts rely on",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "collect",
+						Usage: "Check the daemons and node wallet and write the results for Prometheus",
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return collectAlertMetrics(c)
+						},
+					},
+					{
+						Name:  "schedule",
+						Usage: "Install a systemd timer (or cron entry) that runs `hyperdrive service alerts collect` every 5 minutes",
+						Flags: []cli.Flag{
+							alertsCronFlag,
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return scheduleAlertMetrics(c)
+						},
+					},
+					{
+						Name:  "unschedule",
+						Usage: "Remove the systemd timer or cron entry that collects the alerting metrics",
+						Flags: []cli.Flag{
+							utils.YesFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return unscheduleAlertMetrics(c)
+						},
+					},
+				},
+			},
+
+			{
+				Name:  "notifications",
+				Usage: "Manage the event notifications sent to your webhooks",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "test",
+						Usage: "Send a sample notification to your webhooks, or to a specific URL",
+						Flags: []cli.Flag{
+							notificationsUrlFlag,
+							notificationsFormatFlag,
+						},
+						Action: func(c *cli.Context) error {
+							// Validate args
+							if err := utils.ValidateArgCount(c, 0); err != nil {
+								return err
+							}
+
+							// Run command
+							return testNotifications(c)
+						},
+					},
+				},
+			},
+
+			{
+				Name:  "metrics",
+				Usage: "Manage Hyperdrive's Prometheus metrics",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "scrape",
+						Usage: "Manage custom Prometheus scrape jobs in the extra-scrape-jobs folder",
+						Subcommands: []*cli.Command{
+							{
+								Name:      "add",
+								Usage:     "Have Prometheus scrape another container or host",
+								ArgsUsage: "name target",
+								Flags: []cli.Flag{
+									scrapePathFlag,
+									utils.YesFlag,
+								},
+								Action: func(c *cli.Context) error {
+									// Validate args
+									if err := utils.ValidateArgCount(c, 2); err != nil {
+										return err
+									}
+									name := c.Args().Get(0)
+									target := c.Args().Get(1)
+
+									// Run command
+									return addScrapeJob(c, name, target)
+								},
+							},
+							{
+								Name:    "list",
+								Aliases: []string{"l"},
+								Usage:   "List the custom scrape jobs and whether Prometheus can scrape them",
+								Action: func(c *cli.Context) error {
+									// Validate args
+									if err := utils.ValidateArgCount(c, 0); err != nil {
+										return err
+									}
+
+									// Run command
+									return listScrapeJobs(c)
+								},
+							},
+							{
+								Name:      "remove",
+								Aliases:   []string{"r"},
+								Usage:     "Remove a custom scrape job",
+								ArgsUsage: "name",
+								Flags: []cli.Flag{
+									utils.YesFlag,
+								},
+								Action: func(c *cli.Context) error {
+									// Validate args
+									if err := utils.ValidateArgCount(c, 1); err != nil {
+										return err
+									}
+									name := c.Args().Get(0)
+
+									// Run command
+									return removeScrapeJob(c, name)
+								},
+							},
+						},
+					},
+				},
+			},
+
+			{
+				Name:      "restore",
+				Usage:     "Restore an encrypted backup created with `hyperdrive service backup`",
+				ArgsUsage: "backup-file",
+				Flags: []cli.Flag{
+					restoreForceFlag,
+					utils.YesFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 1); err != nil {
+						return err
+					}
+					backupPath := c.Args().Get(0)
+
+					// Run command
+					return restoreBackup(c, backupPath)
+				},
+			},
+
 			{
 				Name:    "stop",
 				Aliases: []string{"pause", "p"},
@@ -295,6 +713,40 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				},
 			},
 
+			{
+				Name:  "preflight",
+				Usage: "Checks if this machine meets the requirements for running a node, including its hardware, Docker setup, clock sync, and port availability",
+				Flags: []cli.Flag{
+					preflightJsonFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run command
+					return runPreflight(c)
+				},
+			},
+
+			{
+				Name:  "release-notes",
+				Usage: "Show the release notes for the installed version of Hyperdrive",
+				Flags: []cli.Flag{
+					releaseNotesSinceFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run command
+					return showReleaseNotes(c)
+				},
+			},
+
 			{
 				Name:  "get-config-yaml",
 				Usage: "Generate YAML that shows the current configuration schema, including all of the parameters and their descriptions",
@@ -348,10 +800,38 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 					},
 				},
 			*/
+			{
+				Name:  "switch-client",
+				Usage: "Switch the locally-managed Execution Client and / or Beacon Node to a different client, optionally keeping the old ones running as a temporary fallback until the new ones are synced",
+				Flags: []cli.Flag{
+					switchClientEcFlag,
+					switchClientBnFlag,
+					switchClientKeepOldFlag,
+					switchClientWaitFlag,
+					switchClientFinishFlag,
+					switchClientIgnoreCpuFlag,
+					utils.YesFlag,
+				},
+				Action: func(c *cli.Context) error {
+					// Validate args
+					if err := utils.ValidateArgCount(c, 0); err != nil {
+						return err
+					}
+
+					// Run command
+					return switchClient(c)
+				},
+			},
+
 			{
 				Name:    "resync-ec",
 				Aliases: []string{"resync-eth1"},
 				Usage:   fmt.Sprintf("%sDeletes the main Execution client's chain data and resyncs it from scratch. Only use this as a last resort!%s", terminal.ColorRed, terminal.ColorReset),
+				Flags: []cli.Flag{
+					utils.YesFlag,
+					utils.ConfirmFlag,
+					utils.ValidatorsActiveFlag,
+				},
 				Action: func(c *cli.Context) error {
 					// Validate args
 					if err := utils.ValidateArgCount(c, 0); err != nil {
@@ -367,6 +847,13 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Name:    "resync-bn",
 				Aliases: []string{"resync-eth2"},
 				Usage:   fmt.Sprintf("%sDeletes the Beacon Node's chain data and resyncs it from scratch. Only use this as a last resort!%s", terminal.ColorRed, terminal.ColorReset),
+				Flags: []cli.Flag{
+					utils.YesFlag,
+					utils.ConfirmFlag,
+					utils.ValidatorsActiveFlag,
+					resyncBnCompareWithFlag,
+					resyncBnVerifyOnlyFlag,
+				},
 				Action: func(c *cli.Context) error {
 					// Validate args
 					if err := utils.ValidateArgCount(c, 0); err != nil {
@@ -384,6 +871,11 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 				Usage:   fmt.Sprintf("%sDeletes all of the Hyperdrive Docker containers and volumes, including your Execution Client and Beacon Node chain data and your Prometheus database (if metrics are enabled). Also removes your entire `.hyperdrive` configuration folder, including your wallet, password, and validator keys. Only use this if you are cleaning up Hyperdrive and want to start over!%s", terminal.ColorRed, terminal.ColorReset),
 				Flags: []cli.Flag{
 					utils.YesFlag,
+					utils.ConfirmFlag,
+					utils.ValidatorsActiveFlag,
+					terminateKeepChainDataFlag,
+					terminateKeepKeysFlag,
+					terminateContainersOnlyFlag,
 				},
 				Action: func(c *cli.Context) error {
 					// Validate args
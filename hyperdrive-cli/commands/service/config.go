@@ -68,7 +68,7 @@ func configureService(c *cli.Context) error {
 
 	// Run the TUI
 	app := tview.NewApplication()
-	md := cliconfig.NewMainDisplay(app, oldCfg, cfg, isNew, isUpdate)
+	md := cliconfig.NewMainDisplay(app, hd, oldCfg, cfg, isNew, isUpdate)
 	err = app.Run()
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ type mainDisplay struct {
 	content             *tview.Box
 	mainGrid            *tview.Grid
 	wizard              *wizard
+	hd                  *client.HyperdriveClient
 	settingsHome        *settingsHome
 	isNew               bool
 	isUpdate            bool
@@ -31,7 +32,7 @@ type mainDisplay struct {
 }
 
 // Creates a new MainDisplay instance.
-func NewMainDisplay(app *tview.Application, previousConfig *client.GlobalConfig, config *client.GlobalConfig, isNew bool, isUpdate bool) *mainDisplay {
+func NewMainDisplay(app *tview.Application, hd *client.HyperdriveClient, previousConfig *client.GlobalConfig, config *client.GlobalConfig, isNew bool, isUpdate bool) *mainDisplay {
 	// Create a copy of the original config for comparison purposes
 	if previousConfig == nil {
 		previousConfig = config.CreateCopy()
@@ -73,6 +74,7 @@ func NewMainDisplay(app *tview.Application, previousConfig *client.GlobalConfig,
 		navHeader:      navHeader,
 		pages:          pages,
 		app:            app,
+		hd:             hd,
 		content:        grid.Box,
 		mainGrid:       grid,
 		isNew:          isNew,
@@ -9,6 +9,7 @@ import (
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/rivo/tview"
 	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/utils/sys"
 )
 
 // Constants
@@ -70,6 +71,16 @@ func NewReviewPage(md *mainDisplay, oldConfig *client.GlobalConfig, newConfig *c
 				containersToRestart = append(containersToRestart, container)
 			}
 		}
+
+		// Warn about modern images that this CPU can't run
+		unsupportedTags := newConfig.GetUnsupportedImageTags()
+		if len(unsupportedTags) > 0 {
+			builder.WriteString(fmt.Sprintf("\n\n[orange]WARNING: Your CPU is missing the following features required by modern images: %s\n", strings.Join(sys.GetMissingModernCpuFeatures(), ", ")))
+			builder.WriteString("The following images will automatically be switched to their portable versions when you save:")
+			for _, tag := range unsupportedTags {
+				builder.WriteString(fmt.Sprintf("\n\t%s: %s => %s", tag.Section, tview.Escape(tag.OldTag), tview.Escape(tag.NewTag)))
+			}
+		}
 	}
 
 	changeBox.SetText(builder.String())
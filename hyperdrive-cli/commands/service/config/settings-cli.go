@@ -0,0 +1,92 @@
+package config
+
+import (
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rivo/tview"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+// The page wrapper for the CLI config
+type CliConfigPage struct {
+	home         *settingsHome
+	page         *page
+	layout       *standardLayout
+	masterConfig *client.GlobalConfig
+	cliItems     []*parameterizedFormItem
+	backupItems  []*parameterizedFormItem
+	enableS3Box  *parameterizedFormItem
+	s3Items      []*parameterizedFormItem
+	notifyItems  []*parameterizedFormItem
+}
+
+// Creates a new page for the CLI settings
+func NewCliConfigPage(home *settingsHome) *CliConfigPage {
+	configPage := &CliConfigPage{
+		home:         home,
+		masterConfig: home.md.Config,
+	}
+	configPage.createContent()
+
+	configPage.page = newPage(
+		home.homePage,
+		"settings-cli",
+		"CLI",
+		"Configure how the Hyperdrive CLI runs its containers, such as with rootless Docker or Podman, where scheduled backups are sent, and where event notifications are sent.",
+		configPage.layout.grid,
+	)
+
+	return configPage
+}
+
+// Get the underlying page
+func (configPage *CliConfigPage) getPage() *page {
+	return configPage.page
+}
+
+// Creates the content for the CLI settings page
+func (configPage *CliConfigPage) createContent() {
+	// Create the layout
+	configPage.layout = newStandardLayout()
+	configPage.layout.createForm(&configPage.masterConfig.Hyperdrive.Network, "CLI Settings")
+	configPage.layout.setupEscapeReturnHomeHandler(configPage.home.md, configPage.home.homePage)
+
+	// Set up the form items
+	backupCfg := configPage.masterConfig.Cli.Backup
+	configPage.cliItems = createParameterizedFormItems(configPage.masterConfig.Cli.GetParameters(), configPage.layout.descriptionBox)
+	configPage.backupItems = createParameterizedFormItems([]config.IParameter{&backupCfg.Directory, &backupCfg.KeepDaily, &backupCfg.KeepWeekly, &backupCfg.IncludePassword}, configPage.layout.descriptionBox)
+	configPage.enableS3Box = createParameterizedCheckbox(&backupCfg.EnableS3)
+	configPage.s3Items = createParameterizedFormItems([]config.IParameter{&backupCfg.S3Endpoint, &backupCfg.S3Region, &backupCfg.S3Bucket, &backupCfg.S3Prefix, &backupCfg.S3AccessKey, &backupCfg.S3SecretKey}, configPage.layout.descriptionBox)
+	configPage.notifyItems = createParameterizedFormItems(configPage.masterConfig.Cli.Notifications.GetParameters(), configPage.layout.descriptionBox)
+
+	// Map the parameters to the form items in the layout
+	configPage.layout.mapParameterizedFormItems(configPage.cliItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.backupItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.enableS3Box)
+	configPage.layout.mapParameterizedFormItems(configPage.s3Items...)
+	configPage.layout.mapParameterizedFormItems(configPage.notifyItems...)
+
+	// Set up the setting callbacks
+	configPage.enableS3Box.item.(*tview.Checkbox).SetChangedFunc(func(checked bool) {
+		if backupCfg.EnableS3.Value == checked {
+			return
+		}
+		backupCfg.EnableS3.Value = checked
+		configPage.handleLayoutChanged()
+	})
+
+	// Do the initial draw
+	configPage.handleLayoutChanged()
+}
+
+// Handle a bulk redraw request
+func (configPage *CliConfigPage) handleLayoutChanged() {
+	configPage.layout.form.Clear(true)
+	configPage.layout.addFormItems(configPage.cliItems)
+	configPage.layout.addFormItems(configPage.backupItems)
+	configPage.layout.form.AddFormItem(configPage.enableS3Box.item)
+	if configPage.masterConfig.Cli.Backup.EnableS3.Value {
+		configPage.layout.addFormItems(configPage.s3Items)
+	}
+	configPage.layout.addFormItems(configPage.notifyItems)
+	configPage.layout.refresh()
+}
@@ -16,10 +16,13 @@ type settingsHome struct {
 	wizardButton     *tview.Button
 	hyperdrivePage   *HyperdriveConfigPage
 	loggingPage      *LoggingConfigPage
+	cliPage          *CliConfigPage
 	ecPage           *ExecutionConfigPage
 	fallbackPage     *FallbackConfigPage
+	mevBoostPage     *MevBoostConfigPage
 	bnPage           *BeaconConfigPage
 	metricsPage      *MetricsConfigPage
+	resourcesPage    *ResourcesConfigPage
 	modulesPage      *ModulesPage
 	categoryList     *tview.List
 	settingsSubpages []settingsPage
@@ -40,18 +43,24 @@ func newSettingsHome(md *mainDisplay) *settingsHome {
 	// Create the settings subpages
 	home.hyperdrivePage = NewHyperdriveConfigPage(home)
 	home.loggingPage = NewLoggingConfigPage(home)
+	home.cliPage = NewCliConfigPage(home)
 	home.ecPage = NewExecutionConfigPage(home)
 	home.bnPage = NewBeaconConfigPage(home)
 	home.fallbackPage = NewFallbackConfigPage(home)
+	home.mevBoostPage = NewMevBoostConfigPage(home)
 	home.metricsPage = NewMetricsConfigPage(home)
+	home.resourcesPage = NewResourcesConfigPage(home)
 	home.modulesPage = NewModulesPage(home)
 	settingsSubpages := []settingsPage{
 		home.hyperdrivePage,
 		home.loggingPage,
+		home.cliPage,
 		home.ecPage,
 		home.bnPage,
 		home.fallbackPage,
+		home.mevBoostPage,
 		home.metricsPage,
+		home.resourcesPage,
 		home.modulesPage,
 	}
 	home.settingsSubpages = settingsSubpages
@@ -217,6 +226,10 @@ func (home *settingsHome) refresh() {
 		home.loggingPage.layout.refresh()
 	}
 
+	if home.cliPage != nil {
+		home.cliPage.layout.refresh()
+	}
+
 	if home.ecPage != nil {
 		home.ecPage.layout.refresh()
 	}
@@ -233,7 +246,15 @@ func (home *settingsHome) refresh() {
 		home.fallbackPage.layout.refresh()
 	}
 
+	if home.mevBoostPage != nil {
+		home.mevBoostPage.layout.refresh()
+	}
+
 	if home.metricsPage != nil {
 		home.metricsPage.layout.refresh()
 	}
+
+	if home.resourcesPage != nil {
+		home.resourcesPage.layout.refresh()
+	}
 }
@@ -20,6 +20,8 @@ type MetricsConfigPage struct {
 	grafanaItems               []*parameterizedFormItem
 	prometheusItems            []*parameterizedFormItem
 	exporterItems              []*parameterizedFormItem
+	enableAlertingBox          *parameterizedFormItem
+	alertingItems              []*parameterizedFormItem
 	enableBitflyNodeMetricsBox *parameterizedFormItem
 	bitflyNodeMetricsItems     []*parameterizedFormItem
 }
@@ -64,6 +66,9 @@ func (configPage *MetricsConfigPage) createContent() {
 	configPage.grafanaItems = createParameterizedFormItems(configPage.masterConfig.Hyperdrive.Metrics.Grafana.GetParameters(), configPage.layout.descriptionBox)
 	configPage.prometheusItems = createParameterizedFormItems(configPage.masterConfig.Hyperdrive.Metrics.Prometheus.GetParameters(), configPage.layout.descriptionBox)
 	configPage.exporterItems = createParameterizedFormItems(configPage.masterConfig.Hyperdrive.Metrics.Exporter.GetParameters(), configPage.layout.descriptionBox)
+	alertingCfg := configPage.masterConfig.Cli.Alerting
+	configPage.enableAlertingBox = createParameterizedCheckbox(&alertingCfg.EnableAlerting)
+	configPage.alertingItems = createParameterizedFormItems([]config.IParameter{&alertingCfg.ContainerTag, &alertingCfg.Port, &alertingCfg.MinimumPeers, &alertingCfg.MaxDiskUsage, &alertingCfg.MinimumWalletBalance, &alertingCfg.KeyUploadCost, &alertingCfg.WebhookUrl, &alertingCfg.SmtpServer, &alertingCfg.SmtpFrom, &alertingCfg.SmtpTo, &alertingCfg.SmtpUsername, &alertingCfg.SmtpPassword, &alertingCfg.SmtpRequireTls}, configPage.layout.descriptionBox)
 	configPage.enableBitflyNodeMetricsBox = createParameterizedCheckbox(&configPage.masterConfig.Hyperdrive.Metrics.EnableBitflyNodeMetrics)
 	configPage.bitflyNodeMetricsItems = createParameterizedFormItems(configPage.masterConfig.Hyperdrive.Metrics.BitflyNodeMetrics.GetParameters(), configPage.layout.descriptionBox)
 
@@ -72,6 +77,8 @@ func (configPage *MetricsConfigPage) createContent() {
 	configPage.layout.mapParameterizedFormItems(configPage.grafanaItems...)
 	configPage.layout.mapParameterizedFormItems(configPage.prometheusItems...)
 	configPage.layout.mapParameterizedFormItems(configPage.exporterItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.enableAlertingBox)
+	configPage.layout.mapParameterizedFormItems(configPage.alertingItems...)
 	configPage.layout.mapParameterizedFormItems(configPage.enableBitflyNodeMetricsBox)
 	configPage.layout.mapParameterizedFormItems(configPage.bitflyNodeMetricsItems...)
 
@@ -83,6 +90,13 @@ func (configPage *MetricsConfigPage) createContent() {
 		configPage.masterConfig.Hyperdrive.Metrics.EnableMetrics.Value = checked
 		configPage.handleLayoutChanged()
 	})
+	configPage.enableAlertingBox.item.(*tview.Checkbox).SetChangedFunc(func(checked bool) {
+		if alertingCfg.EnableAlerting.Value == checked {
+			return
+		}
+		alertingCfg.EnableAlerting.Value = checked
+		configPage.handleLayoutChanged()
+	})
 	configPage.enableBitflyNodeMetricsBox.item.(*tview.Checkbox).SetChangedFunc(func(checked bool) {
 		if configPage.masterConfig.Hyperdrive.Metrics.EnableBitflyNodeMetrics.Value == checked {
 			return
@@ -108,6 +122,10 @@ func (configPage *MetricsConfigPage) handleLayoutChanged() {
 		configPage.layout.addFormItems(configPage.grafanaItems)
 		configPage.layout.addFormItems(configPage.prometheusItems)
 		configPage.layout.addFormItems(configPage.exporterItems)
+		configPage.layout.form.AddFormItem(configPage.enableAlertingBox.item)
+		if configPage.masterConfig.Cli.Alerting.EnableAlerting.Value {
+			configPage.layout.addFormItems(configPage.alertingItems)
+		}
 	}
 
 	if configPage.masterConfig.Hyperdrive.IsLocalMode() {
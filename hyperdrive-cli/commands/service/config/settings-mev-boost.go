@@ -0,0 +1,121 @@
+package config
+
+import (
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rivo/tview"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+// The page wrapper for the MEV-Boost config
+type MevBoostConfigPage struct {
+	home          *settingsHome
+	page          *page
+	layout        *standardLayout
+	masterConfig  *client.GlobalConfig
+	enableBox     *parameterizedFormItem
+	modeDropdown  *parameterizedFormItem
+	localItems    []*parameterizedFormItem
+	relayItems    []*parameterizedFormItem
+	customItems   []*parameterizedFormItem
+	externalItems []*parameterizedFormItem
+}
+
+// Creates a new page for the MEV-Boost settings
+func NewMevBoostConfigPage(home *settingsHome) *MevBoostConfigPage {
+	configPage := &MevBoostConfigPage{
+		home:         home,
+		masterConfig: home.md.Config,
+	}
+	configPage.createContent()
+
+	configPage.page = newPage(
+		home.homePage,
+		"settings-mev-boost",
+		"MEV-Boost",
+		"Select this to configure MEV-Boost, which lets your Beacon Node get blocks from MEV relays when one of your validators proposes, and choose which relays it uses.",
+		configPage.layout.grid,
+	)
+
+	return configPage
+}
+
+// Get the underlying page
+func (configPage *MevBoostConfigPage) getPage() *page {
+	return configPage.page
+}
+
+// Creates the content for the MEV-Boost settings page
+func (configPage *MevBoostConfigPage) createContent() {
+	// Create the layout
+	configPage.layout = newStandardLayout()
+	configPage.layout.createForm(&configPage.masterConfig.Hyperdrive.Network, "MEV-Boost Settings")
+	configPage.layout.setupEscapeReturnHomeHandler(configPage.home.md, configPage.home.homePage)
+
+	// Set up the form items
+	mevBoostCfg := configPage.masterConfig.Cli.MevBoost
+	configPage.enableBox = createParameterizedCheckbox(&mevBoostCfg.Enable)
+	configPage.modeDropdown = createParameterizedDropDown(&mevBoostCfg.Mode, configPage.layout.descriptionBox)
+	configPage.localItems = createParameterizedFormItems([]config.IParameter{&mevBoostCfg.ContainerTag, &mevBoostCfg.Port, &mevBoostCfg.MetricsPort}, configPage.layout.descriptionBox)
+	configPage.relayItems = createParameterizedFormItems([]config.IParameter{&mevBoostCfg.FlashbotsRelay, &mevBoostCfg.BloxrouteMaxProfitRelay, &mevBoostCfg.BloxrouteRegulatedRelay, &mevBoostCfg.UltrasoundRelay, &mevBoostCfg.AestusRelay, &mevBoostCfg.TitanRelay, &mevBoostCfg.AgnosticRelay}, configPage.layout.descriptionBox)
+	configPage.customItems = createParameterizedFormItems([]config.IParameter{&mevBoostCfg.CustomRelays, &mevBoostCfg.AdditionalFlags}, configPage.layout.descriptionBox)
+	configPage.externalItems = createParameterizedFormItems([]config.IParameter{&mevBoostCfg.ExternalUrl}, configPage.layout.descriptionBox)
+
+	// Map the parameters to the form items in the layout
+	configPage.layout.mapParameterizedFormItems(configPage.enableBox, configPage.modeDropdown)
+	configPage.layout.mapParameterizedFormItems(configPage.localItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.relayItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.customItems...)
+	configPage.layout.mapParameterizedFormItems(configPage.externalItems...)
+
+	// Set up the setting callbacks
+	configPage.enableBox.item.(*tview.Checkbox).SetChangedFunc(func(checked bool) {
+		if mevBoostCfg.Enable.Value == checked {
+			return
+		}
+		mevBoostCfg.Enable.Value = checked
+		configPage.handleLayoutChanged()
+	})
+	configPage.modeDropdown.item.(*DropDown).SetSelectedFunc(func(text string, index int) {
+		if mevBoostCfg.Mode.Value == mevBoostCfg.Mode.Options[index].Value {
+			return
+		}
+		mevBoostCfg.Mode.Value = mevBoostCfg.Mode.Options[index].Value
+		configPage.handleLayoutChanged()
+	})
+
+	// Do the initial draw
+	configPage.handleLayoutChanged()
+}
+
+// Handle all of the form changes when the Enable box or the mode has changed
+func (configPage *MevBoostConfigPage) handleLayoutChanged() {
+	configPage.layout.form.Clear(true)
+	configPage.layout.form.AddFormItem(configPage.enableBox.item)
+
+	mevBoostCfg := configPage.masterConfig.Cli.MevBoost
+	if !mevBoostCfg.Enable.Value {
+		configPage.layout.refresh()
+		return
+	}
+
+	configPage.layout.form.AddFormItem(configPage.modeDropdown.item)
+	if mevBoostCfg.Mode.Value == config.ClientMode_External {
+		configPage.layout.addFormItems(configPage.externalItems)
+		configPage.layout.refresh()
+		return
+	}
+
+	// Only show the relays that support the selected network
+	configPage.layout.addFormItems(configPage.localItems)
+	availableRelays := mevBoostCfg.GetAvailableRelays(configPage.masterConfig.Hyperdrive.Network.Value)
+	for _, item := range configPage.relayItems {
+		for _, relay := range availableRelays {
+			if item.parameter == config.IParameter(relay) {
+				configPage.layout.form.AddFormItem(item.item)
+				break
+			}
+		}
+	}
+	configPage.layout.addFormItems(configPage.customItems)
+	configPage.layout.refresh()
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	swconfig "github.com/nodeset-org/hyperdrive-stakewise/shared/config"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rocket-pool/node-manager-core/config"
+)
+
+// The page wrapper for the container resources config
+type ResourcesConfigPage struct {
+	home           *settingsHome
+	page           *page
+	layout         *standardLayout
+	masterConfig   *client.GlobalConfig
+	containerItems map[config.ContainerID][]*parameterizedFormItem
+}
+
+// Creates a new page for the container resource settings
+func NewResourcesConfigPage(home *settingsHome) *ResourcesConfigPage {
+	configPage := &ResourcesConfigPage{
+		home:         home,
+		masterConfig: home.md.Config,
+	}
+	configPage.createContent()
+
+	configPage.page = newPage(
+		home.homePage,
+		"settings-resources",
+		"Resources / Logging",
+		"Select this to limit how much CPU and memory each of Hyperdrive's containers can use, protect the important ones from the kernel's out-of-memory killer, and control how large their logs can grow.",
+		configPage.layout.grid,
+	)
+
+	return configPage
+}
+
+// Get the underlying page
+func (configPage *ResourcesConfigPage) getPage() *page {
+	return configPage.page
+}
+
+// Creates the content for the container resource settings page
+func (configPage *ResourcesConfigPage) createContent() {
+	// Create the layout
+	configPage.layout = newStandardLayout()
+	configPage.layout.createForm(&configPage.masterConfig.Hyperdrive.Network, "Resources / Logging Settings")
+	configPage.layout.setupEscapeReturnHomeHandler(configPage.home.md, configPage.home.homePage)
+
+	// Set up the form items
+	configPage.containerItems = map[config.ContainerID][]*parameterizedFormItem{}
+	for _, container := range configPage.masterConfig.Cli.Resources.GetContainers() {
+		items := createParameterizedFormItems(container.GetParameters(), configPage.layout.descriptionBox)
+		configPage.layout.mapParameterizedFormItems(items...)
+		configPage.containerItems[container.GetContainer()] = items
+	}
+
+	// Do the initial draw
+	configPage.handleLayoutChanged()
+}
+
+// Handle a bulk redraw request
+func (configPage *ResourcesConfigPage) handleLayoutChanged() {
+	configPage.layout.form.Clear(true)
+
+	// Only show the containers that will be deployed with the current settings
+	for _, container := range configPage.masterConfig.Cli.Resources.GetContainers() {
+		if configPage.isContainerDeployed(container.GetContainer()) {
+			configPage.layout.addFormItems(configPage.containerItems[container.GetContainer()])
+		}
+	}
+	configPage.layout.refresh()
+}
+
+// Check if a container will be deployed with the current settings
+func (configPage *ResourcesConfigPage) isContainerDeployed(container config.ContainerID) bool {
+	cfg := configPage.masterConfig
+	switch container {
+	case config.ContainerID_ExecutionClient, config.ContainerID_BeaconNode:
+		return cfg.Hyperdrive.IsLocalMode()
+	case config.ContainerID_MevBoost:
+		return cfg.Cli.MevBoost.IsLocal()
+	case config.ContainerID_Prometheus, config.ContainerID_Grafana, config.ContainerID_Exporter:
+		return cfg.Hyperdrive.Metrics.EnableMetrics.Value
+	case client.ContainerID_Alertmanager:
+		return cfg.Hyperdrive.Metrics.EnableMetrics.Value && cfg.Cli.Alerting.EnableAlerting.Value
+	case swconfig.ContainerID_StakewiseDaemon, swconfig.ContainerID_StakewiseOperator, swconfig.ContainerID_StakewiseValidator:
+		return cfg.Stakewise.Enabled.Value
+	default:
+		return true
+	}
+}
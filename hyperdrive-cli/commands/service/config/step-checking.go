@@ -0,0 +1,63 @@
+package config
+
+// A step that's shown while a slow check runs in the background, so the UI stays responsive
+type checkingWizardStep struct {
+	wiz  *wizard
+	step *choiceWizardStep
+
+	// Incremented every time a check starts or is cancelled, so the results of a stale check are ignored
+	runID int
+}
+
+func createCheckingStep(wiz *wizard, currentStep int, totalSteps int, helperText string, title string, pageID string, back func()) *checkingWizardStep {
+	checking := &checkingWizardStep{
+		wiz: wiz,
+	}
+
+	show := func(modal *choiceModalLayout) {
+		wiz.md.setPage(modal.page)
+		modal.focus(0)
+	}
+
+	cancel := func() {
+		checking.runID++
+		back()
+	}
+
+	done := func(buttonIndex int, buttonLabel string) {
+		cancel()
+	}
+
+	checking.step = newChoiceStep(
+		wiz,
+		currentStep,
+		totalSteps,
+		helperText,
+		[]string{"Cancel"},
+		nil,
+		76,
+		title,
+		DirectionalModalHorizontal,
+		show,
+		done,
+		cancel,
+		pageID,
+	)
+	return checking
+}
+
+// Show the step and run the check in the background, then call done on the UI thread once it's finished unless the user cancelled it
+func (checking *checkingWizardStep) run(check func(), done func()) {
+	checking.runID++
+	runID := checking.runID
+	checking.step.show()
+
+	go func() {
+		check()
+		checking.wiz.md.app.QueueUpdateDraw(func() {
+			if checking.runID == runID {
+				done()
+			}
+		})
+	}()
+}
@@ -1,16 +1,30 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rivo/tview"
+)
+
+const checkpointSyncCompareLabel string = "Compare With"
+
 func createCheckpointSyncStep(wiz *wizard, currentStep int, totalSteps int) *textBoxWizardStep {
 
 	// Create the labels and args
 	checkpointSyncLabel := wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Name
 
-	helperText := "Your client supports Checkpoint Sync. This powerful feature allows it to copy the most recent state from a separate Beacon Node that you trust, so you don't have to wait for it to sync from scratch - you can start using it instantly!\n\nTake a look at our documentation for an example of how to use it at https://docs.nodeset.io.\n\nIf you would like to use Checkpoint Sync, please provide the provider URL here. If you don't want to use it, leave it blank."
+	helperText := "Your client supports Checkpoint Sync. This powerful feature allows it to copy the most recent state from a separate Beacon Node that you trust, so you don't have to wait for it to sync from scratch - you can start using it instantly!\n\nTake a look at our documentation for an example of how to use it at https://docs.nodeset.io.\n\nIf you would like to use Checkpoint Sync, please provide the provider URL here. If you don't want to use it, leave it blank.\n\nTo make sure the provider can be trusted, you can also enter the URLs of other providers to compare its checkpoint against, separated by commas."
 
 	show := func(modal *textBoxModalLayout) {
 		wiz.md.setPage(modal.page)
 		modal.focus()
 		for label, box := range modal.textboxes {
+			if label == checkpointSyncCompareLabel {
+				box.SetText(wiz.checkpointSyncCompareUrls)
+				continue
+			}
 			for _, param := range wiz.md.Config.Hyperdrive.LocalBeaconClient.GetParameters() {
 				if param.GetCommon().Name == label {
 					box.SetText(param.String())
@@ -20,8 +34,30 @@ func createCheckpointSyncStep(wiz *wizard, currentStep int, totalSteps int) *tex
 	}
 
 	done := func(text map[string]string) {
-		wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Value = text[checkpointSyncLabel]
-		wiz.useFallbackModal.show()
+		checkpointSyncUrl := text[checkpointSyncLabel]
+		wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Value = checkpointSyncUrl
+		wiz.checkpointSyncCompareUrls = text[checkpointSyncCompareLabel]
+		if checkpointSyncUrl == "" {
+			wiz.useFallbackModal.show()
+			return
+		}
+
+		// Make sure the provider is reachable, serves the selected network, and agrees with the others
+		otherUrls := []string{}
+		for _, otherUrl := range strings.Split(wiz.checkpointSyncCompareUrls, ",") {
+			otherUrl = strings.TrimSpace(otherUrl)
+			if otherUrl != "" {
+				otherUrls = append(otherUrls, otherUrl)
+			}
+		}
+		resources := wiz.md.Config.Hyperdrive.GetNetworkResources()
+		var report *client.CheckpointSyncReport
+		wiz.checkpointSyncCheckingModal.run(func() {
+			report = client.VerifyCheckpointSyncProvider(checkpointSyncUrl, otherUrls, resources)
+		}, func() {
+			wiz.checkpointSyncResultModal = createCheckpointSyncResultStep(wiz, currentStep, totalSteps, report)
+			wiz.checkpointSyncResultModal.show()
+		})
 	}
 
 	back := func() {
@@ -35,9 +71,9 @@ func createCheckpointSyncStep(wiz *wizard, currentStep int, totalSteps int) *tex
 		helperText,
 		76,
 		"Beacon Node > Checkpoint Sync",
-		[]string{checkpointSyncLabel},
-		[]int{wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.MaxLength},
-		[]string{wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Regex},
+		[]string{checkpointSyncLabel, checkpointSyncCompareLabel},
+		[]int{wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.MaxLength, 0},
+		[]string{wiz.md.Config.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Regex, ""},
 		show,
 		done,
 		back,
@@ -45,3 +81,102 @@ func createCheckpointSyncStep(wiz *wizard, currentStep int, totalSteps int) *tex
 	)
 
 }
+
+func createCheckpointSyncCheckingStep(wiz *wizard, currentStep int, totalSteps int) *checkingWizardStep {
+	helperText := "Checking your Checkpoint Sync provider and comparing its finalized checkpoint against the other providers you entered...\n\nThis can take a few seconds."
+
+	back := func() {
+		wiz.checkpointSyncProviderModal.show()
+	}
+
+	return createCheckingStep(
+		wiz,
+		currentStep,
+		totalSteps,
+		helperText,
+		"Beacon Node > Checkpoint Sync",
+		"step-checkpoint-sync-checking",
+		back,
+	)
+}
+
+func createCheckpointSyncResultStep(wiz *wizard, currentStep int, totalSteps int, report *client.CheckpointSyncReport) *choiceWizardStep {
+	// Show the checkpoint from each provider so they can be compared
+	lines := []string{}
+	primary := report.Primary
+	if primary.Checkpoint != nil {
+		lines = append(lines,
+			fmt.Sprintf("[green]CONFIGURED[white]  %s", tview.Escape(primary.Url)),
+			fmt.Sprintf("Finalized slot: %d", primary.Checkpoint.Slot),
+			fmt.Sprintf("Block root: %s", primary.Checkpoint.BlockRoot),
+			fmt.Sprintf("State root: %s", primary.Checkpoint.StateRoot),
+		)
+	} else {
+		lines = append(lines, fmt.Sprintf("[red]FAILED[white]  %s", tview.Escape(primary.Url)))
+	}
+	for _, other := range report.Others {
+		switch {
+		case other.Error != nil || !other.NetworkMatches:
+			lines = append(lines, "", fmt.Sprintf("[orange]NOT CHECKED[white]  %s", tview.Escape(other.Url)))
+		case other.CheckpointMatches:
+			lines = append(lines, "", fmt.Sprintf("[green]AGREES[white]  %s", tview.Escape(other.Url)))
+		default:
+			lines = append(lines, "",
+				fmt.Sprintf("[red]DISAGREES[white]  %s", tview.Escape(other.Url)),
+				fmt.Sprintf("Block root: %s", other.Checkpoint.BlockRoot),
+				fmt.Sprintf("State root: %s", other.Checkpoint.StateRoot),
+			)
+		}
+	}
+
+	// Add the warnings and a summary
+	for _, warning := range report.GetWarnings() {
+		lines = append(lines, "", fmt.Sprintf("[orange]%s[white]", tview.Escape(warning)))
+	}
+	var summary string
+	var buttons []string
+	focus := 0
+	if report.IsVerified() {
+		summary = "[green]Your Checkpoint Sync provider was verified successfully.[white]"
+		buttons = []string{"Choose Again", "Next"}
+		focus = 1
+	} else {
+		summary = "[orange]WARNING: Hyperdrive couldn't verify this Checkpoint Sync provider.\n\nSyncing from a provider on the wrong network, or one that disagrees with the others, can prevent your Beacon Node from syncing properly or put it on the wrong chain.[white]"
+		buttons = []string{"Choose Again", "Keep Provider"}
+	}
+
+	helperText := fmt.Sprintf("%s\n\n%s\n\nYou can check the provider again at any time with `hyperdrive service resync-bn --verify-only`.", strings.Join(lines, "\n"), summary)
+
+	show := func(modal *choiceModalLayout) {
+		wiz.md.setPage(modal.page)
+		modal.focus(focus)
+	}
+
+	done := func(buttonIndex int, buttonLabel string) {
+		if buttonIndex == 0 {
+			wiz.checkpointSyncProviderModal.show()
+		} else {
+			wiz.useFallbackModal.show()
+		}
+	}
+
+	back := func() {
+		wiz.checkpointSyncProviderModal.show()
+	}
+
+	return newChoiceStep(
+		wiz,
+		currentStep,
+		totalSteps,
+		helperText,
+		buttons,
+		[]string{},
+		76,
+		"Beacon Node > Checkpoint Sync",
+		DirectionalModalHorizontal,
+		show,
+		done,
+		back,
+		"step-checkpoint-sync-result",
+	)
+}
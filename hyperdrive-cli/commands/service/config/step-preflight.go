@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rivo/tview"
+)
+
+func createPreflightStep(wiz *wizard, currentStep int, totalSteps int, checks []*client.PreflightCheck) *choiceWizardStep {
+	// Build the results table
+	lines := []string{}
+	for _, check := range checks {
+		color := "green"
+		switch check.Status {
+		case client.PreflightStatus_Warn:
+			color = "orange"
+		case client.PreflightStatus_Fail:
+			color = "red"
+		}
+		lines = append(lines, fmt.Sprintf("[%s]%s[white]  %s: %s", color, strings.ToUpper(string(check.Status)), check.Name, tview.Escape(check.Details)))
+	}
+
+	var summary string
+	switch client.GetPreflightStatus(checks) {
+	case client.PreflightStatus_Pass:
+		summary = "This machine meets all of the requirements for running a node."
+	case client.PreflightStatus_Warn:
+		summary = "[orange]This machine can run a node, but please review the warnings above.[white]"
+	case client.PreflightStatus_Fail:
+		summary = "[red]This machine doesn't meet the requirements for running a node. You can continue configuring Hyperdrive, but please fix the failed checks before starting it.[white]"
+	}
+
+	helperText := fmt.Sprintf("Before we begin, we checked this machine for everything a node needs:\n\n%s\n\n%s\n\nYou can run these checks again at any time with `hyperdrive service preflight`.", strings.Join(lines, "\n"), summary)
+
+	show := func(modal *choiceModalLayout) {
+		wiz.md.setPage(modal.page)
+		modal.focus(1)
+	}
+
+	done := func(buttonIndex int, buttonLabel string) {
+		if buttonIndex == 1 {
+			wiz.networkModal.show()
+		} else {
+			wiz.md.app.Stop()
+		}
+	}
+
+	back := func() {
+		wiz.welcomeModal.show()
+	}
+
+	return newChoiceStep(
+		wiz,
+		currentStep,
+		totalSteps,
+		helperText,
+		[]string{"Quit", "Next"},
+		nil,
+		90,
+		"Preflight Check",
+		DirectionalModalHorizontal,
+		show,
+		done,
+		back,
+		"step-preflight",
+	)
+}
+
+func createPreflightCheckingStep(wiz *wizard, currentStep int, totalSteps int) *checkingWizardStep {
+	helperText := "Checking this machine for everything a node needs, such as free disk space, Docker, and open ports...\n\nThis can take a few seconds."
+
+	back := func() {
+		wiz.welcomeModal.show()
+	}
+
+	return createCheckingStep(
+		wiz,
+		currentStep,
+		totalSteps,
+		helperText,
+		"Preflight Check",
+		"step-preflight-checking",
+		back,
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 )
 
 func createWelcomeStep(wiz *wizard, currentStep int, totalSteps int) *choiceWizardStep {
@@ -23,7 +24,15 @@ func createWelcomeStep(wiz *wizard, currentStep int, totalSteps int) *choiceWiza
 
 	done := func(buttonIndex int, buttonLabel string) {
 		if buttonIndex == 1 {
-			wiz.networkModal.show()
+			// Check the host before setting anything up; this is run on demand in the background since it takes a few seconds
+			cfg := wiz.md.Config.CreateCopy()
+			var checks []*client.PreflightCheck
+			wiz.preflightCheckingModal.run(func() {
+				checks = wiz.md.hd.RunPreflightChecks(cfg, false)
+			}, func() {
+				wiz.preflightModal = createPreflightStep(wiz, currentStep, totalSteps, checks)
+				wiz.preflightModal.show()
+			})
 		} else {
 			wiz.md.app.Stop()
 		}
@@ -5,7 +5,9 @@ type wizard struct {
 	md *mainDisplay
 
 	// Step 1 - Welcome
-	welcomeModal *choiceWizardStep
+	welcomeModal           *choiceWizardStep
+	preflightCheckingModal *checkingWizardStep
+	preflightModal         *choiceWizardStep
 
 	// Step 2 - Network
 	networkModal *choiceWizardStep
@@ -25,6 +27,9 @@ type wizard struct {
 	localBnPrysmWarning         *choiceWizardStep
 	localBnTekuWarning          *choiceWizardStep
 	checkpointSyncProviderModal *textBoxWizardStep
+	checkpointSyncCheckingModal *checkingWizardStep
+	checkpointSyncResultModal   *choiceWizardStep
+	checkpointSyncCompareUrls   string
 	externalBnSelectModal       *choiceWizardStep
 	externalBnSettingsModal     *textBoxWizardStep
 	externalPrysmSettingsModal  *textBoxWizardStep
@@ -54,6 +59,7 @@ func newWizard(md *mainDisplay) *wizard {
 
 	// Step 1 - Welcome
 	wiz.welcomeModal = createWelcomeStep(wiz, 1, totalSteps)
+	wiz.preflightCheckingModal = createPreflightCheckingStep(wiz, 1, totalSteps)
 
 	// Step 2 - Network
 	wiz.networkModal = createNetworkStep(wiz, 2, totalSteps)
@@ -71,6 +77,7 @@ func newWizard(md *mainDisplay) *wizard {
 	wiz.localBnPrysmWarning = createPrysmWarningStep(wiz, 5, totalSteps)
 	wiz.localBnTekuWarning = createTekuWarningStep(wiz, 5, totalSteps)
 	wiz.checkpointSyncProviderModal = createCheckpointSyncStep(wiz, 5, totalSteps)
+	wiz.checkpointSyncCheckingModal = createCheckpointSyncCheckingStep(wiz, 5, totalSteps)
 	wiz.externalBnSelectModal = createExternalBnSelectStep(wiz, 5, totalSteps)
 	wiz.externalBnSettingsModal = createExternalBnSettingsStep(wiz, 5, totalSteps)
 	wiz.externalPrysmSettingsModal = createExternalPrysmSettingsStep(wiz, 5, totalSteps)
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// How long to wait for the daemon and VCs to switch to the fallback clients during a drill
+	fallbackDrillTimeout time.Duration = 2 * time.Minute
+
+	// How long to give the VCs to start using the fallback clients after the daemon has switched
+	fallbackDrillVcGracePeriod time.Duration = 30 * time.Second
+
+	fallbackDrillPollInterval time.Duration = 5 * time.Second
+	fallbackDrillLogTail      string        = "100"
+)
+
+var (
+	fallbackDrillFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "drill",
+		Usage: "Run a failover drill: temporarily stop the primary Execution Client and Beacon Node, confirm the daemon and Validator Clients switch to the fallbacks, then restart the primary clients",
+	}
+)
+
+// Probe the fallback clients, and optionally run a failover drill
+func testFallbackClients(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+
+	// Get the config
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("Settings file not found. Please run `hyperdrive service config` to set up Hyperdrive.")
+	}
+	if !cfg.Hyperdrive.Fallback.UseFallbackClients.Value {
+		fmt.Println("You do not have fallback clients enabled. You can set them up with `hyperdrive service config`.")
+		return nil
+	}
+
+	// Probe the fallbacks
+	resources := cfg.Hyperdrive.GetNetworkResources()
+	fmt.Println("Probing your fallback Execution Client...")
+	ecResult := client.ProbeFallbackExecutionClient(cfg.Hyperdrive.Fallback.EcHttpUrl.Value, resources)
+	ecOk := printFallbackProbeResult(ecResult)
+	fmt.Println()
+
+	fmt.Println("Probing your fallback Beacon Node...")
+	bnResult := client.ProbeFallbackBeaconNode(cfg.Hyperdrive.Fallback.BnHttpUrl.Value, cfg.Hyperdrive.Fallback.PrysmRpcUrl.Value, cfg.Hyperdrive.GetSelectedBeaconNode(), resources)
+	bnOk := printFallbackProbeResult(bnResult)
+	fmt.Println()
+
+	if ecOk && bnOk {
+		fmt.Printf("%sYour fallback clients are ready to use.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	} else {
+		fmt.Printf("%sYour fallback clients have problems that must be fixed before they can take over for your primary clients.%s\n", terminal.ColorRed, terminal.ColorReset)
+	}
+
+	if !c.Bool(fallbackDrillFlag.Name) {
+		return nil
+	}
+
+	// Run the drill
+	fmt.Println()
+	if !cfg.Hyperdrive.IsLocalMode() {
+		fmt.Println("Your primary clients are externally managed, so Hyperdrive can't stop them for a failover drill.")
+		return nil
+	}
+	if !(ecOk && bnOk) {
+		fmt.Println("Skipping the failover drill because your fallback clients aren't ready.")
+		return nil
+	}
+	return runFallbackDrill(c, hd, cfg)
+}
+
+// Print the result of a fallback probe, returning true if the client is usable
+func printFallbackProbeResult(result *client.FallbackProbeResult) bool {
+	fmt.Printf("URL:           %s\n", result.Url)
+	if !result.IsReachable {
+		fmt.Printf("Reachable:     %sNO%s (%s)\n", terminal.ColorRed, terminal.ColorReset, result.Error.Error())
+		return false
+	}
+	fmt.Printf("Reachable:     %sYES%s (latency %s)\n", terminal.ColorGreen, terminal.ColorReset, result.Latency.Round(time.Millisecond))
+	if result.Error != nil {
+		fmt.Printf("%sError:         %s%s\n", terminal.ColorRed, result.Error.Error(), terminal.ColorReset)
+		return false
+	}
+	printFallbackCheck("Network:", result.NetworkMatches, result.NetworkDetails)
+	printFallbackCheck("Synced:", result.IsSynced, result.SyncDetails)
+	if result.CompatibilityDetails != "" {
+		printFallbackCheck("Compatible:", result.IsCompatible, result.CompatibilityDetails)
+	}
+	return result.NetworkMatches && result.IsSynced && result.IsCompatible
+}
+
+// Print a single fallback check in green if it passed or red if it failed
+func printFallbackCheck(label string, passed bool, details string) {
+	color := terminal.ColorGreen
+	if !passed {
+		color = terminal.ColorRed
+	}
+	fmt.Printf("%-15s%s%s%s\n", label, color, details, terminal.ColorReset)
+}
+
+// Stop the primary clients, make sure the daemon and VCs switch to the fallbacks, and restore the primaries
+func runFallbackDrill(c *cli.Context, hd *client.HyperdriveClient, cfg *client.GlobalConfig) error {
+	ecContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+	bnContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
+
+	fmt.Printf("%sThe failover drill will stop your primary Execution Client and Beacon Node for up to %s.\nYour validators will rely entirely on your fallback clients during this time.%s\n", terminal.ColorYellow, fallbackDrillTimeout+fallbackDrillVcGracePeriod, terminal.ColorReset)
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Are you sure you want to run the failover drill?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Stop the primaries, making sure they always get restarted, even if the drill is interrupted
+	drillStart := time.Now()
+	var restoreOnce sync.Once
+	restore := func() {
+		restoreOnce.Do(func() {
+			restorePrimaryClients(hd, ecContainerName, bnContainerName)
+		})
+	}
+	defer restore()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		fmt.Printf("\n%sThe failover drill was interrupted, restoring your primary clients...%s\n", terminal.ColorYellow, terminal.ColorReset)
+		restore()
+		os.Exit(1)
+	}()
+	for _, containerName := range []string{ecContainerName, bnContainerName} {
+		fmt.Printf("Stopping %s...\n", containerName)
+		err := hd.StopContainer(containerName)
+		if err != nil {
+			return fmt.Errorf("error stopping %s: %w", containerName, err)
+		}
+	}
+
+	// Wait for the daemon to switch over
+	fmt.Println("Waiting for the daemon to switch to the fallback clients...")
+	daemonSwitched := false
+	for time.Since(drillStart) < fallbackDrillTimeout {
+		time.Sleep(fallbackDrillPollInterval)
+		response, err := hd.Api.Service.ClientStatus()
+		if err != nil {
+			continue
+		}
+		// The fallbacks being healthy isn't enough, the daemon has to see the primaries as down and actually be using the fallbacks
+		if client.IsUsingFallbackClient(&response.Data.EcManagerStatus) && client.IsUsingFallbackClient(&response.Data.BcManagerStatus) {
+			daemonSwitched = true
+			break
+		}
+	}
+	if daemonSwitched {
+		fmt.Printf("%sThe daemon is using the fallback clients (switched in %s).%s\n", terminal.ColorGreen, time.Since(drillStart).Round(time.Second), terminal.ColorReset)
+	} else {
+		fmt.Printf("%sThe daemon didn't switch to the fallback clients within %s.%s\n", terminal.ColorRed, fallbackDrillTimeout, terminal.ColorReset)
+	}
+
+	// Give the VCs some time to switch over, then check on them
+	fmt.Println("Checking the Validator Clients...")
+	time.Sleep(fallbackDrillVcGracePeriod)
+	vcsSwitched := checkVcsUsingFallback(hd, cfg)
+
+	fmt.Println()
+	if !daemonSwitched || !vcsSwitched {
+		return fmt.Errorf("the failover drill found problems; please review the output above")
+	}
+	fmt.Printf("%sThe failover drill succeeded.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	return nil
+}
+
+// Make sure each VC is still running and its recent logs mention the fallback Beacon Node
+func checkVcsUsingFallback(hd *client.HyperdriveClient, cfg *client.GlobalConfig) bool {
+	vcs, err := hd.GetValidatorContainers(cfg.Hyperdrive.ProjectName.Value + "_")
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't get the Validator Client containers: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		return false
+	}
+	if len(vcs) == 0 {
+		fmt.Println("You don't have any Validator Clients, so there's nothing to check.")
+		return true
+	}
+
+	fallbackHost := cfg.Hyperdrive.Fallback.BnHttpUrl.Value
+	if parsedUrl, err := url.Parse(fallbackHost); err == nil && parsedUrl.Host != "" {
+		fallbackHost = parsedUrl.Host
+	}
+	if cfg.Hyperdrive.GetSelectedBeaconNode() == config.BeaconNode_Prysm {
+		fallbackHost = cfg.Hyperdrive.Fallback.PrysmRpcUrl.Value
+	}
+
+	allSwitched := true
+	for _, vc := range vcs {
+		status, err := hd.GetDockerStatus(vc)
+		if err != nil || status != "running" {
+			fmt.Printf("%s%s is not running (%s).%s\n", terminal.ColorRed, vc, status, terminal.ColorReset)
+			allSwitched = false
+			continue
+		}
+
+		logs, err := hd.GetContainerLogs(vc, fallbackDrillLogTail)
+		if err == nil && strings.Contains(logs, fallbackHost) {
+			fmt.Printf("%s%s is running and its logs show it connecting to the fallback Beacon Node.%s\n", terminal.ColorGreen, vc, terminal.ColorReset)
+			continue
+		}
+		fmt.Printf("%s%s is running, but its recent logs don't mention the fallback Beacon Node. Please review them with `hyperdrive service logs` to confirm it switched over.%s\n", terminal.ColorYellow, vc, terminal.ColorReset)
+		allSwitched = false
+	}
+	return allSwitched
+}
+
+// Restart the primary clients after a drill
+func restorePrimaryClients(hd *client.HyperdriveClient, ecContainerName string, bnContainerName string) {
+	for _, containerName := range []string{ecContainerName, bnContainerName} {
+		fmt.Printf("Restarting %s...\n", containerName)
+		err := hd.StartContainer(containerName)
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't restart %s: %s\nPlease run `hyperdrive service start` to restore it.%s\n", terminal.ColorRed, containerName, err.Error(), terminal.ColorReset)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+// Roll Hyperdrive back to the installation from before the last upgrade
+func rollbackInstallation(c *cli.Context, hd *client.HyperdriveClient) error {
+	snapshot, err := hd.LoadInstallationSnapshot()
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("there's no previous installation to roll back to; one is saved automatically each time Hyperdrive is upgraded")
+	}
+
+	// The daemon image is tied to the CLI version, so the CLI has to be rolled back first
+	cliVersion := fmt.Sprintf("v%s", shared.HyperdriveVersion)
+	if snapshot.Version != cliVersion {
+		return fmt.Errorf("the previous installation is Hyperdrive %s, but this is the %s CLI.\nPlease switch to the %s CLI first (with `hyperdrive update --rollback` if you upgraded with `hyperdrive update`, or by downloading it manually), then run this command again", snapshot.Version, cliVersion, snapshot.Version)
+	}
+
+	// Warn about clients that may have already migrated their data
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	fmt.Printf("Hyperdrive will be rolled back to %s, saved on %s.\n\n", snapshot.Version, snapshot.Created.Format("2006-01-02 15:04:05"))
+	if !isNew {
+		printRollbackTagChanges(cfg.GetContainerTags(), snapshot.ContainerTags)
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Your current settings will be replaced with the ones from before the upgrade, and the service will be restarted. Are you sure you want to continue?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Restore and restart
+	err = hd.RestoreInstallationSnapshot()
+	if err != nil {
+		return fmt.Errorf("error restoring the previous installation: %w", err)
+	}
+	fmt.Println("Restored the previous installation. Restarting the service...")
+	err = hd.StartService(getComposeFiles(c))
+	if err != nil {
+		return fmt.Errorf("error restarting the service: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("%sHyperdrive was rolled back to %s.%s\n", terminal.ColorGreen, snapshot.Version, terminal.ColorReset)
+	return nil
+}
+
+// Print the container tags that will change during a rollback, with a warning about client database migrations
+func printRollbackTagChanges(currentTags map[string]string, previousTags map[string]string) {
+	sections := []string{}
+	for section, previousTag := range previousTags {
+		currentTag, exists := currentTags[section]
+		if exists && currentTag != previousTag {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		return
+	}
+	sort.Strings(sections)
+
+	fmt.Println("The following images will be downgraded:")
+	for _, section := range sections {
+		fmt.Printf("\t%s: %s => %s\n", section, currentTags[section], previousTags[section])
+	}
+	fmt.Println()
+	fmt.Printf("%sWARNING: Execution Clients and Beacon Nodes sometimes upgrade their database schemas when a new version starts for the first time.\n", terminal.ColorYellow)
+	fmt.Println("If any of the clients above has done so, its previous version may refuse to start or may corrupt its data. Check the client's release notes before continuing.")
+	fmt.Printf("If a client fails to start after the rollback, you may need to resync it with `hyperdrive service resync-ec` or `hyperdrive service resync-bn`.%s\n\n", terminal.ColorReset)
+}
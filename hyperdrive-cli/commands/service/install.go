@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/nodeset-org/hyperdrive-daemon/shared"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
@@ -35,41 +36,132 @@ var (
 	installLocalFlag *cli.BoolFlag = &cli.BoolFlag{
 		Name:    "local-script",
 		Aliases: []string{"l"},
-		Usage:   fmt.Sprintf("Use a local installer script instead of pulling it down from the source repository. The script and the installer package must be in your current working directory.%sMake sure you absolutely trust the script before using this flag.%s", terminal.ColorRed, terminal.ColorReset),
+		Usage:   fmt.Sprintf("Use a local installer script instead of pulling it down from the source repository. The script, the installer package, and the signed checksum manifest must be in your current working directory.%sMake sure you absolutely trust the script before using this flag.%s", terminal.ColorRed, terminal.ColorReset),
+	}
+	installInsecureFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "insecure",
+		Usage: fmt.Sprintf("Skip verifying the local installer script and package against the signed checksum manifest, and the bundle against its signing key. Only valid with --%s or --bundle.%sThis runs an unverified script as root; only use it for development.%s", installLocalFlag.Name, terminal.ColorRed, terminal.ColorReset),
+	}
+	installRollbackFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "rollback",
+		Usage: "Restore the Hyperdrive installation and settings from before the last upgrade, and restart the service with the previous images",
+	}
+	installBundleFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "bundle",
+		Aliases: []string{"b"},
+		Usage:   "Install from an offline bundle created with `hyperdrive service bundle create` instead of downloading the installer and images",
 	}
 )
 
 // Install the Hyperdrive service
 func installService(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	if c.Bool(installRollbackFlag.Name) {
+		return rollbackInstallation(c, hd)
+	}
+
+	// Only local installs can skip verification
+	bundlePath := c.String(installBundleFlag.Name)
+	if c.Bool(installInsecureFlag.Name) && !c.Bool(installLocalFlag.Name) && bundlePath == "" {
+		return fmt.Errorf("--%s can only be used with --%s or --%s", installInsecureFlag.Name, installLocalFlag.Name, installBundleFlag.Name)
+	}
+	if c.Bool(installLocalFlag.Name) && bundlePath != "" {
+		return fmt.Errorf("--%s and --%s can't be used together", installLocalFlag.Name, installBundleFlag.Name)
+	}
+
+	// Unpack the bundle, which determines the version to install
+	version := c.String(installVersionFlag.Name)
+	var bundleDir string
+	var bundleManifest *client.BundleManifest
+	if bundlePath != "" {
+		var err error
+		bundleDir, bundleManifest, err = extractBundle(c, bundlePath, c.Bool(installInsecureFlag.Name))
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(bundleDir)
+		version = bundleManifest.Version
+		fmt.Printf("The bundle contains Hyperdrive %s and %d images.\n\n", version, len(bundleManifest.Images))
+	} else if !c.Bool(installLocalFlag.Name) {
+		// Pin "latest" to a release tag so the installer and the release notes refer to the same version
+		var err error
+		version, err = client.ResolveReleaseVersion(version)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check the host before installing
+	fmt.Println("Checking if this machine meets the requirements for running a node...")
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	noDeps := c.Bool(installNoDepsFlag.Name)
+	if bundlePath != "" && !noDeps {
+		// The dependencies can't be downloaded without network access
+		fmt.Printf("%sNOTE: You're installing from an offline bundle, so the installer won't install Operating System dependencies. Make sure Docker is already installed.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		noDeps = true
+	}
+	if cfg.Cli.IsRootless() && !noDeps {
+		// The installer's dependencies include rootful Docker, which would defeat the point
+		fmt.Printf("%sNOTE: You've selected a rootless container runtime, so the installer won't install Docker or add you to the docker group.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		noDeps = true
+	}
+	printPreflightChecks(hd.RunPreflightChecks(cfg, !noDeps))
+	fmt.Println()
+
+	// Get the current version so its release notes can be shown after the upgrade
+	previousVersion, err := hd.GetInstalledVersion()
+	if err != nil {
+		return err
+	}
+
 	// Prompt for confirmation
 	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf(
 		"Hyperdrive will be installed --Version: %s\n\n%sIf you're upgrading, your existing configuration will be backed up and preserved.\nAll of your previous settings will be migrated automatically.%s\nAre you sure you want to continue?",
-		c.String(installVersionFlag.Name), terminal.ColorGreen, terminal.ColorReset,
+		version, terminal.ColorGreen, terminal.ColorReset,
 	))) {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
-	// Get Hyperdrive client
-	hd := client.NewHyperdriveClientFromCtx(c)
-
 	// Install service
-	err := hd.InstallService(
-		c.Bool(installVersionFlag.Name),
-		c.Bool(installNoDepsFlag.Name),
-		c.String(installVersionFlag.Name),
-		c.String(installPathFlag.Name),
-		c.Bool(installLocalFlag.Name),
-	)
-	if err != nil {
-		return err
+	if bundlePath != "" {
+		err = hd.InstallServiceFromBundle(
+			c.Bool(installVersionFlag.Name),
+			c.String(installPathFlag.Name),
+			bundleDir,
+			bundleManifest,
+			c.Bool(installInsecureFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		err = hd.LoadBundleImages(bundleDir)
+		if err != nil {
+			return fmt.Errorf("Hyperdrive was installed but the bundle's images couldn't be loaded: %w\nIf your user can't access Docker yet, log out and back in, then run `hyperdrive service bundle load %s`", err, bundlePath)
+		}
+	} else {
+		err = hd.InstallService(
+			c.Bool(installVersionFlag.Name),
+			noDeps,
+			version,
+			c.String(installPathFlag.Name),
+			c.Bool(installLocalFlag.Name),
+			c.Bool(installInsecureFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Print success message & return
 	fmt.Println("")
 	fmt.Println("The Hyperdrive service was successfully installed!")
 
-	printPatchNotes()
+	printPatchNotes(previousVersion, version)
 
 	// Reload the config after installation
 	_, isNew, err := hd.LoadConfig()
@@ -91,14 +183,20 @@ func installService(c *cli.Context) error {
 
 }
 
-// Print the latest patch notes for this release
-// TODO: get this from an external source and don't hardcode it into the CLI
-func printPatchNotes() {
+// Print the release notes for every version since the previously installed one
+func printPatchNotes(previousVersion string, newVersion string) {
 	fmt.Println()
 	fmt.Println(shared.Logo)
-	fmt.Printf("%s=== Hyperdrive v%s ===%s\n\n", terminal.ColorGreen, shared.HyperdriveVersion, terminal.ColorReset)
-	fmt.Printf("Changes you should be aware of before starting:\n\n")
+	fmt.Printf("%s=== Hyperdrive %s ===%s\n\n", terminal.ColorGreen, newVersion, terminal.ColorReset)
 
-	fmt.Printf("%s=== Stakewise Module ===%s\n", terminal.ColorGreen, terminal.ColorReset)
-	fmt.Println("Stakewise functions now initialize the Stakewise wallet if it's missing instead of erroring out.")
+	entries, err := getReleaseNotes(previousVersion, newVersion)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't load the release notes: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("Changes you should be aware of before starting:\n\n")
+	printReleaseNotes(entries)
 }
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	scrapeTargetCheckInterval time.Duration = 5 * time.Second
+	scrapeTargetCheckTimeout  time.Duration = 90 * time.Second
+)
+
+var (
+	scrapePathFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "path",
+		Aliases: []string{"p"},
+		Usage:   "The HTTP path the target serves its metrics on. Defaults to /metrics",
+	}
+)
+
+// Add a custom Prometheus scrape job
+func addScrapeJob(c *cli.Context, name string, target string) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before adding scrape jobs")
+	}
+
+	// Validate the job
+	err = cfg.ValidateScrapeJobName(name)
+	if err != nil {
+		return err
+	}
+	err = client.ValidateScrapeTarget(target)
+	if err != nil {
+		return err
+	}
+	path := c.String(scrapePathFlag.Name)
+	if path != "" {
+		err = client.ValidateMetricsPath(path)
+		if err != nil {
+			return err
+		}
+	}
+	existing, err := findScrapeJob(hd, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if !existing.Managed {
+			return fmt.Errorf("there's already a hand-written scrape job named [%s] in %s; please edit that file instead", name, existing.File)
+		}
+		if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("There's already a scrape job named [%s] for %s. Would you like to replace it?", name, strings.Join(existing.Targets, ", ")))) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	// Make sure Prometheus can reach the target
+	host, _, _ := net.SplitHostPort(target)
+	if client.IsContainerHost(host) {
+		shared, prometheusNetworks, err := hd.GetPrometheusNetworksForHost(host)
+		if err != nil {
+			return fmt.Errorf("error checking if Prometheus can reach [%s]: %w", host, err)
+		}
+		if len(shared) == 0 {
+			return fmt.Errorf("Prometheus can't reach [%s]: there's no container with that name or alias on any of its networks (%s). Connect the container to one of them with `docker network connect`, then try again", host, strings.Join(prometheusNetworks, ", "))
+		}
+		fmt.Printf("Prometheus can reach %s through %s.\n", host, strings.Join(shared, ", "))
+	} else {
+		fmt.Printf("%s isn't a container name, so Hyperdrive can't check that Prometheus can reach it.\n", host)
+	}
+
+	// Save it
+	job := &client.ScrapeJob{
+		Name:        name,
+		Targets:     []string{target},
+		MetricsPath: path,
+	}
+	file, err := hd.SaveScrapeJob(job)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Saved the scrape job to %s.\n", file)
+
+	if !cfg.Hyperdrive.Metrics.EnableMetrics.Value {
+		fmt.Printf("%sWARNING: Metrics are disabled, so nothing will scrape this job until you enable them in the Monitoring / Metrics section of `hyperdrive service config`.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		return nil
+	}
+	verifyScrapeJob(hd, cfg, name, target)
+	return nil
+}
+
+// List the custom Prometheus scrape jobs, along with their health if Prometheus is running
+func listScrapeJobs(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	jobs, err := hd.LoadScrapeJobs()
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Println("There are no custom scrape jobs. Add one with `hyperdrive service metrics scrape add`.")
+		return nil
+	}
+
+	// Get the target health if Prometheus is running
+	var targets []client.PrometheusTarget
+	if isPrometheusRunning(hd, cfg) {
+		targets, err = hd.GetPrometheusTargets()
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't get the target health from Prometheus: %s%s\n\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
+	}
+
+	for _, job := range jobs {
+		path := job.MetricsPath
+		if path == "" {
+			path = "/metrics"
+		}
+		origin := "added by Hyperdrive"
+		if !job.Managed {
+			origin = "hand-written"
+		}
+		fmt.Printf("%s%s%s (%s, %s)\n", terminal.ColorGreen, job.Name, terminal.ColorReset, job.File, origin)
+		for _, address := range job.Targets {
+			fmt.Printf("\t%s%s", address, path)
+			if targets != nil {
+				fmt.Printf(" - %s", formatTargetHealth(client.FindPrometheusTarget(targets, job.Name, address)))
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+// Remove a custom Prometheus scrape job
+func removeScrapeJob(c *cli.Context, name string) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	job, err := findScrapeJob(hd, name)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("there's no scrape job named [%s]; see `hyperdrive service metrics scrape list` for the ones that exist", name)
+	}
+	if !job.Managed {
+		return fmt.Errorf("the scrape job [%s] was written by hand; please remove it from %s yourself", name, job.File)
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("Prometheus will stop scraping %s. Existing metrics from it will be kept until they expire. Are you sure you want to continue?", strings.Join(job.Targets, ", ")))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	err = hd.DeleteScrapeJob(name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s.\n", job.File)
+	return nil
+}
+
+// Find the scrape job with the given name, or nil if there isn't one
+func findScrapeJob(hd *client.HyperdriveClient, name string) (*client.ScrapeJob, error) {
+	jobs, err := hd.LoadScrapeJobs()
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if job.Name == name {
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+// Wait for Prometheus to pick up a new scrape job and report whether it could scrape the target
+func verifyScrapeJob(hd *client.HyperdriveClient, cfg *client.GlobalConfig, name string, address string) {
+	if !isPrometheusRunning(hd, cfg) {
+		fmt.Println("Prometheus isn't running, so it will start scraping the job the next time you run `hyperdrive service start`.")
+		return
+	}
+
+	// Prometheus reloads the folder on its own, then needs to scrape the target once before its health is known
+	fmt.Println("Waiting for Prometheus to scrape the target...")
+	deadline := time.Now().Add(scrapeTargetCheckTimeout)
+	var target *client.PrometheusTarget
+	for {
+		targets, err := hd.GetPrometheusTargets()
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't check the job with Prometheus: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+			return
+		}
+		target = client.FindPrometheusTarget(targets, name, address)
+		if (target != nil && target.Health != "unknown") || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(scrapeTargetCheckInterval)
+	}
+
+	switch {
+	case target == nil:
+		fmt.Printf("%sWARNING: Prometheus hasn't picked up the job yet. Check on it later with `hyperdrive service metrics scrape list`.%s\n", terminal.ColorYellow, terminal.ColorReset)
+	case target.Health == "up":
+		fmt.Printf("%sPrometheus is scraping %s successfully.%s\n", terminal.ColorGreen, target.ScrapeUrl, terminal.ColorReset)
+	case target.Health == "down":
+		fmt.Printf("%sWARNING: Prometheus couldn't scrape %s: %s\nThe job has been kept; check that the target is serving metrics, then check on it again with `hyperdrive service metrics scrape list`.%s\n", terminal.ColorYellow, target.ScrapeUrl, target.LastError, terminal.ColorReset)
+	default:
+		fmt.Printf("%sWARNING: Prometheus hasn't scraped %s yet. Check on it later with `hyperdrive service metrics scrape list`.%s\n", terminal.ColorYellow, target.ScrapeUrl, terminal.ColorReset)
+	}
+}
+
+// Check if the Prometheus container is running
+func isPrometheusRunning(hd *client.HyperdriveClient, cfg *client.GlobalConfig) bool {
+	status, err := hd.GetDockerStatus(fmt.Sprintf("%s_%s", cfg.Hyperdrive.ProjectName.Value, cfg.Hyperdrive.PrometheusContainerName()))
+	return err == nil && status == "running"
+}
+
+// Describe a target's health as reported by Prometheus
+func formatTargetHealth(target *client.PrometheusTarget) string {
+	if target == nil {
+		return "not discovered by Prometheus yet"
+	}
+	switch target.Health {
+	case "up":
+		return fmt.Sprintf("%sup%s", terminal.ColorGreen, terminal.ColorReset)
+	case "down":
+		return fmt.Sprintf("%sdown%s (%s)", terminal.ColorRed, terminal.ColorReset, target.LastError)
+	default:
+		return "waiting for the first scrape"
+	}
+}
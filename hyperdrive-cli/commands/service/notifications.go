@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	notificationsUrlFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "url",
+		Aliases: []string{"u"},
+		Usage:   "Send the sample to this URL instead of the webhooks in your settings, such as a local HTTP server you're testing with",
+	}
+	notificationsFormatFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "format",
+		Aliases: []string{"f"},
+		Usage:   fmt.Sprintf("The format to send the sample to --url in (%s)", formatNotificationFormats()),
+		Value:   string(client.NotificationFormat_Generic),
+	}
+)
+
+// Send a sample notification to the configured webhooks, or to a single URL
+func testNotifications(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	notification := &client.Notification{
+		Event:   client.NotificationEvent_Test,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Test notification",
+		Message: "This is a test of Hyperdrive's event notifications. If you can read this, they're set up correctly.",
+		Project: cfg.Hyperdrive.ProjectName.Value,
+		Time:    time.Now(),
+		Details: map[string]string{
+			"Network": string(cfg.Hyperdrive.Network.Value),
+		},
+	}
+
+	// Send it to the provided URL
+	targetUrl := c.String(notificationsUrlFlag.Name)
+	if targetUrl != "" {
+		format := client.NotificationFormat(c.String(notificationsFormatFlag.Name))
+		if !slices.Contains(client.NotificationFormats, format) {
+			return fmt.Errorf("unknown format [%s]; it must be one of %s", format, formatNotificationFormats())
+		}
+		parsedUrl, err := url.Parse(targetUrl)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return fmt.Errorf("[%s] isn't an http or https URL", targetUrl)
+		}
+		target := client.NewWebhookTarget(format, targetUrl, "")
+		err = target.Send(notification)
+		if err != nil {
+			return fmt.Errorf("error sending the sample to %s: %w", target.GetName(), err)
+		}
+		fmt.Printf("%sSent a sample notification to %s.%s\n", terminal.ColorGreen, target.GetName(), terminal.ColorReset)
+		return nil
+	}
+
+	// Send it to the configured webhooks
+	targets := cfg.Cli.Notifications.GetTargets()
+	if len(targets) == 0 {
+		fmt.Printf("You don't have any notification webhooks set up. Add one in the CLI section of `hyperdrive service config`, or send the sample to a specific URL with --%s.\n", notificationsUrlFlag.Name)
+		return nil
+	}
+	failed := false
+	for _, target := range targets {
+		err := target.Send(notification)
+		if err != nil {
+			fmt.Printf("%s: %sfailed%s (%s)\n", target.GetName(), terminal.ColorRed, terminal.ColorReset, err.Error())
+			failed = true
+			continue
+		}
+		fmt.Printf("%s: %ssent%s\n", target.GetName(), terminal.ColorGreen, terminal.ColorReset)
+	}
+	if failed {
+		return fmt.Errorf("the sample couldn't be sent to all of your webhooks")
+	}
+	return nil
+}
+
+// Let the webhooks know that the service was started
+func notifyServiceStarted(hd *client.HyperdriveClient) {
+	utils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_ServiceStarted,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Hyperdrive started",
+		Message: "The Hyperdrive service was started.",
+	})
+}
+
+// Let the webhooks know that the service was stopped
+func notifyServiceStopped(hd *client.HyperdriveClient) {
+	utils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_ServiceStopped,
+		Level:   client.NotificationLevel_Warning,
+		Title:   "Hyperdrive stopped",
+		Message: "The Hyperdrive service was stopped. Your validators won't attest until it's started again.",
+	})
+}
+
+// Let the webhooks know that the start is being held back by the slashing prevention delay
+func notifySlashingDelayStarted(hd *client.HyperdriveClient, remainingTime time.Duration) {
+	utils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_SlashingDelayStarted,
+		Level:   client.NotificationLevel_Warning,
+		Title:   "Slashing prevention delay started",
+		Message: "The Validator Client was changed, so Hyperdrive is waiting before starting it to avoid attesting twice. Your validators won't attest until the delay is over.",
+		Details: map[string]string{
+			"Remaining":  remainingTime.Round(time.Second).String(),
+			"Safe start": time.Now().Add(remainingTime).Format(time.RFC3339),
+		},
+	})
+}
+
+// Let the webhooks know that the slashing prevention delay is over
+func notifySlashingDelayEnded(hd *client.HyperdriveClient) {
+	utils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_SlashingDelayEnded,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Slashing prevention delay ended",
+		Message: "The Validator Client can now be started safely.",
+	})
+}
+
+// Let the webhooks know about containers that just started restarting in a loop
+func notifyRestartLoops(hd *client.HyperdriveClient, containers []client.ContainerHealth) {
+	newLoops, err := hd.FindNewRestartLoops(containers)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't check the containers for restart loops: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		return
+	}
+	for _, container := range newLoops {
+		utils.Notify(hd, &client.Notification{
+			Event:   client.NotificationEvent_ContainerRestartLoop,
+			Level:   client.NotificationLevel_Critical,
+			Title:   fmt.Sprintf("%s is restarting in a loop", container.Name),
+			Message: fmt.Sprintf("The %s container keeps crashing and being restarted. Check its logs with `hyperdrive service logs %s`.", container.Name, container.Name),
+			Details: map[string]string{
+				"Container": container.Name,
+				"Restarts":  fmt.Sprint(container.Restarts),
+			},
+		})
+	}
+}
+
+// Get the supported notification formats as a comma-separated list
+func formatNotificationFormats() string {
+	formats := make([]string, len(client.NotificationFormats))
+	for i, format := range client.NotificationFormats {
+		formats[i] = string(format)
+	}
+	return strings.Join(formats, ", ")
+}
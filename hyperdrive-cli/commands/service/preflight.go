@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	preflightJsonFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "json",
+		Aliases: []string{"j"},
+		Usage:   "Print the results as JSON",
+	}
+)
+
+// The JSON output of the preflight command
+type preflightReport struct {
+	Status client.PreflightStatus   `json:"status"`
+	Checks []*client.PreflightCheck `json:"checks"`
+}
+
+// Check that the host meets the requirements for running a node
+func runPreflight(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+
+	// Get the config, using the defaults if it hasn't been created yet
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	checks := hd.RunPreflightChecks(cfg, false)
+	status := client.GetPreflightStatus(checks)
+	if c.Bool(preflightJsonFlag.Name) {
+		bytes, err := json.MarshalIndent(preflightReport{
+			Status: status,
+			Checks: checks,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializing preflight results: %w", err)
+		}
+		fmt.Println(string(bytes))
+	} else {
+		printPreflightChecks(checks)
+	}
+
+	if status == client.PreflightStatus_Fail {
+		return fmt.Errorf("one or more preflight checks failed")
+	}
+	return nil
+}
+
+// Print the preflight check results as a table
+func printPreflightChecks(checks []*client.PreflightCheck) {
+	nameWidth := 0
+	for _, check := range checks {
+		if len(check.Name) > nameWidth {
+			nameWidth = len(check.Name)
+		}
+	}
+
+	for _, check := range checks {
+		color := terminal.ColorGreen
+		switch check.Status {
+		case client.PreflightStatus_Warn:
+			color = terminal.ColorYellow
+		case client.PreflightStatus_Fail:
+			color = terminal.ColorRed
+		}
+		fmt.Printf("%s[%s]%s %-*s  %s\n", color, strings.ToUpper(string(check.Status)), terminal.ColorReset, nameWidth, check.Name, check.Details)
+	}
+	fmt.Println()
+
+	switch client.GetPreflightStatus(checks) {
+	case client.PreflightStatus_Pass:
+		fmt.Printf("%sThis machine meets all of the requirements for running a node.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	case client.PreflightStatus_Warn:
+		fmt.Printf("%sThis machine can run a node, but please review the warnings above.%s\n", terminal.ColorYellow, terminal.ColorReset)
+	case client.PreflightStatus_Fail:
+		fmt.Printf("%sThis machine doesn't meet the requirements for running a node. Please fix the failed checks above.%s\n", terminal.ColorRed, terminal.ColorReset)
+	}
+}
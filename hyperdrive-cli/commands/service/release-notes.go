@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	releaseNotesSinceFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "since",
+		Aliases: []string{"s"},
+		Usage:   "Show the notes for every release after this version, instead of just the current one",
+	}
+)
+
+// Show the release notes of the installed version
+func showReleaseNotes(c *cli.Context) error {
+	currentVersion := fmt.Sprintf("v%s", shared.HyperdriveVersion)
+	entries, err := getReleaseNotes(c.String(releaseNotesSinceFlag.Name), currentVersion)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("There are no release notes for this version range.")
+		return nil
+	}
+	printReleaseNotes(entries)
+	return nil
+}
+
+// Get the notes for the releases after the since version up to the until version.
+// If since is empty, only the notes for the until version are returned.
+func getReleaseNotes(since string, until string) ([]*client.ReleaseNotesEntry, error) {
+	notes, err := client.LoadReleaseNotes()
+	if err != nil {
+		return nil, err
+	}
+	untilVersion, err := client.ParseReleaseVersion(until)
+	if err != nil {
+		return nil, err
+	}
+	var sinceVersion *semver.Version
+	if since != "" {
+		version, err := client.ParseReleaseVersion(since)
+		if err != nil {
+			return nil, err
+		}
+		sinceVersion = &version
+	}
+	return notes.GetReleasesBetween(sinceVersion, untilVersion)
+}
+
+// Print release notes, grouped by release and module, with required actions highlighted
+func printReleaseNotes(entries []*client.ReleaseNotesEntry) {
+	actions := []string{}
+	for _, entry := range entries {
+		fmt.Printf("%s=== %s ===%s\n", terminal.ColorGreen, entry.Version, terminal.ColorReset)
+		module := ""
+		for _, note := range entry.Notes {
+			if note.Module != module {
+				module = note.Module
+				fmt.Printf("%s%s:%s\n", terminal.ColorBlue, module, terminal.ColorReset)
+			}
+
+			switch note.Severity {
+			case client.ReleaseNoteSeverity_Critical:
+				fmt.Printf("  - %s[CRITICAL]%s %s\n", terminal.ColorRed, terminal.ColorReset, note.Description)
+			case client.ReleaseNoteSeverity_Important:
+				fmt.Printf("  - %s[IMPORTANT]%s %s\n", terminal.ColorYellow, terminal.ColorReset, note.Description)
+			default:
+				fmt.Printf("  - %s\n", note.Description)
+			}
+			if note.RequiredAction != "" {
+				fmt.Printf("    %sAction required: %s%s\n", terminal.ColorYellow, note.RequiredAction, terminal.ColorReset)
+				actions = append(actions, fmt.Sprintf("%s (%s): %s", entry.Version, note.Module, note.RequiredAction))
+			}
+		}
+		fmt.Println()
+	}
+
+	// Summarize the required actions so they aren't missed
+	if len(actions) > 0 {
+		fmt.Printf("%s=== Required Actions ===\n", terminal.ColorRed)
+		for _, action := range actions {
+			fmt.Printf("  - %s\n", action)
+		}
+		fmt.Printf("%s\n", terminal.ColorReset)
+	}
+}
@@ -10,8 +10,20 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+var (
+	resyncBnCompareWithFlag *cli.StringSliceFlag = &cli.StringSliceFlag{
+		Name:    "compare-with",
+		Aliases: []string{"c"},
+		Usage:   "The URL of another checkpoint sync provider to compare the configured one's finalized checkpoint against. Can be specified multiple times.",
+	}
+	resyncBnVerifyOnlyFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "verify-only",
+		Usage: "Only verify the configured checkpoint sync provider without deleting or resyncing the Beacon Node",
+	}
+)
+
 // Destroy and resync the Beacon Node from scratch
-func resyncBeaconNode(c *cli.Context) error {
+func resyncBeaconNode(c *cli.Context) (err error) {
 	// Get Hyperdrive client
 	hd := client.NewHyperdriveClientFromCtx(c)
 
@@ -24,8 +36,17 @@ func resyncBeaconNode(c *cli.Context) error {
 		return fmt.Errorf("Settings file not found. Please run `hyperdrive service config` to set up Hyperdrive.")
 	}
 
-	fmt.Println("This will delete the chain data of your Beacon Node and resync it from scratch.")
-	fmt.Printf("%sYou should only do this if your Beacon Node has failed and can no longer start or sync properly.\nThis is meant to be a last resort.%s\n\n", terminal.ColorYellow, terminal.ColorReset)
+	// Just verify the checkpoint sync provider if requested
+	checkpointSyncUrl := cfg.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Value
+	if c.Bool(resyncBnVerifyOnlyFlag.Name) {
+		if checkpointSyncUrl == "" {
+			return fmt.Errorf("you do not have a checkpoint sync provider configured")
+		}
+		if !verifyCheckpointSyncProvider(c, cfg, checkpointSyncUrl) {
+			return fmt.Errorf("checkpoint sync provider [%s] could not be verified", checkpointSyncUrl)
+		}
+		return nil
+	}
 
 	// Check the client mode
 	if cfg.Hyperdrive.ClientMode.Value == config.ClientMode_External {
@@ -33,34 +54,57 @@ func resyncBeaconNode(c *cli.Context) error {
 		return nil
 	}
 
-	// Get the current checkpoint sync URL
-	checkpointSyncUrl := cfg.Hyperdrive.LocalBeaconClient.CheckpointSyncProvider.Value
+	fmt.Println("This will delete the chain data of your Beacon Node and resync it from scratch.")
+	fmt.Printf("%sYou should only do this if your Beacon Node has failed and can no longer start or sync properly.\nThis is meant to be a last resort.%s\n\n", terminal.ColorYellow, terminal.ColorReset)
+
+	// Check the current checkpoint sync URL
 	if checkpointSyncUrl == "" {
 		fmt.Printf("%sYou do not have a checkpoint sync provider configured.\nIf you have active validators, they %swill be considered offline and will lose ETH%s%s until your Beacon Node finishes syncing.\nWe strongly recommend you configure a checkpoint sync provider with `hyperdrive service config` so it syncs instantly before running this.%s\n\n", terminal.ColorRed, terminal.ColorBold, terminal.ColorReset, terminal.ColorRed, terminal.ColorReset)
 	} else {
 		fmt.Printf("You have a checkpoint sync provider configured (%s).\nYour Beacon Node will use it to sync to the head of the Beacon Chain instantly after being rebuilt.\n\n", checkpointSyncUrl)
+
+		// Make sure the provider can be trusted before wiping the BN
+		if !verifyCheckpointSyncProvider(c, cfg, checkpointSyncUrl) {
+			if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("%sYour checkpoint sync provider could not be verified. Syncing from a malicious or misconfigured provider can put your Beacon Node on the wrong chain. Do you want to continue anyway?%s", terminal.ColorRed, terminal.ColorReset))) {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+	}
+
+	// Get the container and volume to delete
+	beaconContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
+	volume, err := hd.GetClientVolumeName(beaconContainerName, clientDataVolumeName)
+	if err != nil {
+		return fmt.Errorf("Error getting Beacon Node volume name: %w", err)
 	}
 
 	// Prompt for confirmation
-	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("%sAre you SURE you want to delete and resync your main Beacon Node from scratch? This cannot be undone!%s", terminal.ColorRed, terminal.ColorReset))) {
+	op := &utils.DestructiveOperation{
+		Command:          "service resync-bn",
+		ConfirmationText: cfg.Hyperdrive.ProjectName.Value,
+		ConfirmationName: "project name",
+		Volumes:          []string{volume},
+	}
+	proceed, err := utils.ConfirmDestructiveOperation(c, hd, op)
+	if err != nil {
+		return err
+	}
+	if !proceed {
 		fmt.Println("Cancelled.")
 		return nil
 	}
+	defer func() {
+		utils.RecordDestructiveOperation(op, err)
+	}()
 
 	// Stop the BN
-	beaconContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
 	fmt.Printf("Stopping %s...\n", beaconContainerName)
 	err = hd.StopContainer(beaconContainerName)
 	if err != nil {
 		fmt.Printf("%sWARNING: Stopping Beacon Node container failed: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
 	}
 
-	// Get the BN volume name
-	volume, err := hd.GetClientVolumeName(beaconContainerName, clientDataVolumeName)
-	if err != nil {
-		return fmt.Errorf("Error getting Beacon Node volume name: %w", err)
-	}
-
 	// Remove the BN
 	fmt.Printf("Deleting %s...\n", beaconContainerName)
 	err = hd.RemoveContainer(beaconContainerName)
@@ -85,3 +129,36 @@ func resyncBeaconNode(c *cli.Context) error {
 	fmt.Printf("\nDone! Your Beacon Node is now resyncing. You can follow its progress with `hyperdrive service logs bn`.\n")
 	return nil
 }
+
+// Verify the checkpoint sync provider against the network config and any other providers, printing the results.
+// Returns true if no problems were found.
+func verifyCheckpointSyncProvider(c *cli.Context, cfg *client.GlobalConfig, checkpointSyncUrl string) bool {
+	otherUrls := c.StringSlice(resyncBnCompareWithFlag.Name)
+	fmt.Printf("Verifying checkpoint sync provider %s", checkpointSyncUrl)
+	if len(otherUrls) > 0 {
+		fmt.Printf(" against %d other provider(s)", len(otherUrls))
+	}
+	fmt.Println("...")
+
+	report := client.VerifyCheckpointSyncProvider(checkpointSyncUrl, otherUrls, cfg.Hyperdrive.GetNetworkResources())
+	if report.Primary.Checkpoint != nil {
+		fmt.Printf("Finalized slot:  %d\n", report.Primary.Checkpoint.Slot)
+		fmt.Printf("Block root:      %s\n", report.Primary.Checkpoint.BlockRoot)
+		fmt.Printf("State root:      %s\n", report.Primary.Checkpoint.StateRoot)
+	}
+	for _, other := range report.Others {
+		if other.Error == nil && other.NetworkMatches && other.CheckpointMatches {
+			fmt.Printf("%s%s agrees with the configured provider.%s\n", terminal.ColorGreen, other.Url, terminal.ColorReset)
+		}
+	}
+
+	warnings := report.GetWarnings()
+	for _, warning := range warnings {
+		fmt.Printf("%sWARNING: %s%s\n", terminal.ColorYellow, warning, terminal.ColorReset)
+	}
+	if len(warnings) == 0 {
+		fmt.Printf("%sThe checkpoint sync provider was verified successfully.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	}
+	fmt.Println()
+	return report.IsVerified()
+}
@@ -11,7 +11,7 @@ import (
 )
 
 // Destroy and resync the Execution client from scratch
-func resyncExecutionClient(c *cli.Context) error {
+func resyncExecutionClient(c *cli.Context) (err error) {
 	// Get Hyperdrive client
 	hd := client.NewHyperdriveClientFromCtx(c)
 
@@ -33,26 +33,39 @@ func resyncExecutionClient(c *cli.Context) error {
 	fmt.Println("This will delete the chain data of your primary Execution client and resync it from scratch.")
 	fmt.Printf("%sYou should only do this if your Execution client has failed and can no longer start or sync properly.\nThis is meant to be a last resort.%s\n", terminal.ColorYellow, terminal.ColorReset)
 
+	// Get the container and volume to delete
+	executionContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+	volume, err := hd.GetClientVolumeName(executionContainerName, clientDataVolumeName)
+	if err != nil {
+		return fmt.Errorf("Error getting Execution client volume name: %w", err)
+	}
+
 	// Prompt for confirmation
-	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("%sAre you SURE you want to delete and resync your main Execution client from scratch? This cannot be undone!%s", terminal.ColorRed, terminal.ColorReset))) {
+	op := &utils.DestructiveOperation{
+		Command:          "service resync-ec",
+		ConfirmationText: cfg.Hyperdrive.ProjectName.Value,
+		ConfirmationName: "project name",
+		Volumes:          []string{volume},
+	}
+	proceed, err := utils.ConfirmDestructiveOperation(c, hd, op)
+	if err != nil {
+		return err
+	}
+	if !proceed {
 		fmt.Println("Cancelled.")
 		return nil
 	}
+	defer func() {
+		utils.RecordDestructiveOperation(op, err)
+	}()
 
 	// Stop Execution
-	executionContainerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
 	fmt.Printf("Stopping %s...\n", executionContainerName)
 	err = hd.StopContainer(executionContainerName)
 	if err != nil {
 		fmt.Printf("%sWARNING: Stopping main Execution client container failed: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
 	}
 
-	// Get Execution volume name
-	volume, err := hd.GetClientVolumeName(executionContainerName, clientDataVolumeName)
-	if err != nil {
-		return fmt.Errorf("Error getting Execution client volume name: %w", err)
-	}
-
 	// Remove the EC
 	fmt.Printf("Deleting %s...\n", executionContainerName)
 	err = hd.RemoveContainer(executionContainerName)
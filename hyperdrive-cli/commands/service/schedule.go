@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+)
+
+const (
+	systemdRuntimeDir string = "/run/systemd/system"
+)
+
+// Get the paths of the CLI binary and config directory for a scheduled job, and the user it should run as
+func getScheduledJobEnvironment(hd *client.HyperdriveClient, cfg *client.GlobalConfig) (string, string, *user.User, error) {
+	cliPath, err := os.Executable()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error getting the path of the Hyperdrive CLI: %w", err)
+	}
+	cliPath, err = filepath.EvalSymlinks(cliPath)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error resolving the path of the Hyperdrive CLI: %w", err)
+	}
+	configPath, err := homedir.Expand(hd.Context.ConfigPath)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error expanding config path: %w", err)
+	}
+
+	var jobUser *user.User
+	if cfg.Cli.IsRootless() {
+		jobUser, err = client.GetSystemdUnitUser()
+		if err != nil {
+			return "", "", nil, err
+		}
+	} else {
+		// Reading the data folder needs root, and nobody will be around to enter a sudo password
+		jobUser, err = user.LookupId("0")
+		if err != nil {
+			return "", "", nil, fmt.Errorf("error looking up the root user: %w", err)
+		}
+	}
+	return cliPath, configPath, jobUser, nil
+}
+
+// Check if a scheduled job should be installed as a systemd timer instead of a cron file
+func useSystemdForJob(forceCron bool) bool {
+	_, err := os.Stat(systemdRuntimeDir)
+	return err == nil && !forceCron
+}
+
+// Print the files that will be installed for a scheduled job
+func printScheduledJob(job *client.ScheduledJob, useSystemd bool) {
+	if useSystemd {
+		service, timer := job.CreateUnits()
+		fmt.Printf("The following units will be installed to %s:\n\n", client.SystemdUnitDir)
+		fmt.Printf("%s%s\n%s%s\n", terminal.ColorBlue, service, timer, terminal.ColorReset)
+	} else {
+		fmt.Printf("The following cron file will be installed to %s:\n\n", job.GetCronPath())
+		fmt.Printf("%s%s%s\n", terminal.ColorBlue, job.CreateCronEntry(), terminal.ColorReset)
+	}
+}
+
+// Install a scheduled job and explain how to check on it
+func installScheduledJob(hd *client.HyperdriveClient, job *client.ScheduledJob, useSystemd bool) error {
+	if useSystemd {
+		serviceName, timerName := job.GetUnitNames()
+		service, timer := job.CreateUnits()
+		err := hd.InstallSystemdTimer(serviceName, service, timerName, timer)
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Printf("%sInstalled and started %s.%s\n", terminal.ColorGreen, timerName, terminal.ColorReset)
+		fmt.Printf("You can check when it runs next with `systemctl list-timers %s`, run it now with `sudo systemctl start %s`, and follow its output with `journalctl -u %s`.\n", timerName, serviceName, serviceName)
+		return nil
+	}
+
+	cronPath := job.GetCronPath()
+	err := hd.InstallCronEntry(cronPath, job.CreateCronEntry())
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("%sInstalled %s.%s\n", terminal.ColorGreen, cronPath, terminal.ColorReset)
+	fmt.Printf("Its output will be written to %s.\n", filepath.Join(job.ConfigPath, job.LogFile))
+	return nil
+}
+
+// Check if a scheduled job is installed, either as a systemd timer or a cron file
+func isScheduledJobInstalled(job *client.ScheduledJob) bool {
+	_, timerName := job.GetUnitNames()
+	for _, path := range []string{filepath.Join(client.SystemdUnitDir, timerName), job.GetCronPath()} {
+		_, err := os.Stat(path)
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove a scheduled job, whether it was installed as a systemd timer or a cron file.
+// Returns false if it wasn't installed.
+func uninstallScheduledJob(hd *client.HyperdriveClient, job *client.ScheduledJob) (bool, error) {
+	serviceName, timerName := job.GetUnitNames()
+	_, err := os.Stat(filepath.Join(client.SystemdUnitDir, timerName))
+	if err == nil {
+		err = hd.UninstallSystemdTimer(serviceName, timerName)
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("Removed %s.\n", timerName)
+		return true, nil
+	}
+
+	cronPath := job.GetCronPath()
+	_, err = os.Stat(cronPath)
+	if err == nil {
+		err = hd.UninstallCronEntry(cronPath)
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("Removed %s.\n", cronPath)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	scheduledStartCommand string = "scheduled-start"
+	scheduledStartLogFile string = "scheduled-start.log"
+)
+
+var (
+	startDetachFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "detach",
+		Aliases: []string{"d"},
+		Usage:   "If a slashing prevention delay is required, schedule the start in a background helper instead of waiting in the foreground. The helper keeps running if you close your terminal.",
+	}
+)
+
+// Spawn a background helper that starts the service once the slashing prevention delay has elapsed
+func scheduleDetachedStart(c *cli.Context, hd *client.HyperdriveClient, remainingTime time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error getting the path of the Hyperdrive CLI: %w", err)
+	}
+
+	// Send the helper's output to a log file in the config directory
+	configPath, err := homedir.Expand(hd.Context.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("error expanding config path: %w", err)
+	}
+	logPath := filepath.Join(configPath, scheduledStartLogFile)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening scheduled start log [%s]: %w", logPath, err)
+	}
+	defer logFile.Close()
+
+	// Build the args, forwarding the global settings this command was run with
+	args := []string{"--config-path", configPath}
+	if os.Getuid() == 0 {
+		args = append(args, "--allow-root")
+	}
+	args = append(args, "service")
+	for _, composeFile := range getComposeFiles(c) {
+		args = append(args, "--compose-file", composeFile)
+	}
+	args = append(args, scheduledStartCommand)
+
+	// Run it in its own session so it isn't killed when the terminal goes away
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: true,
+	}
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("error starting the scheduled start helper: %w", err)
+	}
+	err = cmd.Process.Release()
+	if err != nil {
+		return fmt.Errorf("error detaching the scheduled start helper: %w", err)
+	}
+
+	safeStartTime := time.Now().Add(remainingTime)
+	fmt.Printf("%sYou have changed validator clients, so Hyperdrive must wait %s before starting them to prevent slashing.%s\n", terminal.ColorYellow, remainingTime.Round(time.Second), terminal.ColorReset)
+	fmt.Printf("The start has been scheduled in the background (PID %d) and will run at %s.\n", cmd.Process.Pid, safeStartTime.Format(time.RFC822))
+	fmt.Printf("You can follow its progress in %s.\n", logPath)
+	return nil
+}
+
+// Wait for the recorded slashing prevention delay to elapse, then start the service; run by the detached helper
+func runScheduledStart(c *cli.Context) error {
+	hd := client.NewHyperdriveClientFromCtx(c)
+	fmt.Printf("[%s] Scheduled start helper running.\n", time.Now().Format(time.RFC3339))
+
+	// Wait for the latest safe start time of all of the VCs
+	state, err := hd.LoadValidatorState()
+	if err != nil {
+		return err
+	}
+	safeStartTime := time.Time{}
+	for _, vcState := range state.Clients {
+		if vcState.SafeStartTime.After(safeStartTime) {
+			safeStartTime = vcState.SafeStartTime
+		}
+	}
+	remainingTime := time.Until(safeStartTime)
+	if remainingTime > 0 {
+		fmt.Printf("[%s] Waiting %s for the slashing prevention delay to elapse.\n", time.Now().Format(time.RFC3339), remainingTime.Round(time.Second))
+		time.Sleep(remainingTime)
+	}
+
+	// Rerun the safety check in case anything changed while waiting
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected, cancelling the scheduled start")
+	}
+	_, remainingTime, err = checkForValidatorChange(hd, cfg)
+	if err != nil {
+		return fmt.Errorf("error verifying the Validator Clients can be safely started: %w", err)
+	}
+	if remainingTime > 0 {
+		fmt.Printf("[%s] Another %s must elapse before the Validator Clients can be safely started, waiting.\n", time.Now().Format(time.RFC3339), remainingTime.Round(time.Second))
+		time.Sleep(remainingTime)
+	}
+	notifySlashingDelayEnded(hd)
+
+	// Start the service
+	fmt.Printf("[%s] Starting Hyperdrive.\n", time.Now().Format(time.RFC3339))
+	err = hd.StartService(getComposeFiles(c))
+	if err != nil {
+		return fmt.Errorf("error starting service: %w", err)
+	}
+	recordValidatorsStarted(hd, cfg)
+	notifyServiceStarted(hd)
+	fmt.Printf("[%s] Hyperdrive started. If your node wallet password isn't saved, run `hyperdrive wallet set-password` to load it.\n", time.Now().Format(time.RFC3339))
+	return nil
+}
@@ -51,7 +51,7 @@ func startService(c *cli.Context, ignoreConfigSuggestion bool) error {
 		}
 	}
 
-	// Update the Prometheus and Grafana config templates with the assigned ports
+	// Update the Prometheus, Grafana, dashboard, and alerting config templates with the assigned ports
 	if cfg.Hyperdrive.Metrics.EnableMetrics.Value {
 		err := hd.UpdatePrometheusConfiguration(cfg)
 		if err != nil {
@@ -61,6 +61,14 @@ func startService(c *cli.Context, ignoreConfigSuggestion bool) error {
 		if err != nil {
 			return err
 		}
+		err = hd.UpdateGrafanaDashboards(cfg)
+		if err != nil {
+			return err
+		}
+		err = hd.UpdateAlertingConfiguration(cfg)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Validate the config
@@ -76,7 +84,7 @@ func startService(c *cli.Context, ignoreConfigSuggestion bool) error {
 
 	if !c.Bool(ignoreSlashTimerFlag.Name) {
 		// Do the client swap check
-		firstRun, err := checkForValidatorChange(hd, cfg)
+		firstRun, remainingTime, err := checkForValidatorChange(hd, cfg)
 		if err != nil {
 			fmt.Printf("%sWARNING: couldn't verify that the Validator Client containers can be safely restarted:\n\t%s\n", terminal.ColorYellow, err.Error())
 			fmt.Println("If you are changing to a different client, it may resubmit an attestation you have already submitted.")
@@ -89,6 +97,15 @@ func startService(c *cli.Context, ignoreConfigSuggestion bool) error {
 				fmt.Println("Cancelled.")
 				return nil
 			}
+		} else if remainingTime > 0 {
+			notifySlashingDelayStarted(hd, remainingTime)
+
+			// Hand the start off to a background helper if requested so it survives the terminal closing
+			if c.Bool(startDetachFlag.Name) {
+				return scheduleDetachedStart(c, hd, remainingTime)
+			}
+			showSlashingDelay(remainingTime)
+			notifySlashingDelayEnded(hd)
 		} else if firstRun {
 			fmt.Println("It looks like this is your first time starting a Validator Client.")
 			existingNode := cliutils.Confirm("Just to be sure, does your node have any existing, active validators attesting on the Beacon Chain?")
@@ -122,6 +139,9 @@ func startService(c *cli.Context, ignoreConfigSuggestion bool) error {
 	if err != nil {
 		return fmt.Errorf("error starting service: %w", err)
 	}
+	recordValidatorsStarted(hd, cfg)
+	notifyServiceStarted(hd)
+	scheduleAlertMetricsIfMissing(hd, cfg)
 
 	// Check wallet status
 	fmt.Println()
@@ -211,78 +231,118 @@ func promptForPassword(c *cli.Context, hd *client.HyperdriveClient) error {
 	return nil
 }
 
-// Check if any of the VCs has changed and force a wait for slashing protection, since all VCs are tied to the BN selection
-func checkForValidatorChange(hd *client.HyperdriveClient, cfg *client.GlobalConfig) (bool, error) {
+// Check if any of the VCs has changed and determine how long to wait for slashing protection, since all VCs are tied to the BN selection.
+// Returns whether or not this is the first time a VC has been run and how long the caller must wait before starting.
+func checkForValidatorChange(hd *client.HyperdriveClient, cfg *client.GlobalConfig) (bool, time.Duration, error) {
 	// Get all of the VCs belonging to the project
 	prefix := cfg.Hyperdrive.ProjectName.Value
 	vcs, err := hd.GetValidatorContainers(prefix + "_")
 	if err != nil {
-		return false, fmt.Errorf("error getting validator client containers: %w", err)
+		return false, 0, fmt.Errorf("error getting validator client containers: %w", err)
 	}
 
-	// Break if there aren't any
-	if len(vcs) == 0 {
-		return true, nil
+	// Load the persisted VC state, which covers containers that have been removed since they were last stopped
+	state, err := hd.LoadValidatorState()
+	if err != nil {
+		return false, 0, err
 	}
 
-	/*
-		// TODO: DEBUG
-			fmt.Println("Found the following Validator Clients:")
-			for _, vc := range vcs {
-				fmt.Println(vc)
-			}
-			fmt.Println()
-	*/
-
 	// Get the map of VCs to their new tags in the config
 	newTagMap, err := getVcContainerTagParamMap(cfg, vcs)
 	if err != nil {
-		return false, err
+		return false, 0, err
+	}
+
+	// Build the list of VCs to check from both Docker and the state file
+	existingVcs := map[string]bool{}
+	candidates := []string{}
+	for _, vc := range vcs {
+		existingVcs[vc] = true
+		candidates = append(candidates, vc)
+	}
+	for vc := range state.Clients {
+		_, isConfigured := newTagMap[vc]
+		if !existingVcs[vc] && isConfigured {
+			candidates = append(candidates, vc)
+		}
+	}
+
+	// Break if there aren't any
+	if len(candidates) == 0 {
+		return true, 0, nil
 	}
 
 	// Get the list of any VCs that can't be safely started yet
 	longestRemainingTime := time.Duration(0)
-	for _, vc := range vcs {
-		remainingTime, err := checkValidatorClient(hd, vc, newTagMap)
+	delayedVcs := []string{}
+	for _, vc := range candidates {
+		remainingTime, err := checkValidatorClient(hd, vc, newTagMap, state.Clients[vc], existingVcs[vc])
 		if err != nil {
-			return false, err
+			return false, 0, err
 		}
 
 		// If this VC has remaining time before it can be safely started, see if it's more than the current max
+		if remainingTime > 0 {
+			delayedVcs = append(delayedVcs, vc)
+		}
 		if remainingTime > longestRemainingTime {
 			longestRemainingTime = remainingTime
 		}
 	}
 
-	// Show the slashing prevention dialog
+	// Persist the safe start time so an interrupted countdown can be resumed
 	if longestRemainingTime > 0 {
-		showSlashingDelay(longestRemainingTime)
+		err = hd.RecordValidatorSafeStartTime(delayedVcs, time.Now().Add(longestRemainingTime))
+		if err != nil {
+			fmt.Printf("%sWARNING: couldn't save the slashing prevention state: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
 	}
-	return false, nil
+	return false, longestRemainingTime, nil
 }
 
-func checkValidatorClient(hd *client.HyperdriveClient, vcName string, newTagMap map[string]string) (time.Duration, error) {
-	// Get the current and pending VC images
-	currentTag, err := hd.GetDockerImage(vcName)
-	if err != nil {
-		return 0, fmt.Errorf("error getting Docker image tag for [%s]: %w", vcName, err)
-	}
-	currentVcType, err := getDockerImageName(currentTag)
-	if err != nil {
-		return 0, fmt.Errorf("error parsing current Docker image tag [%s] for [%s]: %w", currentTag, vcName, err)
+func checkValidatorClient(hd *client.HyperdriveClient, vcName string, newTagMap map[string]string, record *client.ValidatorClientState, exists bool) (time.Duration, error) {
+	// Get the current VC image, falling back to the last recorded one if the container is gone
+	var currentTag string
+	var currentVcType string
+	var err error
+	if exists {
+		currentTag, err = hd.GetDockerImage(vcName)
+		if err != nil {
+			return 0, fmt.Errorf("error getting Docker image tag for [%s]: %w", vcName, err)
+		}
+		currentVcType, err = getDockerImageName(currentTag)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing current Docker image tag [%s] for [%s]: %w", currentTag, vcName, err)
+		}
+	} else if record != nil {
+		currentVcType = record.ClientType
 	}
+
+	// Get the pending VC image
 	pendingTag := newTagMap[vcName]
 	pendingVcType, err := getDockerImageName(pendingTag)
 	if err != nil {
 		return 0, fmt.Errorf("error parsing pending Docker image tag [%s] for [%s]: %w", pendingTag, vcName, err)
 	}
 
+	// Honor a delay that's still pending from an earlier client change, even if the VC has since been removed or changed back
+	if record != nil {
+		remainingTime := time.Until(record.SafeStartTime)
+		if remainingTime > 0 {
+			fmt.Printf("Validator Client [%s] still has a pending slashing prevention delay from an earlier client change.\n", vcName)
+			return remainingTime, nil
+		}
+	}
+
 	// Compare the clients and warn if necessary
-	if currentVcType == pendingVcType {
-		fmt.Printf("Validator Client [%s] is still [%s] - no slashing prevention delay necessary.\n", vcName, currentVcType)
+	if currentVcType == "" || currentVcType == pendingVcType {
+		fmt.Printf("Validator Client [%s] is still [%s] - no slashing prevention delay necessary.\n", vcName, pendingVcType)
 		return 0, nil
-	} else {
-		validatorFinishTime, err := hd.GetDockerContainerShutdownTime(vcName)
+	}
+
+	validatorFinishTime := time.Time{}
+	if exists {
+		validatorFinishTime, err = hd.GetDockerContainerShutdownTime(vcName)
 		if err != nil {
 			return 0, fmt.Errorf("error getting VC [%s] shutdown time: %w", vcName, err)
 		}
@@ -300,25 +360,93 @@ func checkValidatorClient(hd *client.HyperdriveClient, vcName string, newTagMap
 				return 0, fmt.Errorf("error stopping VC [%s]: %w", vcName, err)
 			}
 			validatorFinishTime = time.Now()
+			err = hd.RecordValidatorsStopped([]string{vcName}, validatorFinishTime, map[string]string{vcName: currentVcType}, map[string]string{vcName: currentTag})
+			if err != nil {
+				fmt.Printf("%sWARNING: couldn't save the stop time of VC [%s]: %s%s\n", terminal.ColorYellow, vcName, err.Error(), terminal.ColorReset)
+			}
 		}
+	}
 
-		// Print the warning and start the time lockout
-		safeStartTime := validatorFinishTime.Add(15 * time.Minute)
-		remainingTime := time.Until(safeStartTime)
-		if remainingTime <= 0 {
-			fmt.Printf("Validator Client [%s] has been offline for %s, which is long enough to prevent slashing.\n", vcName, time.Since(validatorFinishTime))
-			return 0, nil
+	// The container may have been recreated since it was stopped, so trust whichever stop time is most recent
+	if record != nil {
+		if record.StopTime.After(validatorFinishTime) {
+			validatorFinishTime = record.StopTime
+		}
+		if !exists && record.StopTime.IsZero() {
+			// It was last seen running and has since been removed, so there's no way to know when it stopped
+			fmt.Printf("%sValidator Client [%s] was removed without recording when it stopped, assuming it just stopped.%s\n", terminal.ColorYellow, vcName, terminal.ColorReset)
+			validatorFinishTime = time.Now()
+			err = hd.RecordValidatorsStopped([]string{vcName}, validatorFinishTime, nil, nil)
+			if err != nil {
+				fmt.Printf("%sWARNING: couldn't save the stop time of VC [%s]: %s%s\n", terminal.ColorYellow, vcName, err.Error(), terminal.ColorReset)
+			}
 		}
+	}
 
-		// If this VC has remaining time before it can be safely started, add it to the list
-		if remainingTime > 0 {
-			fmt.Printf("Validator Client [%s] has changed types from [%s] to [%s].\n", vcName, currentVcType, pendingVcType)
-			fmt.Printf("Only %s has elapsed since you stopped it.\n", time.Since(validatorFinishTime))
+	// Print the warning and start the time lockout
+	safeStartTime := validatorFinishTime.Add(slashingDelay)
+	if record != nil && record.SafeStartTime.After(safeStartTime) {
+		safeStartTime = record.SafeStartTime
+	}
+	remainingTime := time.Until(safeStartTime)
+	if remainingTime <= 0 {
+		fmt.Printf("Validator Client [%s] has been offline for %s, which is long enough to prevent slashing.\n", vcName, time.Since(validatorFinishTime))
+		return 0, nil
+	}
+
+	// This can't be safely started, return its info
+	fmt.Printf("Validator Client [%s] has changed types from [%s] to [%s].\n", vcName, currentVcType, pendingVcType)
+	fmt.Printf("Only %s has elapsed since you stopped it.\n", time.Since(validatorFinishTime))
+	return remainingTime, nil
+}
+
+// Record the client types of the VCs in the config after they've been started.
+// VCs belonging to disabled modules weren't started, so any delay they still have pending is kept.
+func recordValidatorsStarted(hd *client.HyperdriveClient, cfg *client.GlobalConfig) {
+	clientTypes := map[string]string{}
+	tagMap := map[string]string{}
+	for _, module := range cfg.GetAllModuleConfigs() {
+		if !module.IsEnabled() {
+			continue
+		}
+		for name, tag := range module.GetValidatorContainerTagInfo() {
+			fullName := cfg.Hyperdrive.GetDockerArtifactName(string(name))
+			clientType, err := getDockerImageName(tag)
+			if err != nil {
+				continue
+			}
+			clientTypes[fullName] = clientType
+			tagMap[fullName] = tag
 		}
+	}
+	err := hd.RecordValidatorsStarted(clientTypes, tagMap)
+	if err != nil {
+		fmt.Printf("%sWARNING: couldn't save the Validator Client state: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+	}
+}
 
-		// This can't be safely started, return its info
-		return remainingTime, nil
+// Record the stop time of all of the project's VCs before they're shut down
+func recordValidatorsStopped(hd *client.HyperdriveClient, cfg *client.GlobalConfig) error {
+	vcs, err := hd.GetValidatorContainers(cfg.Hyperdrive.ProjectName.Value + "_")
+	if err != nil {
+		return fmt.Errorf("error getting validator client containers: %w", err)
 	}
+
+	clientTypes := map[string]string{}
+	images := map[string]string{}
+	for _, vc := range vcs {
+		image, err := hd.GetDockerImage(vc)
+		if err != nil {
+			return fmt.Errorf("error getting Docker image tag for [%s]: %w", vc, err)
+		}
+		clientType, err := getDockerImageName(image)
+		if err != nil {
+			return fmt.Errorf("error parsing Docker image tag [%s] for [%s]: %w", image, vc, err)
+		}
+		clientTypes[vc] = clientType
+		images[vc] = image
+	}
+	return hd.RecordValidatorsStopped(vcs, time.Now(), clientTypes, images)
 }
 
 func showSlashingDelay(remainingTime time.Duration) {
@@ -327,8 +455,11 @@ func showSlashingDelay(remainingTime time.Duration) {
 	fmt.Println("To prevent slashing, Hyperdrive will delay activating the new client until it is safe.")
 	fmt.Println("See the documentation for a more detailed explanation: https://docs.nodeset.io")
 	fmt.Printf("If you have read the documentation, understand the risks, and want to bypass this cooldown, run `hyperdrive service start --%s`.%s\n\n", ignoreSlashTimerFlag.Name, terminal.ColorReset)
+	waitForSlashingDelay(remainingTime)
+}
 
-	// Wait for 15 minutes
+// Count down the slashing prevention delay
+func waitForSlashingDelay(remainingTime time.Duration) {
 	safeStartTime := time.Now().Add(remainingTime)
 	for remainingTime > 0 {
 		fmt.Printf("Remaining time: %s", remainingTime)
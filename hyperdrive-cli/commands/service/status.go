@@ -26,5 +26,12 @@ func serviceStatus(c *cli.Context) error {
 	}
 
 	// Print service status
-	return hd.PrintServiceStatus(getComposeFiles(c))
+	err = hd.PrintServiceStatus(getComposeFiles(c))
+	if err != nil {
+		return err
+	}
+
+	// Print the last scheduled backup
+	printBackupStatus(hd)
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
 	"github.com/urfave/cli/v2"
 )
 
@@ -19,6 +20,23 @@ func stopService(c *cli.Context) error {
 		return nil
 	}
 
+	// Record when the VCs were stopped so the slashing prevention delay can be enforced later
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if !isNew {
+		err = recordValidatorsStopped(hd, cfg)
+		if err != nil {
+			fmt.Printf("%sWARNING: couldn't record the Validator Client stop time: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
+	}
+
 	// Pause service
-	return hd.PauseService(getComposeFiles(c))
+	err = hd.PauseService(getComposeFiles(c))
+	if err != nil {
+		return err
+	}
+	notifyServiceStopped(hd)
+	return nil
 }
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/utils/sys"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	clientSwitchStateFile      string        = "client-switch.json"
+	fallbackContainerSuffix    string        = "_fallback"
+	clientSwitchPollInterval   time.Duration = 1 * time.Minute
+	minimumClientSwitchFreeGiB uint64        = 100
+)
+
+var (
+	switchClientEcFlag *cli.StringFlag = &cli.StringFlag{
+		Name:  "ec",
+		Usage: "The Execution Client to switch to (geth, nethermind, besu, or reth)",
+	}
+	switchClientBnFlag *cli.StringFlag = &cli.StringFlag{
+		Name:  "bn",
+		Usage: "The Beacon Node to switch to (lighthouse, lodestar, nimbus, prysm, or teku)",
+	}
+	switchClientKeepOldFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "keep-old",
+		Usage: "Keep the old Execution Client and Beacon Node running as a temporary fallback until the new ones are synced (only possible when switching both)",
+	}
+	switchClientWaitFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "wait",
+		Usage: "Wait for the new clients to finish syncing, then clean up the old clients automatically",
+	}
+	switchClientFinishFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "finish",
+		Usage: "Finish a previous client switch by removing the temporary fallback and the old client data, once the new clients are synced",
+	}
+	switchClientIgnoreCpuFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "ignore-cpu-features",
+		Usage: "Switch even though your CPU is missing features required by the 'modern' client images, using the 'portable' images instead",
+	}
+)
+
+// Persistent record of an in-progress client switch
+type clientSwitchState struct {
+	OldExecutionClient config.ExecutionClient `json:"oldExecutionClient"`
+	NewExecutionClient config.ExecutionClient `json:"newExecutionClient"`
+	OldBeaconNode      config.BeaconNode      `json:"oldBeaconNode"`
+	NewBeaconNode      config.BeaconNode      `json:"newBeaconNode"`
+	FallbackContainers []string               `json:"fallbackContainers"`
+	StartTime          time.Time              `json:"startTime"`
+
+	// The fallback settings from before the switch, restored once it's finished
+	RestoreFallback    bool   `json:"restoreFallback"`
+	UseFallbackClients bool   `json:"useFallbackClients"`
+	FallbackEcHttpUrl  string `json:"fallbackEcHttpUrl"`
+	FallbackBnHttpUrl  string `json:"fallbackBnHttpUrl"`
+	FallbackPrysmRpc   string `json:"fallbackPrysmRpc"`
+}
+
+// Switch the locally-managed Execution Client and / or Beacon Node to a different client
+func switchClient(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+
+	// Get the config
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("Settings file not found. Please run `hyperdrive service config` to set up Hyperdrive.")
+	}
+	if !cfg.Hyperdrive.IsLocalMode() {
+		fmt.Println("You use an externally-managed Execution Client and Beacon Node. Hyperdrive cannot switch them for you.")
+		return nil
+	}
+
+	// Resume a pending switch if there is one
+	state, err := loadClientSwitchState(hd)
+	if err != nil {
+		return err
+	}
+	if c.Bool(switchClientFinishFlag.Name) {
+		if state == nil {
+			fmt.Println("There is no client switch in progress.")
+			return nil
+		}
+		return finishClientSwitch(c, hd, cfg, state)
+	}
+	if state != nil {
+		return fmt.Errorf("a client switch started on %s is still in progress; run `hyperdrive service switch-client --%s` to complete it first", state.StartTime.Format(time.RFC822), switchClientFinishFlag.Name)
+	}
+
+	// Parse the requested clients
+	oldEc := cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value
+	oldBn := cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Value
+	newEc := oldEc
+	newBn := oldBn
+	if c.IsSet(switchClientEcFlag.Name) {
+		newEc, err = parseClientOption(c.String(switchClientEcFlag.Name), cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Options)
+		if err != nil {
+			return err
+		}
+	}
+	if c.IsSet(switchClientBnFlag.Name) {
+		newBn, err = parseClientOption(c.String(switchClientBnFlag.Name), cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Options)
+		if err != nil {
+			return err
+		}
+	}
+	switchingEc := newEc != oldEc
+	switchingBn := newBn != oldBn
+	if !switchingEc && !switchingBn {
+		fmt.Printf("You're already using %s and %s; there's nothing to switch.\n", oldEc, oldBn)
+		return nil
+	}
+
+	// Check the CPU features
+	missingFeatures := sys.GetMissingModernCpuFeatures()
+	if len(missingFeatures) > 0 {
+		fmt.Printf("%sYour CPU is missing support for the following features required by 'modern' client images:\n", terminal.ColorYellow)
+		for _, name := range missingFeatures {
+			fmt.Printf("  - %s\n", name)
+		}
+		fmt.Printf("The new client would have to use its slower 'portable' image instead, which may not keep up on this machine.%s\n\n", terminal.ColorReset)
+		if !c.Bool(switchClientIgnoreCpuFlag.Name) {
+			return fmt.Errorf("your CPU doesn't support the new client's modern image; if you still want to switch, run this again with `--%s`", switchClientIgnoreCpuFlag.Name)
+		}
+		fmt.Printf("%sContinuing with the portable image because --%s was provided.%s\n\n", terminal.ColorYellow, switchClientIgnoreCpuFlag.Name, terminal.ColorReset)
+	}
+
+	// Check the disk space
+	err = checkClientSwitchDiskSpace(c, hd, cfg, switchingEc, switchingBn)
+	if err != nil {
+		return err
+	}
+
+	// Work out if the old clients can be kept as a temporary fallback
+	keepOld := false
+	if cfg.Hyperdrive.Fallback.UseFallbackClients.Value {
+		fmt.Println("You already have fallback clients configured, so they will be used while the new clients sync.")
+	} else if switchingEc && switchingBn {
+		if newBn == config.BeaconNode_Prysm && oldBn != config.BeaconNode_Prysm {
+			fmt.Printf("%sPrysm Validator Clients can't use a non-Prysm Beacon Node as a fallback, so the old clients can't be kept running.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		} else {
+			keepOld = c.Bool(switchClientKeepOldFlag.Name) || (!c.Bool(utils.YesFlag.Name) && utils.Confirm(fmt.Sprintf("Would you like to keep %s and %s running as a temporary fallback until %s and %s are synced? This needs enough disk space for both pairs of clients.", oldEc, oldBn, newEc, newBn)))
+		}
+	} else {
+		fmt.Printf("%sNOTE: the old client can only be kept as a temporary fallback when you switch both the Execution Client and Beacon Node, since each one needs a partner to stay in sync.\nYour Validator Clients will be offline until the new client is synced unless you configure fallback clients with `hyperdrive service config`.%s\n\n", terminal.ColorYellow, terminal.ColorReset)
+	}
+
+	// Confirm
+	fmt.Println("The following changes will be made:")
+	if switchingEc {
+		fmt.Printf("\tExecution Client: %s -> %s\n", oldEc, newEc)
+	}
+	if switchingBn {
+		fmt.Printf("\tBeacon Node:      %s -> %s\n", oldBn, newBn)
+		fmt.Printf("%sChanging your Beacon Node changes your Validator Clients, so Hyperdrive will enforce the 15 minute slashing prevention delay before starting them.%s\n", terminal.ColorYellow, terminal.ColorReset)
+	}
+	fmt.Println()
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Are you sure you want to switch clients?")) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Set up the state record
+	state = &clientSwitchState{
+		OldExecutionClient: oldEc,
+		NewExecutionClient: newEc,
+		OldBeaconNode:      oldBn,
+		NewBeaconNode:      newBn,
+		StartTime:          time.Now(),
+	}
+
+	// Keep the old clients running as a fallback
+	if keepOld {
+		err = startTemporaryFallback(hd, cfg, state)
+		if err != nil {
+			removeTemporaryFallback(hd, state)
+			return fmt.Errorf("error starting the old clients as a fallback: %w\nRun `hyperdrive service start` to bring your original clients back", err)
+		}
+	}
+
+	// Update the config, removing the fallback copies if the switch can't be recorded so they aren't left behind
+	cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value = newEc
+	cfg.Hyperdrive.LocalBeaconClient.BeaconNode.Value = newBn
+	err = hd.SaveConfig(cfg)
+	if err != nil {
+		removeTemporaryFallback(hd, state)
+		return fmt.Errorf("error saving config: %w", err)
+	}
+	err = saveClientSwitchState(hd, state)
+	if err != nil {
+		removeTemporaryFallback(hd, state)
+		return err
+	}
+
+	// Restart with the new clients; this enforces the slashing delay if the VCs are changing
+	fmt.Println("Starting the new clients...")
+	err = startService(c, true)
+	if err != nil {
+		return fmt.Errorf("error starting Hyperdrive: %w", err)
+	}
+
+	// Print the summary
+	fmt.Println()
+	fmt.Printf("%s=== Client Switch Summary ===%s\n", terminal.ColorGreen, terminal.ColorReset)
+	if switchingEc {
+		fmt.Printf("Execution Client: %s -> %s\n", oldEc, newEc)
+	}
+	if switchingBn {
+		fmt.Printf("Beacon Node:      %s -> %s\n", oldBn, newBn)
+	}
+	if keepOld {
+		fmt.Printf("Temporary fallback: %s and %s (containers %v)\n", oldEc, oldBn, state.FallbackContainers)
+	}
+	fmt.Println("The old client data will be deleted once the new clients have finished syncing.")
+	fmt.Println()
+
+	if !c.Bool(switchClientWaitFlag.Name) {
+		fmt.Printf("Follow the sync progress with `hyperdrive service sync`, then run `hyperdrive service switch-client --%s` to clean up the old clients.\n", switchClientFinishFlag.Name)
+		return nil
+	}
+	return finishClientSwitch(c, hd, cfg, state)
+}
+
+// Finish a client switch by removing the fallback and old client data once the new clients are synced
+func finishClientSwitch(c *cli.Context, hd *client.HyperdriveClient, cfg *client.GlobalConfig, state *clientSwitchState) error {
+	// Wait for the new clients to sync
+	for {
+		ecSynced, bnSynced, err := getPrimaryClientSyncStatus(hd)
+		if err != nil {
+			return err
+		}
+		if ecSynced && bnSynced {
+			break
+		}
+		if !c.Bool(switchClientWaitFlag.Name) {
+			fmt.Println("The new clients haven't finished syncing yet, so the old clients can't be removed. Check on them with `hyperdrive service sync`.")
+			return nil
+		}
+		fmt.Printf("Waiting for the new clients to sync (Execution Client synced: %t, Beacon Node synced: %t)...\n", ecSynced, bnSynced)
+		time.Sleep(clientSwitchPollInterval)
+	}
+	fmt.Println("The new clients are synced.")
+
+	// Remove the fallback containers
+	removeTemporaryFallback(hd, state)
+
+	// Restore the fallback settings
+	if state.RestoreFallback {
+		cfg.Hyperdrive.Fallback.UseFallbackClients.Value = state.UseFallbackClients
+		cfg.Hyperdrive.Fallback.EcHttpUrl.Value = state.FallbackEcHttpUrl
+		cfg.Hyperdrive.Fallback.BnHttpUrl.Value = state.FallbackBnHttpUrl
+		cfg.Hyperdrive.Fallback.PrysmRpcUrl.Value = state.FallbackPrysmRpc
+		err := hd.SaveConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("error restoring fallback settings: %w", err)
+		}
+	}
+
+	// Delete the old client data
+	if state.OldExecutionClient != state.NewExecutionClient {
+		ecName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+		fmt.Printf("Deleting the old %s data...\n", state.OldExecutionClient)
+		err := hd.DeleteClientDataFolder(ecName, clientDataVolumeName, string(state.OldExecutionClient))
+		if err != nil {
+			fmt.Printf("%sWARNING: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
+	}
+	if state.OldBeaconNode != state.NewBeaconNode {
+		bnName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
+		fmt.Printf("Deleting the old %s data...\n", state.OldBeaconNode)
+		err := hd.DeleteClientDataFolder(bnName, clientDataVolumeName, string(state.OldBeaconNode))
+		if err != nil {
+			fmt.Printf("%sWARNING: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
+	}
+
+	// Clear the state
+	err := deleteClientSwitchState(hd)
+	if err != nil {
+		return err
+	}
+
+	// Apply the restored fallback settings
+	if state.RestoreFallback {
+		fmt.Println("Restarting Hyperdrive with your original fallback settings...")
+		err = startService(c, true)
+		if err != nil {
+			return fmt.Errorf("error starting Hyperdrive: %w", err)
+		}
+	}
+
+	fmt.Printf("%sClient switch complete.%s\n", terminal.ColorGreen, terminal.ColorReset)
+	return nil
+}
+
+// Replace the old EC and BN with copies that the daemon and VCs can use as fallbacks while the new ones sync.
+// The copies share the client data volume with the new clients, so each new client must keep its data in a different folder than the copy it replaces.
+func startTemporaryFallback(hd *client.HyperdriveClient, cfg *client.GlobalConfig, state *clientSwitchState) error {
+	if state.OldExecutionClient == state.NewExecutionClient || state.OldBeaconNode == state.NewBeaconNode {
+		return fmt.Errorf("the old clients can only be kept as a fallback when both the Execution Client and Beacon Node are changing, since the new clients would otherwise use the same data folders")
+	}
+	ecName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+	bnName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
+	ecFallbackName := ecName + fallbackContainerSuffix
+	bnFallbackName := bnName + fallbackContainerSuffix
+	networkName := cfg.Hyperdrive.GetDockerArtifactName("net")
+
+	// Start the EC copy first so the BN copy has something to follow
+	fmt.Printf("Replacing %s with %s as a temporary fallback...\n", ecName, ecFallbackName)
+	err := hd.CloneContainer(ecName, ecFallbackName, networkName, nil)
+	if err != nil {
+		return err
+	}
+	state.FallbackContainers = append(state.FallbackContainers, ecFallbackName)
+
+	// Point the BN copy at the EC copy
+	ecPort := cfg.Hyperdrive.LocalExecutionClient.HttpPort.Value
+	wsPort := cfg.Hyperdrive.LocalExecutionClient.WebsocketPort.Value
+	enginePort := cfg.Hyperdrive.LocalExecutionClient.EnginePort.Value
+	fmt.Printf("Replacing %s with %s as a temporary fallback...\n", bnName, bnFallbackName)
+	err = hd.CloneContainer(bnName, bnFallbackName, networkName, map[string]string{
+		"EC_HTTP_ENDPOINT":      fmt.Sprintf("http://%s:%d", ecFallbackName, ecPort),
+		"EC_WS_ENDPOINT":        fmt.Sprintf("ws://%s:%d", ecFallbackName, wsPort),
+		"EC_ENGINE_ENDPOINT":    fmt.Sprintf("http://%s:%d", ecFallbackName, enginePort),
+		"EC_ENGINE_WS_ENDPOINT": fmt.Sprintf("ws://%s:%d", ecFallbackName, enginePort),
+	})
+	if err != nil {
+		return err
+	}
+	state.FallbackContainers = append(state.FallbackContainers, bnFallbackName)
+
+	// Save the current fallback settings and point them at the copies
+	fallback := cfg.Hyperdrive.Fallback
+	state.RestoreFallback = true
+	state.UseFallbackClients = fallback.UseFallbackClients.Value
+	state.FallbackEcHttpUrl = fallback.EcHttpUrl.Value
+	state.FallbackBnHttpUrl = fallback.BnHttpUrl.Value
+	state.FallbackPrysmRpc = fallback.PrysmRpcUrl.Value
+	fallback.UseFallbackClients.Value = true
+	fallback.EcHttpUrl.Value = fmt.Sprintf("http://%s:%d", ecFallbackName, ecPort)
+	fallback.BnHttpUrl.Value = fmt.Sprintf("http://%s:%d", bnFallbackName, cfg.Hyperdrive.LocalBeaconClient.HttpPort.Value)
+	if state.OldBeaconNode == config.BeaconNode_Prysm {
+		fallback.PrysmRpcUrl.Value = fmt.Sprintf("%s:%d", bnFallbackName, cfg.Hyperdrive.LocalBeaconClient.Prysm.RpcPort.Value)
+	}
+	return nil
+}
+
+// Stop and remove the temporary fallback copies of the old clients
+func removeTemporaryFallback(hd *client.HyperdriveClient, state *clientSwitchState) {
+	for _, container := range state.FallbackContainers {
+		fmt.Printf("Removing temporary fallback %s...\n", container)
+		err := hd.StopContainer(container)
+		if err != nil {
+			fmt.Printf("%sWARNING: couldn't stop %s: %s%s\n", terminal.ColorYellow, container, err.Error(), terminal.ColorReset)
+		}
+		err = hd.RemoveContainer(container)
+		if err != nil {
+			fmt.Printf("%sWARNING: couldn't remove %s: %s%s\n", terminal.ColorYellow, container, err.Error(), terminal.ColorReset)
+		}
+	}
+}
+
+// Make sure there's enough free disk space for the new clients, using the size of the existing client data as an estimate
+func checkClientSwitchDiskSpace(c *cli.Context, hd *client.HyperdriveClient, cfg *client.GlobalConfig, switchingEc bool, switchingBn bool) error {
+	required := minimumClientSwitchFreeGiB * 1024 * 1024 * 1024
+	estimate := uint64(0)
+	check := func(containerID config.ContainerID) {
+		containerName := cfg.Hyperdrive.GetDockerArtifactName(string(containerID))
+		volume, err := hd.GetClientVolumeName(containerName, clientDataVolumeName)
+		if err != nil {
+			return
+		}
+		size, err := hd.GetVolumeSize(volume)
+		if err != nil || size < 0 {
+			return
+		}
+		estimate += uint64(size)
+	}
+	if switchingEc {
+		check(config.ContainerID_ExecutionClient)
+	}
+	if switchingBn {
+		check(config.ContainerID_BeaconNode)
+	}
+	if estimate > required {
+		required = estimate
+	}
+
+	// Get the free space where Docker keeps its volumes
+	rootDir, err := hd.GetDockerRootDir()
+	if err != nil {
+		return err
+	}
+	var stat syscall.Statfs_t
+	err = syscall.Statfs(rootDir, &stat)
+	if err != nil {
+		fmt.Printf("%sWARNING: couldn't check the free space of [%s]: %s%s\n", terminal.ColorYellow, rootDir, err.Error(), terminal.ColorReset)
+		return nil
+	}
+	free := stat.Bavail * uint64(stat.Bsize)
+
+	gib := float64(1024 * 1024 * 1024)
+	fmt.Printf("Free disk space in %s: %.2f GiB (estimated requirement: %.2f GiB)\n", rootDir, float64(free)/gib, float64(required)/gib)
+	if free < required {
+		fmt.Printf("%sWARNING: you may not have enough free disk space for the new clients to sync while the old data is still present.%s\n", terminal.ColorYellow, terminal.ColorReset)
+		if !(c.Bool(utils.YesFlag.Name) || utils.Confirm("Do you want to continue anyway?")) {
+			return fmt.Errorf("cancelled due to insufficient disk space")
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// Check if the primary EC and BN are synced
+func getPrimaryClientSyncStatus(hd *client.HyperdriveClient) (bool, bool, error) {
+	response, err := hd.Api.Service.ClientStatus()
+	if err != nil {
+		return false, false, fmt.Errorf("error getting client status: %w", err)
+	}
+	ecStatus := response.Data.EcManagerStatus.PrimaryClientStatus
+	bnStatus := response.Data.BcManagerStatus.PrimaryClientStatus
+	return ecStatus.IsSynced, bnStatus.IsSynced, nil
+}
+
+// Get the option with the provided value from a list of client options
+func parseClientOption[ClientType ~string](value string, options []*config.ParameterOption[ClientType]) (ClientType, error) {
+	for _, option := range options {
+		if string(option.Value) == value {
+			return option.Value, nil
+		}
+	}
+	return "", fmt.Errorf("[%s] is not a supported client", value)
+}
+
+// Load the in-progress client switch state, or nil if there isn't one
+func loadClientSwitchState(hd *client.HyperdriveClient) (*clientSwitchState, error) {
+	path, err := homedir.Expand(filepath.Join(hd.Context.ConfigPath, clientSwitchStateFile))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding client switch state path: %w", err)
+	}
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading client switch state [%s]: %w", path, err)
+	}
+	state := &clientSwitchState{}
+	err = json.Unmarshal(bytes, state)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing client switch state [%s]: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save the in-progress client switch state
+func saveClientSwitchState(hd *client.HyperdriveClient, state *clientSwitchState) error {
+	path, err := homedir.Expand(filepath.Join(hd.Context.ConfigPath, clientSwitchStateFile))
+	if err != nil {
+		return fmt.Errorf("error expanding client switch state path: %w", err)
+	}
+	bytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing client switch state: %w", err)
+	}
+	err = os.WriteFile(path, bytes, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing client switch state [%s]: %w", path, err)
+	}
+	return nil
+}
+
+// Delete the client switch state once it's complete
+func deleteClientSwitchState(hd *client.HyperdriveClient) error {
+	path, err := homedir.Expand(filepath.Join(hd.Context.ConfigPath, clientSwitchStateFile))
+	if err != nil {
+		return fmt.Errorf("error expanding client switch state path: %w", err)
+	}
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting client switch state [%s]: %w", path, err)
+	}
+	return nil
+}
@@ -1,46 +1,122 @@
 package service
 
 import (
+	"encoding/json"
 	"fmt"
-	"math"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
 	"github.com/rocket-pool/node-manager-core/api/types"
+	"github.com/rocket-pool/node-manager-core/config"
 	"github.com/urfave/cli/v2"
 )
 
-// When printing sync percents, we should avoid printing 100%.
-// This function is only called if we're still syncing,
-// and the `%0.2f` token will round up if we're above 99.99%.
-func SyncRatioToPercent(in float64) float64 {
-	return math.Min(99.99, in*100)
-	// TODO: INCORPORATE THIS
-}
+const (
+	syncWatchInterval time.Duration = 15 * time.Second
+	syncRateWindow    time.Duration = 10 * time.Minute
+	syncQueryTimeout  time.Duration = 5 * time.Second
+	syncLogTail       string        = "200"
+)
+
+var (
+	syncWatchFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "watch",
+		Aliases: []string{"w"},
+		Usage:   "Keep sampling the sync progress of your clients to show their sync rates and, once they reach their final sync stage, their estimated time to completion. Press Ctrl+C to exit.",
+	}
+
+	// Nethermind reports the progress of each of its sync stages, e.g. "Old Headers 1,000 / 17,000,000 ( 0.01 %)"
+	nethermindProgressRegex *regexp.Regexp = regexp.MustCompile(`(Old Headers|Old Bodies|Old Receipts|Beacon Headers|Downloaded)\s.*?\(\s*([\d.]+)\s*%\s*\)`)
+
+	// Reth reports the progress of each stage of its pipeline, e.g. "stage=Execution ... stage_progress=12.34%"
+	rethProgressRegex *regexp.Regexp = regexp.MustCompile(`stage=(\S+).*?stage_progress="?([\d.]+)%`)
 
-func printClientStatus(status *types.ClientStatus, name string) {
+	// The last sync stage each client reports in its logs; the time remaining can only be estimated once a client reaches it,
+	// since the earlier stages take very different amounts of time
+	finalEcLogStages map[config.ExecutionClient]string = map[config.ExecutionClient]string{
+		config.ExecutionClient_Nethermind: "Old Receipts",
+		config.ExecutionClient_Reth:       "Finish",
+	}
+)
+
+// Print the sync status of a client, recording its progress in the history to estimate how long it has left
+func printClientStatus(hd *client.HyperdriveClient, cfg *client.GlobalConfig, history *client.SyncHistory, status *types.ClientStatus, name string, isEc bool, isFallback bool) {
 
 	if status.Error != "" {
 		fmt.Printf("Your %s is unavailable (%s).\n", name, status.Error)
 		return
 	}
 
+	endpoint := getClientEndpoint(hd, cfg, isEc, isFallback)
 	if status.IsSynced {
 		fmt.Printf("Your %s is fully synced.\n", name)
+		printHeadInfo(endpoint, isEc)
+		return
+	}
+
+	// Fall back to the client's logs if it doesn't report sync progress
+	progress := status.SyncProgress
+	stage := ""
+	fromLogs := false
+	if isEc && !isFallback && progress == 0 && cfg.Hyperdrive.IsLocalMode() {
+		progress, stage = getEcProgressFromLogs(hd, cfg)
+		fromLogs = stage != ""
+	}
+
+	if progress == 0 {
+		fmt.Printf("Your %s is still syncing (%0.2f%%).\n", name, client.SyncRatioToPercent(progress))
+		if isEc {
+			fmt.Printf("\tNOTE: your %s may not report sync progress.\n\tYou should check its logs to review it.\n", name)
+		}
+		printHeadInfo(endpoint, isEc)
+		return
+	}
+
+	// The progress an Execution Client reports only covers its current stage, so label it as such
+	isFinalStage := !isEc
+	switch {
+	case fromLogs:
+		isFinalStage = stage == finalEcLogStages[cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value]
+		fmt.Printf("Your %s is still syncing (%s stage: %0.2f%%, from its logs).\n", name, stage, client.SyncRatioToPercent(progress))
+	case isEc:
+		stage = "block download"
+		fmt.Printf("Your %s is still syncing (block download stage: %0.2f%%).\n", name, client.SyncRatioToPercent(progress))
+	default:
+		fmt.Printf("Your %s is still syncing (%0.2f%%).\n", name, client.SyncRatioToPercent(progress))
+	}
+	printHeadInfo(endpoint, isEc)
+	if !isFinalStage {
+		fmt.Printf("\tThis is only the progress of the %s stage; your %s may have more stages to go after it, so the time remaining can't be estimated yet.\n", stage, name)
 		return
 	}
 
-	fmt.Printf("Your %s is still syncing (%0.2f%%).\n", name, client.SyncRatioToPercent(status.SyncProgress))
-	if strings.Contains(name, "execution") && status.SyncProgress == 0 {
-		fmt.Printf("\tNOTE: your %s may not report sync progress.\n\tYou should check its logs to review it.\n", name)
+	// Estimate the time remaining
+	clientKey := strings.ReplaceAll(name, " ", "-")
+	if stage != "" {
+		clientKey += "-" + strings.ReplaceAll(stage, " ", "-")
 	}
+	history.AddSample(clientKey, progress, time.Now())
+	samples := history.Clients[clientKey]
+	rate, hasRate := getSyncRate(samples)
+	if !hasRate {
+		fmt.Println("\tNot enough samples to estimate the time remaining yet.")
+		return
+	}
+	eta, _ := estimateSyncEta(samples)
+	fmt.Printf("\tSync rate: %0.2f%%/hour, estimated time remaining: %s\n", rate*100*time.Hour.Seconds(), eta.Round(time.Second))
 }
 
-func printSyncProgress(status *types.ClientManagerStatus, name string) {
+func printSyncProgress(hd *client.HyperdriveClient, cfg *client.GlobalConfig, history *client.SyncHistory, status *types.ClientManagerStatus, name string, isEc bool) {
 
 	// Print primary client status
-	printClientStatus(&status.PrimaryClientStatus, fmt.Sprintf("primary %s client", name))
+	printClientStatus(hd, cfg, history, &status.PrimaryClientStatus, fmt.Sprintf("primary %s client", name), isEc, false)
 
 	if !status.FallbackEnabled {
 		fmt.Printf("You do not have a fallback %s client enabled.\n", name)
@@ -48,7 +124,7 @@ func printSyncProgress(status *types.ClientManagerStatus, name string) {
 	}
 
 	// A fallback is enabled, so print fallback client status
-	printClientStatus(&status.FallbackClientStatus, fmt.Sprintf("fallback %s client", name))
+	printClientStatus(hd, cfg, history, &status.FallbackClientStatus, fmt.Sprintf("fallback %s client", name), isEc, true)
 }
 
 func getSyncProgress(c *cli.Context) error {
@@ -67,6 +143,33 @@ func getSyncProgress(c *cli.Context) error {
 		return err
 	}
 
+	// Load the sync history from previous runs
+	history, err := hd.LoadSyncHistory()
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't load the sync history, time estimates will start from scratch: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		history = &client.SyncHistory{
+			Clients: map[string][]client.SyncSample{},
+		}
+	}
+
+	if !c.Bool(syncWatchFlag.Name) {
+		return printSyncStatus(hd, cfg, history)
+	}
+
+	// Keep sampling until the user exits
+	for {
+		fmt.Printf("=== %s ===\n", time.Now().Format(time.RFC822))
+		err = printSyncStatus(hd, cfg, history)
+		if err != nil {
+			fmt.Printf("%sWARNING: Couldn't get the client status: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+		}
+		fmt.Println()
+		time.Sleep(syncWatchInterval)
+	}
+}
+
+// Print the status of all of the clients and save the updated sync history
+func printSyncStatus(hd *client.HyperdriveClient, cfg *client.GlobalConfig, history *client.SyncHistory) error {
 	// Get node status
 	status, err := hd.Api.Service.ClientStatus()
 	if err != nil {
@@ -74,11 +177,193 @@ func getSyncProgress(c *cli.Context) error {
 	}
 
 	// Print EC status
-	printSyncProgress(&status.Data.EcManagerStatus, "execution")
+	printSyncProgress(hd, cfg, history, &status.Data.EcManagerStatus, "execution", true)
 
 	// Print CC status
-	printSyncProgress(&status.Data.BcManagerStatus, "beacon")
+	printSyncProgress(hd, cfg, history, &status.Data.BcManagerStatus, "beacon", false)
 
-	// Return
+	err = hd.SaveSyncHistory(history)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't save the sync history: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+	}
 	return nil
 }
+
+// Get the sync rate in progress per second using the samples within the rate window, or false if there aren't enough.
+// If there aren't enough recent samples, the last two samples are used so estimates carry over between runs.
+func getSyncRate(samples []client.SyncSample) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	last := samples[len(samples)-1]
+	first := samples[len(samples)-2]
+	cutoff := last.Time.Add(-syncRateWindow)
+	for _, sample := range samples[:len(samples)-1] {
+		if !sample.Time.Before(cutoff) {
+			first = sample
+			break
+		}
+	}
+
+	elapsed := last.Time.Sub(first.Time)
+	gained := last.Progress - first.Progress
+	if elapsed <= 0 || gained <= 0 {
+		return 0, false
+	}
+	return gained / elapsed.Seconds(), true
+}
+
+// Estimate the time remaining until the samples reach completion, or false if there isn't enough data for an estimate yet
+func estimateSyncEta(samples []client.SyncSample) (time.Duration, bool) {
+	rate, hasRate := getSyncRate(samples)
+	if !hasRate {
+		return 0, false
+	}
+	remaining := (1 - samples[len(samples)-1].Progress) / rate
+	return time.Duration(remaining * float64(time.Second)), true
+}
+
+// Get the HTTP endpoint of a client that can be reached from the host, or an empty string if it isn't available
+func getClientEndpoint(hd *client.HyperdriveClient, cfg *client.GlobalConfig, isEc bool, isFallback bool) string {
+	if isFallback {
+		if isEc {
+			return cfg.Hyperdrive.Fallback.EcHttpUrl.Value
+		}
+		return cfg.Hyperdrive.Fallback.BnHttpUrl.Value
+	}
+	if !cfg.Hyperdrive.IsLocalMode() {
+		if isEc {
+			return cfg.Hyperdrive.ExternalExecutionClient.HttpUrl.Value
+		}
+		return cfg.Hyperdrive.ExternalBeaconClient.HttpUrl.Value
+	}
+
+	// Local clients use their container names as hostnames, which only resolve inside Docker, so use their IPs instead
+	containerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_BeaconNode))
+	port := cfg.Hyperdrive.LocalBeaconClient.HttpPort.Value
+	if isEc {
+		containerName = cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+		port = cfg.Hyperdrive.LocalExecutionClient.HttpPort.Value
+	}
+	ip, err := hd.GetContainerIP(containerName)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("http://%s:%d", ip, port)
+}
+
+// Print the head block or slot of a client and how far it is from the chain head, if available
+func printHeadInfo(endpoint string, isEc bool) {
+	if endpoint == "" {
+		return
+	}
+	httpClient := &http.Client{
+		Timeout: syncQueryTimeout,
+	}
+	if isEc {
+		printEcHeadInfo(httpClient, endpoint)
+	} else {
+		printBnHeadInfo(httpClient, endpoint)
+	}
+}
+
+// Print the current and highest known blocks of an Execution Client
+func printEcHeadInfo(httpClient *http.Client, endpoint string) {
+	var syncing struct {
+		Result json.RawMessage `json:"result"`
+	}
+	err := client.PostJsonRpc(httpClient, endpoint, "eth_syncing", &syncing)
+	if err != nil {
+		return
+	}
+
+	// eth_syncing returns false once the client is synced
+	var progress struct {
+		CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+		HighestBlock hexutil.Uint64 `json:"highestBlock"`
+	}
+	if json.Unmarshal(syncing.Result, &progress) == nil && progress.HighestBlock > 0 {
+		distance := uint64(0)
+		if progress.HighestBlock > progress.CurrentBlock {
+			distance = uint64(progress.HighestBlock - progress.CurrentBlock)
+		}
+		fmt.Printf("\tHead block: %d of %d (%d blocks behind)\n", progress.CurrentBlock, progress.HighestBlock, distance)
+		return
+	}
+
+	var blockNumber struct {
+		Result hexutil.Uint64 `json:"result"`
+	}
+	err = client.PostJsonRpc(httpClient, endpoint, "eth_blockNumber", &blockNumber)
+	if err != nil {
+		return
+	}
+	fmt.Printf("\tHead block: %d\n", blockNumber.Result)
+}
+
+// Print the head slot of a Beacon Node and its distance from the chain head
+func printBnHeadInfo(httpClient *http.Client, endpoint string) {
+	resp, err := httpClient.Get(strings.TrimSuffix(endpoint, "/") + "/eth/v1/node/syncing")
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var syncing struct {
+		Data struct {
+			HeadSlot     string `json:"head_slot"`
+			SyncDistance string `json:"sync_distance"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&syncing)
+	if err != nil {
+		return
+	}
+	headSlot, err := strconv.ParseUint(syncing.Data.HeadSlot, 10, 64)
+	if err != nil {
+		return
+	}
+	distance, err := strconv.ParseUint(syncing.Data.SyncDistance, 10, 64)
+	if err != nil {
+		return
+	}
+	fmt.Printf("\tHead slot: %d (%d slots behind)\n", headSlot, distance)
+}
+
+// Get the sync progress of a local Execution Client from its logs, for clients that don't report it over the API.
+// Returns the progress of the current sync stage and its name, or 0 if it couldn't be determined.
+func getEcProgressFromLogs(hd *client.HyperdriveClient, cfg *client.GlobalConfig) (float64, string) {
+	var regex *regexp.Regexp
+	switch cfg.Hyperdrive.LocalExecutionClient.ExecutionClient.Value {
+	case config.ExecutionClient_Nethermind:
+		regex = nethermindProgressRegex
+	case config.ExecutionClient_Reth:
+		regex = rethProgressRegex
+	default:
+		return 0, ""
+	}
+
+	containerName := cfg.Hyperdrive.GetDockerArtifactName(string(config.ContainerID_ExecutionClient))
+	logs, err := hd.GetContainerLogs(containerName, syncLogTail)
+	if err != nil {
+		return 0, ""
+	}
+
+	// Use the most recent progress line
+	lines := strings.Split(logs, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		match := regex.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		percent, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		return percent / 100, match[1]
+	}
+	return 0, ""
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+)
+
+func TestEstimateSyncEta(t *testing.T) {
+	start := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	// Get a sample taken the given time after the start
+	sample := func(offset time.Duration, progress float64) client.SyncSample {
+		return client.SyncSample{
+			Time:     start.Add(offset),
+			Progress: progress,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		samples  []client.SyncSample
+		expected time.Duration
+		hasEta   bool
+	}{
+		{
+			name:    "no samples",
+			samples: nil,
+		},
+		{
+			name:    "one sample",
+			samples: []client.SyncSample{sample(0, 0.5)},
+		},
+		{
+			name:     "two samples",
+			samples:  []client.SyncSample{sample(0, 0.5), sample(100*time.Second, 0.6)},
+			expected: 400 * time.Second,
+			hasEta:   true,
+		},
+		{
+			name:    "no progress",
+			samples: []client.SyncSample{sample(0, 0.5), sample(time.Minute, 0.5)},
+		},
+		{
+			name:    "progress went backwards",
+			samples: []client.SyncSample{sample(0, 0.5), sample(time.Minute, 0.4)},
+		},
+		{
+			name:    "same time",
+			samples: []client.SyncSample{sample(0, 0.5), sample(0, 0.6)},
+		},
+		{
+			name: "only samples in the rate window are used",
+			samples: []client.SyncSample{
+				sample(0, 0),
+				sample(20*time.Minute, 0.5),
+				sample(25*time.Minute, 0.55),
+				sample(30*time.Minute, 0.6),
+			},
+			expected: 40 * time.Minute,
+			hasEta:   true,
+		},
+		{
+			name: "last two samples are used when none are recent",
+			samples: []client.SyncSample{
+				sample(0, 0.2),
+				sample(time.Hour, 0.3),
+				sample(2*time.Hour, 0.4),
+			},
+			expected: 6 * time.Hour,
+			hasEta:   true,
+		},
+		{
+			name:     "finished syncing",
+			samples:  []client.SyncSample{sample(0, 0.9), sample(time.Minute, 1)},
+			expected: 0,
+			hasEta:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eta, hasEta := estimateSyncEta(test.samples)
+			if hasEta != test.hasEta {
+				t.Fatalf("expected hasEta to be %t, got %t", test.hasEta, hasEta)
+			}
+			if (eta - test.expected).Abs() > time.Second {
+				t.Errorf("expected an ETA of %s, got %s", test.expected, eta)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/context"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	systemdUnlockAttempts int = 3
+
+	// The global config path flag and its default folder in the user's home directory
+	configPathFlagName  string = "config-path"
+	defaultConfigFolder string = ".hyperdrive"
+)
+
+var (
+	systemdAskPasswordFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "ask-password",
+		Aliases: []string{"p"},
+		Usage:   "If the node wallet password isn't saved, prompt for it at boot through systemd's password agents (answer with `sudo systemd-tty-ask-password-agent`) and load the wallet with it",
+	}
+	systemdHealthTimeoutFlag *cli.DurationFlag = &cli.DurationFlag{
+		Name:    "timeout",
+		Aliases: []string{"t"},
+		Usage:   "How long to wait for the daemon to respond after starting the service at boot before the unit fails",
+		Value:   5 * time.Minute,
+	}
+)
+
+// Install a systemd unit that starts the service at boot
+func installSystemdUnit(c *cli.Context) error {
+	// Under sudo the default config path is in root's home, but the unit runs as the user that invoked it
+	unitUser, err := client.GetSystemdUnitUser()
+	if err != nil {
+		return err
+	}
+	if os.Getenv("SUDO_USER") != "" && !c.IsSet(configPathFlagName) {
+		context.GetHyperdriveContext(c).ConfigPath = filepath.Join(unitUser.HomeDir, defaultConfigFolder)
+	}
+
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected. Please run `hyperdrive service config` to set up Hyperdrive before installing the systemd unit")
+	}
+
+	// Get the settings for the unit
+	cliPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error getting the path of the Hyperdrive CLI: %w", err)
+	}
+	cliPath, err = filepath.EvalSymlinks(cliPath)
+	if err != nil {
+		return fmt.Errorf("error resolving the path of the Hyperdrive CLI: %w", err)
+	}
+	unitName := cfg.GetSystemdUnitName()
+	unlockUnitName := cfg.GetSystemdUnlockUnitName()
+	askPassword := c.Bool(systemdAskPasswordFlag.Name)
+	opts := client.SystemdUnitOptions{
+		CliPath:       cliPath,
+		ConfigPath:    hd.Context.ConfigPath,
+		User:          unitUser,
+		ComposeFiles:  getComposeFiles(c),
+		HealthTimeout: c.Duration(systemdHealthTimeoutFlag.Name),
+	}
+	unit := cfg.CreateSystemdUnit(opts)
+	unlockUnit := cfg.CreateSystemdUnlockUnit(opts)
+
+	// Prompt for confirmation
+	fmt.Printf("The following unit will be installed to %s:\n\n", filepath.Join(client.SystemdUnitDir, unitName))
+	fmt.Printf("%s%s%s\n", terminal.ColorBlue, unit, terminal.ColorReset)
+	if askPassword {
+		fmt.Printf("The following unit will be installed to %s to ask for the node wallet password:\n\n", filepath.Join(client.SystemdUnitDir, unlockUnitName))
+		fmt.Printf("%s%s%s\n", terminal.ColorBlue, unlockUnit, terminal.ColorReset)
+	}
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("Hyperdrive will be started at boot as %s. Are you sure you want to continue?", unitUser.Username))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	err = hd.InstallSystemdUnit(unitName, unit)
+	if err != nil {
+		return err
+	}
+	if askPassword {
+		err = hd.InstallSystemdUnit(unlockUnitName, unlockUnit)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = removeSystemdUnlockUnit(hd, unlockUnitName)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	if askPassword {
+		fmt.Printf("%sInstalled and enabled %s and %s.%s\n", terminal.ColorGreen, unitName, unlockUnitName, terminal.ColorReset)
+	} else {
+		fmt.Printf("%sInstalled and enabled %s.%s\n", terminal.ColorGreen, unitName, terminal.ColorReset)
+	}
+	if cfg.Cli.IsRootless() {
+		fmt.Printf("%sNOTE: Your container runtime runs as %s, so it must start at boot without a login session. If you haven't already, run `sudo loginctl enable-linger %s`.%s\n", terminal.ColorYellow, unitUser.Username, unitUser.Username, terminal.ColorReset)
+	}
+	if askPassword {
+		fmt.Printf("If your node wallet password isn't saved, you'll be prompted for it on the console once the daemon has started. Boot doesn't wait for the prompt; to answer it over SSH, run `sudo systemd-tty-ask-password-agent`, or check on it with `systemctl status %s`.\n", unlockUnitName)
+	}
+	fmt.Printf("You can check on it with `systemctl status %s` and follow its output with `journalctl -u %s`.\n", unitName, unitName)
+	return nil
+}
+
+// Disable and remove the systemd unit
+func uninstallSystemdUnit(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	unitName := cfg.GetSystemdUnitName()
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("Hyperdrive will no longer be started at boot by %s. The service itself won't be stopped. Are you sure you want to continue?", unitName))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	err = removeSystemdUnlockUnit(hd, cfg.GetSystemdUnlockUnitName())
+	if err != nil {
+		return err
+	}
+	err = hd.UninstallSystemdUnit(unitName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s.\n", unitName)
+	return nil
+}
+
+// Remove the unit that asks for the node wallet password at boot, if it's installed
+func removeSystemdUnlockUnit(hd *client.HyperdriveClient, unlockUnitName string) error {
+	_, err := os.Stat(filepath.Join(client.SystemdUnitDir, unlockUnitName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	err = hd.UninstallSystemdUnit(unlockUnitName)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s.\n", unlockUnitName)
+	return nil
+}
+
+// Start the service and check that it came up; run by the systemd unit at boot
+func runSystemdStart(c *cli.Context) error {
+	hd := client.NewHyperdriveClientFromCtx(c)
+	timeout := c.Duration(systemdHealthTimeoutFlag.Name)
+
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return fmt.Errorf("no configuration detected, not starting Hyperdrive")
+	}
+	errors := cfg.Validate()
+	if len(errors) > 0 {
+		return fmt.Errorf("your configuration has errors, run `hyperdrive service config` to correct them:\n%s", strings.Join(errors, "\n"))
+	}
+
+	// Respect the slashing prevention delay since nobody is around to confirm it.
+	// The unit has already counted as started, so waiting here doesn't hold up boot.
+	_, remainingTime, err := checkForValidatorChange(hd, cfg)
+	if err != nil {
+		return fmt.Errorf("error verifying the Validator Clients can be safely started: %w", err)
+	}
+	if remainingTime > 0 {
+		notifySlashingDelayStarted(hd, remainingTime)
+		fmt.Printf("Waiting %s for the slashing prevention delay to elapse.\n", remainingTime.Round(time.Second))
+		time.Sleep(remainingTime)
+		notifySlashingDelayEnded(hd)
+	}
+
+	// Start the service
+	fmt.Println("Starting Hyperdrive.")
+	err = hd.StartService(getComposeFiles(c))
+	if err != nil {
+		return fmt.Errorf("error starting service: %w", err)
+	}
+	recordValidatorsStarted(hd, cfg)
+	notifyServiceStarted(hd)
+
+	// Wait for the daemon to respond
+	startTime := time.Now()
+	for {
+		isReady, _, description := checkDaemonReady(hd)
+		if isReady {
+			fmt.Println(description)
+			break
+		}
+		if timeout > 0 && time.Since(startTime)+waitPollInterval > timeout {
+			return fmt.Errorf("timed out after %s waiting for the daemon (%s)", timeout, description)
+		}
+		time.Sleep(waitPollInterval)
+	}
+
+	// Report on the clients and wallet; these can take a while so they don't fail the unit
+	_, _, ecDescription := checkClientSynced(hd, true)
+	fmt.Printf("Execution Client: %s\n", ecDescription)
+	_, _, bnDescription := checkClientSynced(hd, false)
+	fmt.Printf("Beacon Node: %s\n", bnDescription)
+	_, _, walletDescription := checkWalletLoaded(hd)
+	fmt.Printf("Node wallet: %s\n", walletDescription)
+	return nil
+}
+
+// Prompt for the node wallet password through systemd's password agents and load the wallet with it; run by the systemd unit at boot
+func unlockWalletAtBoot(c *cli.Context) error {
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	// The service unit starts the daemon in the background after any slashing prevention delay, so allow for that on top of the timeout
+	timeout := c.Duration(systemdHealthTimeoutFlag.Name)
+	if timeout > 0 {
+		_, remainingTime, err := checkForValidatorChange(hd, cfg)
+		if err == nil {
+			timeout += remainingTime
+		}
+	}
+	startTime := time.Now()
+	isReady, _, description := checkDaemonReady(hd)
+	if !isReady {
+		fmt.Printf("Waiting for the daemon to start before asking for the node wallet password (%s).\n", description)
+		for !isReady {
+			if timeout > 0 && time.Since(startTime)+waitPollInterval > timeout {
+				return fmt.Errorf("timed out after %s waiting for the daemon (%s)", timeout.Round(time.Second), description)
+			}
+			time.Sleep(waitPollInterval)
+			isReady, _, description = checkDaemonReady(hd)
+		}
+	}
+
+	// Check if the password is needed
+	response, err := hd.Api.Wallet.Status()
+	if err != nil {
+		return fmt.Errorf("error getting wallet status: %w", err)
+	}
+	status := response.Data.WalletStatus
+	if status.Wallet.IsLoaded {
+		fmt.Printf("The node wallet %s is already loaded.\n", status.Wallet.WalletAddress.Hex())
+		return nil
+	}
+	if !status.Wallet.IsOnDisk {
+		fmt.Println("There's no node wallet yet, so there's nothing to unlock.")
+		return nil
+	}
+
+	// Ask for it, allowing for typos
+	projectName := cfg.Hyperdrive.ProjectName.Value
+	for attempt := 1; attempt <= systemdUnlockAttempts; attempt++ {
+		passwordString, err := client.AskSystemdPassword(fmt.Sprintf("hyperdrive:%s", projectName), fmt.Sprintf("Hyperdrive (%s) node wallet password:", projectName))
+		if err != nil {
+			return err
+		}
+		password, err := input.ValidateNodePassword("password", passwordString)
+		if err != nil {
+			fmt.Printf("Invalid password: %s\n", err.Error())
+			continue
+		}
+
+		_, err = hd.Api.Wallet.SetPassword(password, false)
+		if err != nil {
+			fmt.Printf("Error setting password: %s\n", err.Error())
+			continue
+		}
+		response, err = hd.Api.Wallet.Status()
+		if err != nil {
+			return fmt.Errorf("error getting wallet status: %w", err)
+		}
+		status = response.Data.WalletStatus
+		if status.Wallet.IsLoaded {
+			fmt.Printf("The node wallet %s is now loaded.\n", status.Wallet.WalletAddress.Hex())
+			return nil
+		}
+		fmt.Println("The password was set but the node wallet couldn't be loaded with it.")
+	}
+
+	// The service is running either way, so don't fail the unit
+	fmt.Printf("The node wallet couldn't be loaded after %d attempts. Run `hyperdrive wallet set-password` to load it.\n", systemdUnlockAttempts)
+	return nil
+}
@@ -9,17 +9,100 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+var (
+	terminateKeepChainDataFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "keep-chain-data",
+		Usage: "Keep the Execution Client and Beacon Node chain data volumes, so a new installation with the same project name doesn't have to resync",
+	}
+	terminateKeepKeysFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "keep-keys",
+		Usage: "Keep the user data folder with your node wallet, validator keys, and slashing protection databases",
+	}
+	terminateContainersOnlyFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "containers-only",
+		Usage: "Only remove the Hyperdrive containers and networks, keeping all of the volumes and the Hyperdrive directory",
+	}
+)
+
 // Terminate the Hyperdrive service
 func terminateService(c *cli.Context) error {
+	// Get Hyperdrive client
+	hd := client.NewHyperdriveClientFromCtx(c)
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	// Work out what will be removed
+	opts := client.TerminateOptions{
+		KeepChainData:  c.Bool(terminateKeepChainDataFlag.Name),
+		KeepKeys:       c.Bool(terminateKeepKeysFlag.Name),
+		ContainersOnly: c.Bool(terminateContainersOnlyFlag.Name),
+	}
+	plan, err := hd.GetTerminatePlan(hd.Context.ConfigPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.ContainersOnly {
+		fmt.Printf("%sWARNING: This will remove the Hyperdrive containers and networks. Your validators will stop attesting until you start Hyperdrive again.%s\n\n", terminal.ColorRed, terminal.ColorReset)
+	} else {
+		fmt.Printf("%sWARNING: This will terminate the Hyperdrive service. Any staking minipools will be penalized", terminal.ColorRed)
+		if !opts.KeepChainData {
+			fmt.Print(", your Execution client and Beacon node chain databases will be deleted, you will lose ALL of your sync progress")
+		}
+		fmt.Printf(", and you will lose your Prometheus metrics database!\nAfter doing this, you will have to **reinstall** Hyperdrive uses `hyperdrive service install -d` in order to use it again.%s\n\n", terminal.ColorReset)
+	}
+
 	// Prompt for confirmation
-	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("%sWARNING: Are you sure you want to terminate the Hyperdrive service? Any staking minipools will be penalized, your Execution client and Beacon node chain databases will be deleted, you will lose ALL of your sync progress, and you will lose your Prometheus metrics database!\nAfter doing this, you will have to **reinstall** Hyperdrive uses `hyperdrive service install -d` in order to use it again.%s", terminal.ColorRed, terminal.ColorReset))) {
+	op := &utils.DestructiveOperation{
+		Command:          "service terminate",
+		ConfirmationText: cfg.Hyperdrive.ProjectName.Value,
+		ConfirmationName: "project name",
+		Volumes:          plan.DeletedVolumes,
+		Paths:            plan.DeletedPaths,
+		OfferBackup:      !(opts.KeepKeys || opts.ContainersOnly),
+	}
+	proceed, err := utils.ConfirmDestructiveOperation(c, hd, op)
+	if err != nil {
+		return err
+	}
+	if !proceed {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
-	// Get Hyperdrive client
-	hd := client.NewHyperdriveClientFromCtx(c)
-
 	// Stop service
-	return hd.TerminateService(getComposeFiles(c), hd.Context.ConfigPath)
+	err = hd.TerminateService(getComposeFiles(c), plan)
+	utils.RecordDestructiveOperation(op, err)
+	if err != nil {
+		return err
+	}
+	printTerminateRemains(hd, cfg, plan)
+	return nil
+}
+
+// Print what was left on disk after terminating the service and how to use it again
+func printTerminateRemains(hd *client.HyperdriveClient, cfg *client.GlobalConfig, plan *client.TerminatePlan) {
+	if len(plan.KeptVolumes) == 0 && len(plan.KeptPaths) == 0 {
+		fmt.Println("Nothing from Hyperdrive remains on disk.")
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("The following remains on disk:")
+	utils.PrintDataSizes(hd, plan.KeptVolumes, plan.KeptPaths)
+	fmt.Println()
+
+	if plan.Options.ContainersOnly {
+		fmt.Println("Run `hyperdrive service start` to recreate the containers and pick up where you left off.")
+		return
+	}
+	if len(plan.KeptVolumes) > 0 {
+		fmt.Printf("A new installation with the project name `%s` will reuse the chain data volumes instead of resyncing. Remove them with `docker volume rm` if you no longer need them.\n", cfg.Hyperdrive.ProjectName.Value)
+	}
+	if len(plan.KeptPaths) > 0 {
+		fmt.Printf("Your node wallet, validator keys, and slashing protection databases are still in %s. A new installation will load them if its User Data Path points there, so move the folder into place before starting it if you change that setting.\n", cfg.Hyperdrive.UserDataPath.Value)
+		fmt.Printf("%sDon't run these keys on another machine while this one could still be validating with them, or you could be slashed!%s\n", terminal.ColorYellow, terminal.ColorReset)
+	}
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
@@ -13,8 +14,9 @@ const (
 	clientDataVolumeName string = "/ethclient"
 	dataFolderVolumeName string = "/.hyperdrive/data"
 
-	PruneFreeSpaceRequired uint64 = 50 * 1024 * 1024 * 1024
-	dockerImageRegex       string = ".*/(?P<image>.*):.*"
+	PruneFreeSpaceRequired uint64        = 50 * 1024 * 1024 * 1024
+	dockerImageRegex       string        = ".*/(?P<image>.*):.*"
+	slashingDelay          time.Duration = 15 * time.Minute
 )
 
 // Get the compose file paths for a CLI context
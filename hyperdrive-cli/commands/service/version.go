@@ -2,10 +2,13 @@ package service
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
 	"github.com/rocket-pool/node-manager-core/config"
+	"github.com/rocket-pool/node-manager-core/utils/sys"
 	"github.com/urfave/cli/v2"
 )
 
@@ -111,10 +114,40 @@ func serviceVersion(c *cli.Context) error {
 		return fmt.Errorf("unknown client mode [%v]", clientMode)
 	}
 
+	// Get the MEV-Boost string
+	mevBoostString := "Disabled"
+	mevBoost := cfg.Cli.MevBoost
+	if mevBoost.Enable.Value {
+		if mevBoost.Mode.Value == config.ClientMode_External {
+			mevBoostString = fmt.Sprintf("Externally managed (%s)", mevBoost.ExternalUrl.Value)
+		} else {
+			mevBoostString = fmt.Sprintf("Locally managed\n\tImage: %s", mevBoost.ContainerTag.Value)
+		}
+	}
+
 	// Print version info
 	fmt.Printf("Hyperdrive client version: %s\n", c.App.Version)
 	fmt.Printf("Hyperdrive daemon version: %s\n", serviceVersion)
 	fmt.Printf("Selected Execution Client: %s\n", executionClientString)
 	fmt.Printf("Selected Beacon Node: %s\n", beaconNodeString)
+	fmt.Printf("MEV-Boost: %s\n", mevBoostString)
+
+	// Print the image variant selection
+	missingFeatures := sys.GetMissingModernCpuFeatures()
+	if len(missingFeatures) > 0 {
+		fmt.Printf("Image variant: portable (CPU is missing %s)\n", strings.Join(missingFeatures, ", "))
+	} else {
+		fmt.Println("Image variant: modern")
+	}
+	record, err := hd.LoadPortableImageRecord()
+	if err != nil {
+		return err
+	}
+	if len(record.Changes) > 0 {
+		fmt.Println("Automatic portable image selections:")
+		for _, change := range record.Changes {
+			fmt.Printf("\t%s: %s => %s (%s)\n", change.Section, change.OldTag, change.NewTag, change.Time.Format(time.RFC822))
+		}
+	}
 	return nil
 }
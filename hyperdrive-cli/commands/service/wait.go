@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/rocket-pool/node-manager-core/beacon"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	waitPollInterval     time.Duration = 5 * time.Second
+	waitProgressInterval time.Duration = 30 * time.Second
+
+	waitForEcSynced         string  = "ec-synced"
+	waitForBnSynced         string  = "bn-synced"
+	waitForDaemonReady      string  = "daemon-ready"
+	waitForWalletLoaded     string  = "wallet-loaded"
+	waitForValidatorsActive string  = "validators-active"
+	waitUnknownProgress     float64 = -1
+)
+
+var (
+	waitConditions []string = []string{
+		waitForEcSynced,
+		waitForBnSynced,
+		waitForDaemonReady,
+		waitForWalletLoaded,
+		waitForValidatorsActive,
+	}
+
+	waitForFlag *cli.StringFlag = &cli.StringFlag{
+		Name:     "for",
+		Aliases:  []string{"f"},
+		Usage:    fmt.Sprintf("The condition to wait for. Options: %s", strings.Join(waitConditions, ", ")),
+		Required: true,
+	}
+	waitTimeoutFlag *cli.DurationFlag = &cli.DurationFlag{
+		Name:    "timeout",
+		Aliases: []string{"t"},
+		Usage:   "The maximum amount of time to wait (e.g. 30m, 2h) before exiting with an error. Set to 0 to wait indefinitely.",
+	}
+)
+
+// Checks a wait condition, returning whether it's met, the progress towards it (from 0 to 1, or waitUnknownProgress),
+// a description of the current state, and an error if the condition can never be met
+type waitCondition func() (bool, float64, string, error)
+
+// Wait for a condition to be met so scripts don't need to poll and parse command output
+func waitForCondition(c *cli.Context) error {
+	conditionName := c.String(waitForFlag.Name)
+	timeout := c.Duration(waitTimeoutFlag.Name)
+
+	// Get the condition
+	hd := client.NewHyperdriveClientFromCtx(c)
+	var condition waitCondition
+	switch conditionName {
+	case waitForEcSynced:
+		condition = func() (bool, float64, string, error) {
+			isReady, progress, description := checkClientSynced(hd, true)
+			return isReady, progress, description, nil
+		}
+	case waitForBnSynced:
+		condition = func() (bool, float64, string, error) {
+			isReady, progress, description := checkClientSynced(hd, false)
+			return isReady, progress, description, nil
+		}
+	case waitForDaemonReady:
+		condition = func() (bool, float64, string, error) {
+			isReady, progress, description := checkDaemonReady(hd)
+			return isReady, progress, description, nil
+		}
+	case waitForWalletLoaded:
+		condition = func() (bool, float64, string, error) {
+			isReady, progress, description := checkWalletLoaded(hd)
+			return isReady, progress, description, nil
+		}
+	case waitForValidatorsActive:
+		sw := client.NewStakewiseClientFromCtx(c)
+		condition = func() (bool, float64, string, error) {
+			return checkValidatorsActive(sw)
+		}
+	default:
+		return fmt.Errorf("invalid condition '%s'; options are %s", conditionName, strings.Join(waitConditions, ", "))
+	}
+
+	// Poll until the condition is met or the timeout elapses
+	startTime := time.Now()
+	lastReportTime := time.Time{}
+	history := &client.SyncHistory{
+		Clients: map[string][]client.SyncSample{},
+	}
+	for {
+		isReady, progress, description, err := condition()
+		if err != nil {
+			return fmt.Errorf("can't wait for %s: %w", conditionName, err)
+		}
+		if isReady {
+			fmt.Printf("%s: %s (waited %s)\n", conditionName, description, time.Since(startTime).Round(time.Second))
+			return nil
+		}
+		if progress != waitUnknownProgress {
+			history.AddSample(conditionName, progress, time.Now())
+		}
+
+		// Print progress periodically
+		if time.Since(lastReportTime) >= waitProgressInterval {
+			message := fmt.Sprintf("Waiting for %s: %s", conditionName, description)
+			if eta, hasEta := estimateSyncEta(history.Clients[conditionName]); hasEta {
+				message += fmt.Sprintf(", estimated time remaining %s", eta.Round(time.Second))
+			}
+			fmt.Println(message)
+			lastReportTime = time.Now()
+		}
+
+		// Check the timeout
+		if timeout > 0 && time.Since(startTime)+waitPollInterval > timeout {
+			return fmt.Errorf("timed out after %s waiting for %s (%s)", timeout, conditionName, description)
+		}
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// Check if the primary Execution Client or Beacon Node is synced
+func checkClientSynced(hd *client.HyperdriveClient, isEc bool) (bool, float64, string) {
+	response, err := hd.Api.Service.ClientStatus()
+	if err != nil {
+		return false, waitUnknownProgress, fmt.Sprintf("error getting client status: %s", err.Error())
+	}
+	status := response.Data.BcManagerStatus.PrimaryClientStatus
+	if isEc {
+		status = response.Data.EcManagerStatus.PrimaryClientStatus
+	}
+
+	if status.Error != "" {
+		return false, waitUnknownProgress, fmt.Sprintf("client unavailable (%s)", status.Error)
+	}
+	if status.IsSynced {
+		return true, 1, "synced"
+	}
+	if isEc && status.SyncProgress == 0 {
+		// Some ECs don't report sync progress
+		return false, waitUnknownProgress, "syncing (progress not reported)"
+	}
+	if isEc {
+		// This only covers the block download, and ECs often have more stages after it, so it can't be used to estimate the time remaining
+		return false, waitUnknownProgress, fmt.Sprintf("syncing (block download stage: %0.2f%%)", client.SyncRatioToPercent(status.SyncProgress))
+	}
+	return false, status.SyncProgress, fmt.Sprintf("syncing (%0.2f%%)", client.SyncRatioToPercent(status.SyncProgress))
+}
+
+// Check if the daemon is responding to API requests
+func checkDaemonReady(hd *client.HyperdriveClient) (bool, float64, string) {
+	response, err := hd.Api.Service.Version()
+	if err != nil {
+		return false, waitUnknownProgress, fmt.Sprintf("daemon not responding (%s)", err.Error())
+	}
+	return true, 1, fmt.Sprintf("daemon v%s is ready", response.Data.Version)
+}
+
+// Check if the node wallet is loaded
+func checkWalletLoaded(hd *client.HyperdriveClient) (bool, float64, string) {
+	response, err := hd.Api.Wallet.Status()
+	if err != nil {
+		return false, waitUnknownProgress, fmt.Sprintf("error getting wallet status: %s", err.Error())
+	}
+	status := response.Data.WalletStatus
+	if status.Wallet.IsLoaded {
+		return true, 1, fmt.Sprintf("wallet %s is loaded", status.Wallet.WalletAddress.Hex())
+	}
+	if !status.Wallet.IsOnDisk {
+		return false, waitUnknownProgress, "no wallet has been created yet"
+	}
+	return false, waitUnknownProgress, "wallet is not loaded yet"
+}
+
+// Check if all of the Stakewise validators are active on the Beacon Chain, including ones that are exiting or were slashed but haven't left yet.
+// Validators that have already exited can never become active again, so they're reported but not waited for.
+// Having no validators left to wait for can't be fixed by waiting, so that returns an error.
+func checkValidatorsActive(sw *client.StakewiseClient) (bool, float64, string, error) {
+	response, err := sw.Api.Status.GetValidatorStatuses()
+	if err != nil {
+		return false, waitUnknownProgress, fmt.Sprintf("error getting validator statuses: %s", err.Error()), nil
+	}
+	states := response.Data.States
+	if len(states) == 0 {
+		return false, waitUnknownProgress, "no validators found", fmt.Errorf("there are no validators to wait for; generate and upload your validator keys first")
+	}
+
+	active := 0
+	exited := 0
+	for _, state := range states {
+		switch state.BeaconStatus {
+		case beacon.ValidatorState_ActiveOngoing, beacon.ValidatorState_ActiveExiting, beacon.ValidatorState_ActiveSlashed:
+			active++
+		case beacon.ValidatorState_ExitedUnslashed, beacon.ValidatorState_ExitedSlashed, beacon.ValidatorState_WithdrawalPossible, beacon.ValidatorState_WithdrawalDone:
+			exited++
+		}
+	}
+	remaining := len(states) - exited
+	if remaining == 0 {
+		return false, waitUnknownProgress, fmt.Sprintf("all %d validators have exited", exited), fmt.Errorf("all of your validators have already exited, so none of them can become active")
+	}
+
+	progress := float64(active) / float64(remaining)
+	description := fmt.Sprintf("%d of %d validators active", active, remaining)
+	if exited > 0 {
+		description += fmt.Sprintf(" (%d exited, not counted)", exited)
+	}
+	return active == remaining, progress, description, nil
+}
@@ -7,10 +7,11 @@ import (
 )
 
 func uploadDepositData(c *cli.Context) error {
-	// Get the client
+	// Get the clients
+	hd := client.NewHyperdriveClientFromCtx(c)
 	sw := client.NewStakewiseClientFromCtx(c)
 
 	// Upload to the server
-	_, err := swcmdutils.UploadDepositData(sw)
+	_, err := swcmdutils.UploadDepositData(hd, sw)
 	return err
 }
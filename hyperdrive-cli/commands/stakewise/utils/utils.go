@@ -2,6 +2,7 @@ package swcmdutils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
@@ -17,7 +18,7 @@ func printUploadError(err error) {
 }
 
 // Upload deposit data to the server
-func UploadDepositData(sw *client.StakewiseClient) (bool, error) {
+func UploadDepositData(hd *client.HyperdriveClient, sw *client.StakewiseClient) (bool, error) {
 	fmt.Println("Uploading deposit data to the NodeSet server...")
 	response, err := sw.Api.Nodeset.UploadDepositData(false)
 	if err != nil {
@@ -58,5 +59,20 @@ func UploadDepositData(sw *client.StakewiseClient) (bool, error) {
 	fmt.Println()
 
 	fmt.Printf("Total keys registered: %s%d%s\n", terminal.ColorGreen, data.TotalCount, terminal.ColorReset)
+
+	pubkeys := make([]string, len(data.UnregisteredPubkeys))
+	for i, key := range data.UnregisteredPubkeys {
+		pubkeys[i] = key.HexWithPrefix()
+	}
+	utils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_DepositDataUploaded,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Deposit data uploaded",
+		Message: fmt.Sprintf("Uploaded the deposit data for %d new validator keys to NodeSet.", len(data.UnregisteredPubkeys)),
+		Details: map[string]string{
+			"New keys":   strings.Join(pubkeys, ", "),
+			"Total keys": fmt.Sprint(data.TotalCount),
+		},
+	})
 	return true, nil
 }
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
@@ -30,7 +31,8 @@ var (
 )
 
 func exit(c *cli.Context) error {
-	// Get the client
+	// Get the clients
+	hd := client.NewHyperdriveClientFromCtx(c)
 	sw := client.NewStakewiseClientFromCtx(c)
 
 	// Get all active validators
@@ -104,6 +106,20 @@ func exit(c *cli.Context) error {
 	// Log successand return if not broadcasting
 	if !noBroadcastBool {
 		fmt.Println("Successfully exited the selected validator(s). It will take some time before their status is reflected on the Beacon Chain.")
+		exited := make([]string, len(pubkeys))
+		for i, pubkey := range pubkeys {
+			exited[i] = pubkey.HexWithPrefix()
+		}
+		utils.Notify(hd, &client.Notification{
+			Event:   client.NotificationEvent_ExitsBroadcast,
+			Level:   client.NotificationLevel_Warning,
+			Title:   "Validator exits broadcast",
+			Message: fmt.Sprintf("Broadcast voluntary exits for %d validators. Keep them running until they've left the exit queue.", len(pubkeys)),
+			Details: map[string]string{
+				"Validators": strings.Join(exited, ", "),
+				"Epoch":      fmt.Sprint(response.Data.Epoch),
+			},
+		})
 		return nil
 	}
 
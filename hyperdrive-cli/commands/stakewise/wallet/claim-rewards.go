@@ -6,6 +6,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	cliutils "github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/tx"
 	"github.com/rocket-pool/node-manager-core/eth"
 	"github.com/urfave/cli/v2"
@@ -50,5 +51,15 @@ func claimRewards(c *cli.Context) error {
 	}
 
 	fmt.Println("Rewards successfully claimed.")
+	cliutils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_RewardsClaimed,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Rewards claimed",
+		Message: "Claimed your StakeWise rewards.",
+		Details: map[string]string{
+			"ETH":                 fmt.Sprintf("%.4f", eth.WeiToEth(resp.Data.WithdrawableEth)),
+			resp.Data.TokenSymbol: fmt.Sprintf("%.4f", eth.WeiToEth(resp.Data.WithdrawableToken)),
+		},
+	})
 	return nil
 }
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	swconfig "github.com/nodeset-org/hyperdrive-stakewise/shared/config"
@@ -64,6 +65,7 @@ func generateKeys(c *cli.Context) error {
 	// Generate the new keys
 	startTime := time.Now()
 	latestTime := startTime
+	pubkeys := make([]string, 0, count)
 	for i := uint64(0); i < count; i++ {
 		response, err := sw.Api.Wallet.GenerateKeys(1, false)
 		if err != nil {
@@ -77,9 +79,19 @@ func generateKeys(c *cli.Context) error {
 		latestTime = time.Now()
 		pubkey := response.Data.Pubkeys[0]
 		fmt.Printf("Generated %s (%d/%d) in %s\n", pubkey.HexWithPrefix(), (i + 1), count, elapsed)
+		pubkeys = append(pubkeys, pubkey.HexWithPrefix())
 	}
 	fmt.Printf("Completed in %s.\n", time.Since(startTime))
 	fmt.Println()
+	cliutils.Notify(hd, &client.Notification{
+		Event:   client.NotificationEvent_KeysGenerated,
+		Level:   client.NotificationLevel_Info,
+		Title:   "Validator keys generated",
+		Message: fmt.Sprintf("Generated %d new validator keys for StakeWise.", count),
+		Details: map[string]string{
+			"Keys": strings.Join(pubkeys, ", "),
+		},
+	})
 
 	// Restart the Stakewise Operator
 	if noRestart {
@@ -114,7 +126,7 @@ func generateKeys(c *cli.Context) error {
 	fmt.Println()
 
 	// Upload to the server
-	newKeysUploaded, err := swcmdutils.UploadDepositData(sw)
+	newKeysUploaded, err := swcmdutils.UploadDepositData(hd, sw)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,34 @@
+package update
+
+import (
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// Register commands
+func RegisterCommands(app *cli.App, name string, aliases []string) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:    name,
+		Aliases: aliases,
+		Usage:   "Update the Hyperdrive CLI and service to the latest release",
+		Flags: []cli.Flag{
+			utils.YesFlag,
+			updateFeedFlag,
+			updateForceFlag,
+			updateAllowDowngradeFlag,
+			updateRollbackFlag,
+		},
+		Action: func(c *cli.Context) error {
+			// Validate args
+			if err := utils.ValidateArgCount(c, 0); err != nil {
+				return err
+			}
+
+			// Run
+			if c.Bool(updateRollbackFlag.Name) {
+				return rollbackCli(c)
+			}
+			return updateCli(c)
+		},
+	})
+}
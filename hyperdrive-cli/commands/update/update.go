@@ -0,0 +1,200 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/blang/semver/v4"
+	"github.com/nodeset-org/hyperdrive-daemon/shared"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	updateFeedFlag *cli.StringFlag = &cli.StringFlag{
+		Name:    "feed",
+		Usage:   "The URL of the release feed to check for new versions. It must follow the format of GitHub's \"latest release\" API.",
+		Value:   client.DefaultReleaseFeedUrl,
+		EnvVars: []string{"HYPERDRIVE_RELEASE_FEED"},
+	}
+	updateForceFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:    "force",
+		Aliases: []string{"f"},
+		Usage:   "Reinstall the latest release even if this CLI is already up to date",
+	}
+	updateAllowDowngradeFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "allow-downgrade",
+		Usage: "Install the latest release from the feed even if it's older than this CLI. Only use this if you trust the feed.",
+	}
+	updateRollbackFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "rollback",
+		Usage: "Restore the CLI binary that was replaced by the last update",
+	}
+)
+
+// Update the CLI binary and the Hyperdrive service to the latest release
+func updateCli(c *cli.Context) error {
+	// Get the latest release
+	feedUrl := c.String(updateFeedFlag.Name)
+	release, err := client.GetLatestRelease(feedUrl)
+	if err != nil {
+		return err
+	}
+	latestVersion, err := release.GetVersion()
+	if err != nil {
+		return err
+	}
+	currentVersion, err := semver.Parse(shared.HyperdriveVersion)
+	if err != nil {
+		return fmt.Errorf("error parsing current version [%s]: %w", shared.HyperdriveVersion, err)
+	}
+	fmt.Printf("Current version: v%s\n", currentVersion)
+	fmt.Printf("Latest version:  v%s\n\n", latestVersion)
+	if latestVersion.LT(currentVersion) && !c.Bool(updateAllowDowngradeFlag.Name) {
+		return fmt.Errorf("the latest release in the feed (v%s) is older than this CLI (v%s); refusing to downgrade unless you use --%s", latestVersion, currentVersion, updateAllowDowngradeFlag.Name)
+	}
+	if latestVersion.EQ(currentVersion) && !c.Bool(updateForceFlag.Name) {
+		fmt.Println("You're already running the latest version of Hyperdrive.")
+		return nil
+	}
+
+	// Find the artifacts for this machine
+	assetName := client.GetCliAssetName()
+	binaryAsset := release.GetAsset(assetName)
+	manifestAsset := release.GetAsset(client.ChecksumManifestName)
+	signatureAsset := release.GetAsset(client.ChecksumManifestName + client.SignatureExtension)
+	if binaryAsset == nil {
+		return fmt.Errorf("release %s doesn't have a CLI binary for this machine (%s)", release.TagName, assetName)
+	}
+	if manifestAsset == nil || signatureAsset == nil {
+		return fmt.Errorf("release %s doesn't have a signed checksum manifest, so it can't be verified", release.TagName)
+	}
+
+	// Make sure the signed manifest belongs to the release the feed advertised, so an old release can't be passed off as a new one
+	fmt.Printf("Downloading %s... ", client.ChecksumManifestName)
+	manifestData, err := client.DownloadReleaseArtifact(manifestAsset.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	signature, err := client.DownloadReleaseArtifact(signatureAsset.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	fmt.Println("done.")
+	manifest, err := client.ParseChecksumManifest(manifestData, signature)
+	if err != nil {
+		return err
+	}
+	err = manifest.VerifyVersion(release.TagName)
+	if err != nil {
+		return fmt.Errorf("error verifying release %s: %w", release.TagName, err)
+	}
+
+	// Get the path of the running binary
+	binaryPath, err := getCliPath()
+	if err != nil {
+		return err
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("The Hyperdrive CLI at %s will be updated to %s, and then the Hyperdrive service will be updated to match. Are you sure you want to continue?", binaryPath, release.TagName))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Download and verify the new binary
+	fmt.Printf("Downloading %s... ", assetName)
+	binary, err := client.DownloadReleaseArtifact(binaryAsset.DownloadUrl)
+	if err != nil {
+		return err
+	}
+	fmt.Println("done.")
+
+	err = manifest.VerifyArtifact(assetName, binary)
+	if err != nil {
+		return fmt.Errorf("error verifying %s: %w", assetName, err)
+	}
+	fmt.Printf("%sVerified %s against the signed checksum manifest for %s.%s\n", terminal.ColorGreen, assetName, release.TagName, terminal.ColorReset)
+
+	// Download and verify the installer from the same release, so nothing is changed until every artifact has been verified
+	installer, err := client.DownloadInstallerFromRelease(release, manifest)
+	if err != nil {
+		return err
+	}
+
+	// Replace the binary
+	hd := client.NewHyperdriveClientFromCtx(c)
+	backupPath, err := hd.ReplaceCliBinary(binaryPath, binary)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated the CLI to %s.\n\n", release.TagName)
+
+	// Update the service, putting the previous CLI back if it fails so the two stay on the same version
+	fmt.Println("Updating the Hyperdrive service...")
+	err = hd.RunInstaller(false, true, "", installer)
+	if err != nil {
+		restoreErr := hd.RestoreCliBinary(binaryPath)
+		if restoreErr != nil {
+			return fmt.Errorf("the service update failed: %w\nThe previous CLI couldn't be restored either (%s); it's still saved at %s", err, restoreErr.Error(), backupPath)
+		}
+		return fmt.Errorf("the service update failed, so the previous CLI was restored: %w", err)
+	}
+	fmt.Printf("The previous CLI was saved to %s; you can restore it with `hyperdrive update --rollback`.\n", backupPath)
+
+	fmt.Println()
+	fmt.Printf("%sHyperdrive was successfully updated to %s!%s\n", terminal.ColorGreen, release.TagName, terminal.ColorReset)
+	fmt.Printf("%s\n=== Next Steps ===\n", terminal.ColorBlue)
+	fmt.Printf("Run 'hyperdrive service config' to review the settings changes for this update, then 'hyperdrive service start' to apply them.%s\n", terminal.ColorReset)
+	return nil
+}
+
+// Restore the CLI binary that was replaced by the last update
+func rollbackCli(c *cli.Context) error {
+	binaryPath, err := getCliPath()
+	if err != nil {
+		return err
+	}
+	backupPath := binaryPath + client.CliBackupSuffix
+	binary, err := os.ReadFile(backupPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("there's no previous CLI binary at %s to restore", backupPath)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading previous CLI binary [%s]: %w", backupPath, err)
+	}
+
+	// Prompt for confirmation
+	if !(c.Bool(utils.YesFlag.Name) || utils.Confirm(fmt.Sprintf("The Hyperdrive CLI at %s will be replaced with the previous version from %s. Are you sure you want to continue?", binaryPath, backupPath))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Swap the binaries
+	hd := client.NewHyperdriveClientFromCtx(c)
+	_, err = hd.ReplaceCliBinary(binaryPath, binary)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored the previous CLI binary. The version it replaced was saved to %s.\n", backupPath)
+	fmt.Println("Run `hyperdrive service install -d` to change the Hyperdrive service to the same version as the restored CLI.")
+	return nil
+}
+
+// Get the resolved path of the running CLI binary
+func getCliPath() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("error getting the path of the CLI binary: %w", err)
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("error resolving the path of the CLI binary: %w", err)
+	}
+	return path, nil
+}
@@ -206,6 +206,11 @@ func RegisterCommands(app *cli.App, name string, aliases []string) {
 			{
 				Name:  "purge",
 				Usage: fmt.Sprintf("%sDeletes your node wallet, your validator keys, and restarts your Validator Client while preserving your chain data. WARNING: Only use this if you want to stop validating with this machine!%s", terminal.ColorRed, terminal.ColorReset),
+				Flags: []cli.Flag{
+					utils.YesFlag,
+					utils.ConfirmFlag,
+					utils.ValidatorsActiveFlag,
+				},
 				Action: func(c *cli.Context) error {
 					// Validate args
 					if err := utils.ValidateArgCount(c, 0); err != nil {
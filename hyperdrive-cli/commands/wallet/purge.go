@@ -3,6 +3,8 @@ package wallet
 import (
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mitchellh/go-homedir"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
@@ -13,14 +15,47 @@ func purge(c *cli.Context) error {
 	// Get Hyperdrive client
 	hd := client.NewHyperdriveClientFromCtx(c)
 
-	if !utils.Confirm(fmt.Sprintf("%sWARNING: This will delete your node wallet, all of your validator keys (including externally-generated ones in the 'custom-keys' folder), and restart your Docker containers.\nYou will NO LONGER be able to attest with this machine anymore until you recover your wallet or initialize a new one.\n\nYou MUST have your node wallet's mnemonic recorded before running this, or you will lose access to your node wallet and your validators forever!\n\n%sDo you want to continue?", terminal.ColorRed, terminal.ColorReset)) {
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading user settings: %w", err)
+	}
+
+	fmt.Printf("%sWARNING: This will delete your node wallet, all of your validator keys (including externally-generated ones in the 'custom-keys' folder), and restart your Docker containers.\nYou will NO LONGER be able to attest with this machine anymore until you recover your wallet or initialize a new one.\n\nYou MUST have your node wallet's mnemonic recorded before running this, or you will lose access to your node wallet and your validators forever!%s\n\n", terminal.ColorRed, terminal.ColorReset)
+
+	// Confirm with the node address, or the project name if there isn't a wallet
+	op := &utils.DestructiveOperation{
+		Command:          "wallet purge",
+		ConfirmationText: cfg.Hyperdrive.ProjectName.Value,
+		ConfirmationName: "project name",
+		OfferBackup:      true,
+	}
+	address, err := hd.GetNodeWalletAddressOnDisk()
+	if err != nil {
+		return err
+	}
+	if address != (common.Address{}) {
+		op.ConfirmationText = address.Hex()
+		op.ConfirmationName = "node address"
+	}
+	dataPath, err := homedir.Expand(cfg.Hyperdrive.UserDataPath.Value)
+	if err != nil {
+		return fmt.Errorf("error loading data path: %w", err)
+	}
+	op.Paths = []string{dataPath}
+
+	proceed, err := utils.ConfirmDestructiveOperation(c, hd, op)
+	if err != nil {
+		return err
+	}
+	if !proceed {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
 	// Purge
 	composeFiles := c.StringSlice(utils.ComposeFileFlag.Name)
-	err := hd.PurgeData(composeFiles)
+	err = hd.PurgeData(composeFiles)
+	utils.RecordDestructiveOperation(op, err)
 	if err != nil {
 		return fmt.Errorf("%w\n%sTHERE WAS AN ERROR DELETING YOUR KEYS. They most likely have not been deleted. Proceed with caution.%s", err, terminal.ColorRed, terminal.ColorReset)
 	}
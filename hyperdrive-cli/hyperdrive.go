@@ -11,6 +11,7 @@ import (
 	"github.com/nodeset-org/hyperdrive-daemon/shared"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/commands/service"
 	swcmd "github.com/nodeset-org/hyperdrive/hyperdrive-cli/commands/stakewise"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/commands/update"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/commands/wallet"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils"
 	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/context"
@@ -107,6 +108,7 @@ func main() {
 	service.RegisterCommands(app, "service", []string{"s"})
 	swcmd.RegisterCommands(app, "stakewise", []string{"sw"})
 	wallet.RegisterCommands(app, "wallet", []string{"w"})
+	update.RegisterCommands(app, "update", []string{"u"})
 
 	app.Before = func(c *cli.Context) error {
 		// Check user ID
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+	"github.com/rocket-pool/node-manager-core/utils/input"
+	"github.com/urfave/cli/v2"
+)
+
+// An operation that permanently deletes some of the node's data
+type DestructiveOperation struct {
+	// The command being run, for the audit log
+	Command string
+
+	// What the user has to type to confirm the operation, such as the project name
+	ConfirmationText string
+
+	// A description of the confirmation text, such as "project name"
+	ConfirmationName string
+
+	// The Docker volumes that will be deleted
+	Volumes []string
+
+	// The directories that will be deleted
+	Paths []string
+
+	// True to offer a backup of the node wallet, validator keys, and slashing protection databases first
+	OfferBackup bool
+
+	projectName      string
+	validatorsActive bool
+	backupPath       string
+}
+
+// Show what a destructive operation will delete, make sure it's safe to run, offer a backup, and have the user confirm it by typing the confirmation text.
+// Returns false if the operation should not proceed.
+func ConfirmDestructiveOperation(c *cli.Context, hd *client.HyperdriveClient, op *DestructiveOperation) (bool, error) {
+	cfg, _, err := hd.LoadConfig()
+	if err != nil {
+		return false, fmt.Errorf("error loading user settings: %w", err)
+	}
+	op.projectName = cfg.Hyperdrive.ProjectName.Value
+
+	// List everything that's going to be removed
+	if len(op.Volumes) == 0 && len(op.Paths) == 0 {
+		fmt.Println("No volumes or files will be deleted.")
+	} else {
+		fmt.Printf("%sThe following will be permanently deleted:%s\n", terminal.ColorRed, terminal.ColorReset)
+		PrintDataSizes(hd, op.Volumes, op.Paths)
+	}
+	fmt.Println()
+
+	// Refuse to run while the validators are active
+	vcs, err := hd.GetRunningValidatorContainers(op.projectName)
+	if err != nil {
+		return false, fmt.Errorf("error checking for active validators: %w", err)
+	}
+	if len(vcs) > 0 {
+		if !c.Bool(ValidatorsActiveFlag.Name) {
+			fmt.Printf("%sYour Validator Client is running (%s), so your validators may be active. Stop it with `hyperdrive service stop` first, or run this again with --%s if you're sure.%s\n", terminal.ColorRed, strings.Join(vcs, ", "), ValidatorsActiveFlag.Name, terminal.ColorReset)
+			return false, nil
+		}
+		fmt.Printf("%sWARNING: Your Validator Client is running (%s). Continuing anyway since --%s was provided.%s\n\n", terminal.ColorYellow, strings.Join(vcs, ", "), ValidatorsActiveFlag.Name, terminal.ColorReset)
+		op.validatorsActive = true
+	}
+
+	// Have the user type the confirmation text
+	if c.Bool(YesFlag.Name) {
+		confirmation := c.String(ConfirmFlag.Name)
+		if confirmation == "" {
+			return false, fmt.Errorf("--%s is required when using --%s; set it to your %s (%s)", ConfirmFlag.Name, YesFlag.Name, op.ConfirmationName, op.ConfirmationText)
+		}
+		if !strings.EqualFold(confirmation, op.ConfirmationText) {
+			return false, fmt.Errorf("--%s does not match your %s (%s)", ConfirmFlag.Name, op.ConfirmationName, op.ConfirmationText)
+		}
+	} else {
+		confirmation := Prompt(fmt.Sprintf("%sThis cannot be undone! Type your %s (%s) to continue:%s", terminal.ColorRed, op.ConfirmationName, op.ConfirmationText, terminal.ColorReset), "^.*$", "")
+		if !strings.EqualFold(strings.TrimSpace(confirmation), op.ConfirmationText) {
+			fmt.Printf("That doesn't match your %s.\n", op.ConfirmationName)
+			return false, nil
+		}
+	}
+	fmt.Println()
+
+	// Offer a backup in case the keys are still needed
+	if op.OfferBackup && !c.Bool(YesFlag.Name) {
+		op.backupPath, err = OfferBackup(hd)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Record the result of a confirmed destructive operation in the audit log
+func RecordDestructiveOperation(op *DestructiveOperation, opErr error) {
+	entry := client.AuditEntry{
+		Time:             time.Now(),
+		Command:          op.Command,
+		ProjectName:      op.projectName,
+		ConfirmedWith:    op.ConfirmationName,
+		Volumes:          op.Volumes,
+		Paths:            op.Paths,
+		ValidatorsActive: op.validatorsActive,
+		Backup:           op.backupPath,
+	}
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+	err := client.WriteAuditEntry(entry)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't record this operation in the audit log: %s%s\n", terminal.ColorYellow, err.Error(), terminal.ColorReset)
+	}
+}
+
+// Prompt for a new passphrase to encrypt a backup with
+func PromptNewBackupPassphrase() string {
+	for {
+		passphrase := PromptPassword(
+			"Please enter a passphrase to encrypt the backup with. You'll need it to restore the backup, so keep it somewhere safe:",
+			fmt.Sprintf("^.{%d,}$", input.MinPasswordLength),
+			fmt.Sprintf("Your passphrase must be at least %d characters long. Please try again:", input.MinPasswordLength),
+		)
+		confirmation := PromptPassword("Please confirm your passphrase:", "^.*$", "")
+		if passphrase == confirmation {
+			return passphrase
+		}
+		fmt.Println("Passphrase confirmation does not match.")
+		fmt.Println("")
+	}
+}
+
+// Offer to back up the node before a destructive operation.
+// Returns the path of the backup, or an empty string if one wasn't made.
+func OfferBackup(hd *client.HyperdriveClient) (string, error) {
+	cfg, isNew, err := hd.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading user settings: %w", err)
+	}
+	if isNew {
+		return "", nil
+	}
+	if !Confirm("Would you like to make an encrypted backup of your node wallet, validator keys, slashing protection databases, and settings first?") {
+		return "", nil
+	}
+
+	// Save it to the home directory since the config folder may be about to be deleted
+	backupPath, err := homedir.Expand(filepath.Join("~", cfg.GetDefaultBackupFilename()))
+	if err != nil {
+		return "", fmt.Errorf("error expanding backup path: %w", err)
+	}
+	passphrase := PromptNewBackupPassphrase()
+	_, err = hd.CreateBackup(backupPath, passphrase, false)
+	if err != nil {
+		return "", fmt.Errorf("error creating backup: %w", err)
+	}
+	fmt.Printf("Saved the backup to %s. Restore it with `hyperdrive service restore %s`.\n\n", backupPath, backupPath)
+	return backupPath, nil
+}
+
+// Print the given volumes and paths along with their sizes
+func PrintDataSizes(hd *client.HyperdriveClient, volumes []string, paths []string) {
+	for _, volume := range volumes {
+		size, err := hd.GetVolumeSize(volume)
+		fmt.Printf("\tVolume %s (%s)\n", volume, FormatDataSize(size, err))
+	}
+	for _, path := range paths {
+		size, err := hd.GetDirectorySize(path)
+		fmt.Printf("\tPath   %s (%s)\n", path, FormatDataSize(size, err))
+	}
+}
+
+// Format a size in bytes for display, or a placeholder if it couldn't be determined
+func FormatDataSize(size int64, err error) string {
+	if err != nil {
+		return "unknown size"
+	}
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTP"[exp])
+}
@@ -33,6 +33,14 @@ var (
 		Aliases: []string{"f"},
 		Usage:   "Supplemental Docker compose files for custom containers to include when performing service commands such as 'start' and 'stop'; this flag may be defined multiple times",
 	}
+	ConfirmFlag *cli.StringFlag = &cli.StringFlag{
+		Name:  "confirm",
+		Usage: "The project name (or node address, where asked for) to confirm a destructive operation with. Required along with --yes, since it replaces typing it in.",
+	}
+	ValidatorsActiveFlag *cli.BoolFlag = &cli.BoolFlag{
+		Name:  "i-understand-validators-are-active",
+		Usage: "Allow a destructive operation to run while your Validator Client is running. Your validators may go offline or become impossible to exit!",
+	}
 )
 
 func InstantiateFlag[FlagType cli.Flag](prototype FlagType, description string) cli.Flag {
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/client"
+	"github.com/nodeset-org/hyperdrive/hyperdrive-cli/utils/terminal"
+)
+
+// Send an event notification to the configured webhooks.
+// Failures are printed as a warning, since a webhook being down shouldn't stop the command that triggered the event.
+func Notify(hd *client.HyperdriveClient, notification *client.Notification) {
+	_, err := hd.SendNotification(notification)
+	if err != nil {
+		fmt.Printf("%sWARNING: Couldn't send the \"%s\" notification: %s%s\n", terminal.ColorYellow, notification.Title, err.Error(), terminal.ColorReset)
+	}
+}
@@ -77,14 +77,18 @@ func HandleTx(c *cli.Context, hd *client.HyperdriveClient, txInfo *eth.Transacti
 	fmt.Println(submissionMessage)
 	response, err := hd.Api.Tx.SubmitTx(submission, nonce, maxFee, maxPrioFee)
 	if err != nil {
+		notifyTx(hd, client.NotificationEvent_TxFailed, identifier, common.Hash{}, err)
 		return false, fmt.Errorf("error submitting transaction: %w", err)
 	}
+	notifyTx(hd, client.NotificationEvent_TxSubmitted, identifier, response.Data.TxHash, nil)
 
 	// Wait for it
 	utils.PrintTransactionHash(hd, response.Data.TxHash)
 	if _, err = hd.Api.Tx.WaitForTransaction(response.Data.TxHash); err != nil {
+		notifyTx(hd, client.NotificationEvent_TxFailed, identifier, response.Data.TxHash, err)
 		return false, fmt.Errorf("error waiting for transaction: %w", err)
 	}
+	notifyTx(hd, client.NotificationEvent_TxMined, identifier, response.Data.TxHash, nil)
 
 	updateCustomNonce(hd)
 	return true, nil
@@ -169,8 +173,14 @@ func HandleTxBatch(c *cli.Context, hd *client.HyperdriveClient, txInfos []*eth.T
 	fmt.Println(submissionMessage)
 	response, err := hd.Api.Tx.SubmitTxBatch(submissions, nonce, maxFee, maxPrioFee)
 	if err != nil {
+		for i := range submissions {
+			notifyTx(hd, client.NotificationEvent_TxFailed, identifierFunc(i), common.Hash{}, err)
+		}
 		return false, fmt.Errorf("error submitting transactions: %w", err)
 	}
+	for i, hash := range response.Data.TxHashes {
+		notifyTx(hd, client.NotificationEvent_TxSubmitted, identifierFunc(i), hash, nil)
+	}
 
 	// Wait for them
 	utils.PrintTransactionBatchHashes(hd, response.Data.TxHashes)
@@ -190,12 +200,14 @@ func waitForTransactions(hd *client.HyperdriveClient, hashes []common.Hash, iden
 		hash := hash
 		wg.Go(func() error {
 			if _, err := hd.Api.Tx.WaitForTransaction(hash); err != nil {
+				notifyTx(hd, client.NotificationEvent_TxFailed, identifierFunc(i), hash, err)
 				return fmt.Errorf("error waiting for transaction %s: %w", hash.Hex(), err)
 			}
 			lock.Lock()
 			successCount++
 			fmt.Printf("TX %s (%s) complete (%d/%d)\n", hash.Hex(), identifierFunc(i), successCount, total)
 			lock.Unlock()
+			notifyTx(hd, client.NotificationEvent_TxMined, identifierFunc(i), hash, nil)
 			return nil
 		})
 	}
@@ -205,6 +217,31 @@ func waitForTransactions(hd *client.HyperdriveClient, hashes []common.Hash, iden
 	return nil
 }
 
+// Send a notification about a transaction's progress. The hash is left out if the transaction was never submitted.
+func notifyTx(hd *client.HyperdriveClient, event client.NotificationEvent, identifier string, hash common.Hash, txErr error) {
+	notification := &client.Notification{
+		Event:   event,
+		Level:   client.NotificationLevel_Info,
+		Details: map[string]string{},
+	}
+	switch event {
+	case client.NotificationEvent_TxSubmitted:
+		notification.Title = "Transaction submitted"
+		notification.Message = fmt.Sprintf("Submitted the transaction for %s.", identifier)
+	case client.NotificationEvent_TxMined:
+		notification.Title = "Transaction mined"
+		notification.Message = fmt.Sprintf("The transaction for %s was included in a block.", identifier)
+	default:
+		notification.Level = client.NotificationLevel_Warning
+		notification.Title = "Transaction failed"
+		notification.Message = fmt.Sprintf("The transaction for %s failed: %s", identifier, txErr.Error())
+	}
+	if hash != (common.Hash{}) {
+		notification.Details["Transaction"] = hash.Hex()
+	}
+	utils.Notify(hd, notification)
+}
+
 // If a custom nonce is set, increment it for the next transaction
 func updateCustomNonce(hd *client.HyperdriveClient) {
 	if hd.Context.Nonce.Cmp(common.Big0) > 0 {
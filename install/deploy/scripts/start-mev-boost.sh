@@ -0,0 +1,33 @@
+#!/bin/sh
+# This script launches MEV-Boost for Hyperdrive's docker stack; only edit if you know what you're doing ;)
+
+# Set up the network-based flags
+if [ "$NETWORK" = "mainnet" ]; then
+    MEV_NETWORK="mainnet"
+elif [ "$NETWORK" = "holesky-dev" ]; then
+    MEV_NETWORK="holesky"
+elif [ "$NETWORK" = "holesky" ]; then
+    MEV_NETWORK="holesky"
+else
+    echo "Unknown network [$NETWORK]"
+    exit 1
+fi
+
+# Make sure there's a relay to use
+if [ -z "$MEV_BOOST_RELAYS" ]; then
+    echo "No MEV relays are selected, please enable at least one in the MEV-Boost section of \`hyperdrive service config\`."
+    exit 1
+fi
+
+CMD="/app/mev-boost \
+    -$MEV_NETWORK \
+    -addr 0.0.0.0:$MEV_BOOST_PORT \
+    -relay-check \
+    -relays $MEV_BOOST_RELAYS \
+    $MEV_BOOST_ADDITIONAL_FLAGS"
+
+if [ "$ENABLE_METRICS" = "true" ]; then
+    CMD="$CMD -metrics -metrics-addr 0.0.0.0:$MEV_BOOST_METRICS_PORT"
+fi
+
+exec ${CMD}